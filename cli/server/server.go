@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"sort"
+	"text/tabwriter"
 
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core"
@@ -66,6 +68,14 @@ func NewCommands() []cli.Command {
 			Usage: "directory for storing JSON dumps",
 		},
 	)
+	var cfgHeightFlags = make([]cli.Flag, len(cfgFlags))
+	copy(cfgHeightFlags, cfgFlags)
+	cfgHeightFlags = append(cfgHeightFlags,
+		cli.UintFlag{
+			Name:  "height",
+			Usage: "block height to revert the chain to",
+		},
+	)
 	return []cli.Command{
 		{
 			Name:   "node",
@@ -89,6 +99,30 @@ func NewCommands() []cli.Command {
 					Action: restoreDB,
 					Flags:  cfgCountInFlags,
 				},
+				{
+					Name:   "stats",
+					Usage:  "show the number of keys and their size per key prefix",
+					Action: statsDB,
+					Flags:  cfgFlags,
+				},
+				{
+					Name:   "check",
+					Usage:  "check chain data consistency",
+					Action: checkDB,
+					Flags:  cfgFlags,
+				},
+				{
+					Name:   "compact",
+					Usage:  "compact the database (if supported by DB backend)",
+					Action: compactDB,
+					Flags:  cfgFlags,
+				},
+				{
+					Name:   "rollback",
+					Usage:  "revert the chain to the given height",
+					Action: rollbackDB,
+					Flags:  cfgHeightFlags,
+				},
 			},
 		},
 	}
@@ -323,6 +357,122 @@ func restoreDB(ctx *cli.Context) error {
 	return nil
 }
 
+func statsDB(ctx *cli.Context) error {
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	stats := storage.CollectStats(store)
+	prefixes := make([]storage.KeyPrefix, 0, len(stats))
+	for p := range stats {
+		prefixes = append(prefixes, p)
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return prefixes[i] < prefixes[j] })
+
+	var total storage.PrefixStats
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Prefix\tKeys\tKey bytes\tValue bytes\t")
+	for _, p := range prefixes {
+		ps := stats[p]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t\n", p, ps.Keys, ps.KeySize, ps.ValueSize)
+		total.Keys += ps.Keys
+		total.KeySize += ps.KeySize
+		total.ValueSize += ps.ValueSize
+	}
+	fmt.Fprintf(w, "Total\t%d\t%d\t%d\t\n", total.Keys, total.KeySize, total.ValueSize)
+	if err := w.Flush(); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	if r, ok := store.(storage.StatsReporter); ok {
+		bstats, err := r.Stats()
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("can't get DB stats: %v", err), 1)
+		}
+		fmt.Println()
+		fmt.Println(bstats)
+	}
+	return nil
+}
+
+func checkDB(ctx *cli.Context) error {
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	var problems int
+	onProblem := func(err error) {
+		problems++
+		fmt.Println(err)
+	}
+	progress := func(index uint32) {
+		if index%100000 == 0 {
+			fmt.Fprintf(os.Stderr, "checking block %d\n", index)
+		}
+	}
+	if err := core.CheckStore(store, onProblem, progress); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if problems != 0 {
+		return cli.NewExitError(fmt.Errorf("%d problems found", problems), 1)
+	}
+	fmt.Println("no problems found")
+	return nil
+}
+
+func compactDB(ctx *cli.Context) error {
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	c, ok := store.(storage.Compactor)
+	if !ok {
+		return cli.NewExitError("compaction is not supported by this DB type", 1)
+	}
+	if err := c.Compact(); err != nil {
+		return cli.NewExitError(fmt.Errorf("compaction failed: %v", err), 1)
+	}
+	return nil
+}
+
+func rollbackDB(ctx *cli.Context) error {
+	if !ctx.IsSet("height") {
+		return cli.NewExitError("height is required", 1)
+	}
+	height := uint32(ctx.Uint("height"))
+	store, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	if err := core.Rollback(store, height); err != nil {
+		return cli.NewExitError(fmt.Errorf("rollback failed: %v", err), 1)
+	}
+	fmt.Printf("chain reverted to block %d\n", height)
+	return nil
+}
+
+// openStore opens the storage configured for the node without initializing
+// the blockchain.
+func openStore(ctx *cli.Context) (storage.Store, error) {
+	cfg, err := getConfigFromContext(ctx)
+	if err != nil {
+		return nil, cli.NewExitError(err, 1)
+	}
+	store, err := storage.NewStore(cfg.ApplicationConfiguration.DBConfiguration)
+	if err != nil {
+		return nil, cli.NewExitError(fmt.Errorf("could not initialize storage: %s", err), 1)
+	}
+	return store, nil
+}
+
 // readBlock performs reading of block size and then bytes with the length equal to that size.
 func readBlock(reader *io.BinReader) ([]byte, error) {
 	var size = reader.ReadU32LE()
@@ -361,7 +511,12 @@ func startServer(ctx *cli.Context) error {
 	rpcServer := server.New(chain, cfg.ApplicationConfiguration.RPC, serv, log)
 	errChan := make(chan error)
 
-	go serv.Start(errChan)
+	// Secondary node doesn't process blocks, so it only serves RPC without
+	// P2P networking.
+	secondary := cfg.ApplicationConfiguration.DBConfiguration.ReadOnly
+	if !secondary {
+		go serv.Start(errChan)
+	}
 	go rpcServer.Start(errChan)
 
 	fmt.Println(logo())
@@ -377,7 +532,9 @@ Main:
 			cancel()
 
 		case <-grace.Done():
-			serv.Shutdown()
+			if !secondary {
+				serv.Shutdown()
+			}
 			if serverErr := rpcServer.Shutdown(); serverErr != nil {
 				shutdownErr = errors.Wrap(serverErr, "Error encountered whilst shutting down server")
 			}
@@ -420,7 +577,12 @@ func initBlockChain(cfg config.Config, log *zap.Logger) (*core.Blockchain, error
 		return nil, cli.NewExitError(fmt.Errorf("could not initialize storage: %s", err), 1)
 	}
 
-	chain, err := core.NewBlockchain(store, cfg.ProtocolConfiguration, log)
+	var chain *core.Blockchain
+	if cfg.ApplicationConfiguration.DBConfiguration.ReadOnly {
+		chain, err = core.NewSecondaryBlockchain(store, cfg.ProtocolConfiguration, log)
+	} else {
+		chain, err = core.NewBlockchain(store, cfg.ProtocolConfiguration, log)
+	}
 	if err != nil {
 		return nil, cli.NewExitError(fmt.Errorf("could not initialize blockchain: %s", err), 1)
 	}
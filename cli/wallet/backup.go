@@ -0,0 +1,168 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/cli/flags"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/urfave/cli"
+)
+
+func newBackupCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:  "split",
+			Usage: "split account key into Shamir's secret sharing shares",
+			UsageText: "split --path <path> --addr <addr> --threshold <k> --shares <n>\n\n" +
+				"   Prints n shares of the account private key, any k of them are\n" +
+				"   enough to restore it with `backup restore`, less than k reveal\n" +
+				"   nothing about it. Every share contains the address of the key.",
+			Action: splitKey,
+			Flags: []cli.Flag{
+				walletPathFlag,
+				flags.AddressFlag{
+					Name:  "addr",
+					Usage: "Address of the account to split the key of",
+				},
+				cli.IntFlag{
+					Name:  "threshold, k",
+					Usage: "Number of shares needed to restore the key",
+				},
+				cli.IntFlag{
+					Name:  "shares, n",
+					Usage: "Total number of shares",
+				},
+			},
+		},
+		{
+			Name:  "restore",
+			Usage: "restore account key from Shamir's secret sharing shares",
+			UsageText: "restore --path <path> [--name <name>] [<share>...]\n\n" +
+				"   Restores the key from shares (they're asked for if not given),\n" +
+				"   encrypts it with the new password and adds the account to the\n" +
+				"   wallet.",
+			Action: restoreKey,
+			Flags: []cli.Flag{
+				walletPathFlag,
+				cli.StringFlag{
+					Name:  "name",
+					Usage: "Optional account name",
+				},
+			},
+		},
+	}
+}
+
+func splitKey(ctx *cli.Context) error {
+	wall, err := openWallet(ctx.String("path"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+
+	addrFlag := ctx.Generic("addr").(*flags.Address)
+	if !addrFlag.IsSet {
+		return cli.NewExitError("address was not provided", 1)
+	}
+	acc := wall.GetAccount(addrFlag.Uint160())
+	if acc == nil {
+		return cli.NewExitError(fmt.Errorf("wallet contains no account for '%s'", addrFlag), 1)
+	}
+	if acc.IsWatchOnly() {
+		return cli.NewExitError(wallet.ErrWatchOnly, 1)
+	}
+
+	pass, err := readPassword("Enter password > ")
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if err := acc.Decrypt(pass); err != nil {
+		return cli.NewExitError(fmt.Errorf("can't unlock an account: %v", err), 1)
+	}
+
+	k, n := ctx.Int("threshold"), ctx.Int("shares")
+	shares, err := keys.SplitPrivateKey(acc.PrivateKey(), n, k)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	for _, s := range shares {
+		fmt.Printf("Share %d/%d (%d needed) of %s: %s\n", s.Index, n, k, s.Address(), s)
+	}
+	return nil
+}
+
+func restoreKey(ctx *cli.Context) error {
+	wall, err := openWallet(ctx.String("path"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+
+	shares, err := readKeyShares(ctx.Args())
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	priv, err := keys.CombineKeyShares(shares)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't restore the key: %v", err), 1)
+	}
+	acc, err := wallet.NewAccountFromWIF(priv.WIF())
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	fmt.Printf("Restored key of %s\n", acc.Address)
+
+	pass, err := readPassword("Enter new password > ")
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	passCheck, err := readPassword("Confirm new password > ")
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if pass != passCheck {
+		return cli.NewExitError(errPhraseMismatch, 1)
+	}
+	if err := acc.EncryptWithParams(pass, wall.Scrypt); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	acc.Label = ctx.String("name")
+	if err := addAccountAndSave(wall, acc); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
+
+// readKeyShares decodes shares given as arguments or asks for them until
+// there is enough to restore the key.
+func readKeyShares(args []string) ([]*keys.KeyShare, error) {
+	var shares []*keys.KeyShare
+	for _, arg := range args {
+		s, err := keys.NewKeyShareFromString(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid share %s: %v", arg, err)
+		}
+		shares = append(shares, s)
+	}
+	if len(args) > 0 {
+		return shares, nil
+	}
+	for len(shares) == 0 || len(shares) < shares[0].Threshold {
+		str, err := readPassword(fmt.Sprintf("Enter share %d > ", len(shares)+1))
+		if err != nil {
+			return nil, err
+		}
+		if str == "" {
+			return nil, errors.New("not enough shares")
+		}
+		s, err := keys.NewKeyShareFromString(str)
+		if err != nil {
+			fmt.Printf("Invalid share: %v\n", err)
+			continue
+		}
+		shares = append(shares, s)
+	}
+	return shares, nil
+}
@@ -0,0 +1,96 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/cli/flags"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/urfave/cli"
+)
+
+func newConsolidateCommand() cli.Command {
+	return cli.Command{
+		Name:  "consolidate",
+		Usage: "merge small NEO/GAS outputs into one",
+		UsageText: "consolidate --path <path> --rpc <node> --addr <addr> --asset [NEO|GAS|<hex-id>]" +
+			" [--threshold <amount>] [--max-inputs <n>] [--out <path>] [--signer <socket>]\n\n" +
+			"   Creates a transaction spending outputs of the asset belonging to the\n" +
+			"   address with value less than threshold (all outputs if it's not\n" +
+			"   given), starting from the smallest ones, and sending their sum back\n" +
+			"   to the same address.",
+		Action: consolidateOutputs,
+		Flags: []cli.Flag{
+			walletPathFlag,
+			rpcFlag,
+			timeoutFlag,
+			outFlag,
+			signerFlag,
+			flags.AddressFlag{
+				Name:  "addr",
+				Usage: "Address to consolidate outputs of",
+			},
+			cli.StringFlag{
+				Name:  "asset",
+				Usage: "Asset ID",
+			},
+			cli.StringFlag{
+				Name:  "threshold",
+				Usage: "Only merge outputs with value less than this",
+			},
+			cli.IntFlag{
+				Name:  "max-inputs",
+				Usage: "Maximum number of outputs to merge",
+				Value: 100,
+			},
+		},
+	}
+}
+
+func consolidateOutputs(ctx *cli.Context) error {
+	wall, err := openWallet(ctx.String("path"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+
+	addrFlag := ctx.Generic("addr").(*flags.Address)
+	if !addrFlag.IsSet {
+		return cli.NewExitError("address was not provided", 1)
+	}
+	acc := wall.GetAccount(addrFlag.Uint160())
+	if acc == nil {
+		return cli.NewExitError(fmt.Errorf("wallet contains no account for '%s'", addrFlag), 1)
+	}
+
+	asset, err := getAssetID(ctx.String("asset"))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("invalid asset id: %v", err), 1)
+	}
+
+	var threshold util.Fixed8
+	if s := ctx.String("threshold"); s != "" {
+		threshold, err = util.Fixed8FromString(s)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("invalid threshold: %v", err), 1)
+		}
+	}
+
+	gctx, cancel := getGoContext(ctx)
+	defer cancel()
+
+	c, err := client.New(gctx, ctx.String("rpc"), client.Options{})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	tx, err := c.CreateConsolidationTx(addrFlag.String(), asset, threshold, ctx.Int("max-inputs"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if err := signAndSendOrWrite(ctx, c, tx, acc); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	fmt.Println(tx.Hash().StringLE())
+	return nil
+}
@@ -0,0 +1,172 @@
+package wallet
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/urfave/cli"
+)
+
+func newGenerateCommand() cli.Command {
+	return cli.Command{
+		Name:  "generate",
+		Usage: "generate a batch of accounts",
+		UsageText: "generate --path <path> [--count <n>] [--prefix <prefix>] [--workers <n>]" +
+			" [--password-file <file>] [--csv <file>]\n\n" +
+			"   Generates accounts in parallel, encrypts their keys with the same\n" +
+			"   password and adds them to the wallet (it's created if it doesn't\n" +
+			"   exist). Addresses, script hashes and public keys are written to CSV\n" +
+			"   file (<path>.csv by default). With --prefix only accounts with\n" +
+			"   addresses starting with it are generated, each additional character\n" +
+			"   makes the search ~58 times longer, use --timeout to limit it.",
+		Action: generateAccounts,
+		Flags: []cli.Flag{
+			walletPathFlag,
+			timeoutFlag,
+			cli.IntFlag{
+				Name:  "count, c",
+				Usage: "Number of accounts to generate",
+				Value: 1,
+			},
+			cli.StringFlag{
+				Name:  "prefix",
+				Usage: "Address prefix to search for",
+			},
+			cli.IntFlag{
+				Name:  "workers",
+				Usage: "Number of goroutines to use",
+				Value: runtime.NumCPU(),
+			},
+			cli.StringFlag{
+				Name:  "password-file",
+				Usage: "File with the password to encrypt keys with (first line is used)",
+			},
+			cli.StringFlag{
+				Name:  "csv",
+				Usage: "File to write addresses to",
+			},
+		},
+	}
+}
+
+func generateAccounts(ctx *cli.Context) error {
+	path := ctx.String("path")
+	if len(path) == 0 {
+		return cli.NewExitError(errNoPath, 1)
+	}
+	count := ctx.Int("count")
+	if count < 1 {
+		return cli.NewExitError(errors.New("count must be positive"), 1)
+	}
+	csvPath := ctx.String("csv")
+	if csvPath == "" {
+		csvPath = path + ".csv"
+	}
+
+	// New wallet file is only created after successful generation, so
+	// that it's not left empty if the search is interrupted.
+	var (
+		wall   *wallet.Wallet
+		scrypt = keys.NEP2ScryptParams()
+	)
+	if _, err := os.Stat(path); err == nil {
+		wall, err = openWallet(path)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		defer wall.Close()
+		scrypt = wall.Scrypt
+	}
+
+	pass, err := readGeneratePassword(ctx.String("password-file"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	gctx, cancel := getGoContext(ctx)
+	defer cancel()
+
+	workers := ctx.Int("workers")
+	accs, err := wallet.GenerateAccounts(gctx, count, workers, ctx.String("prefix"))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't generate accounts: %v", err), 1)
+	}
+	if err := wallet.EncryptAccounts(accs, pass, scrypt, workers); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	if wall == nil {
+		wall, err = wallet.NewWallet(path)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		defer wall.Close()
+	}
+	for _, acc := range accs {
+		wall.AddAccount(acc)
+	}
+	if err := wall.SaveAtomic(); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if err := writeAccountsCSV(csvPath, accs); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	fmt.Printf("%d accounts added to %s, addresses are written to %s\n", len(accs), wall.Path(), csvPath)
+	return nil
+}
+
+// readGeneratePassword reads the password from the file or asks for it if
+// the file is not given.
+func readGeneratePassword(file string) (string, error) {
+	if file == "" {
+		pass, err := readPassword("Enter password > ")
+		if err != nil {
+			return "", err
+		}
+		passCheck, err := readPassword("Confirm password > ")
+		if err != nil {
+			return "", err
+		}
+		if pass != passCheck {
+			return "", errPhraseMismatch
+		}
+		return pass, nil
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("can't read password file: %v", err)
+	}
+	pass := strings.TrimRight(strings.SplitN(string(data), "\n", 2)[0], "\r")
+	if pass == "" {
+		return "", errors.New("password file is empty")
+	}
+	return pass, nil
+}
+
+func writeAccountsCSV(path string, accs []*wallet.Account) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("can't create CSV file: %v", err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	_ = w.Write([]string{"address", "script_hash", "public_key"})
+	for _, acc := range accs {
+		pub := acc.PrivateKey().PublicKey()
+		_ = w.Write([]string{acc.Address, acc.Contract.ScriptHash().StringLE(), hex.EncodeToString(pub.Bytes())})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("can't write CSV file: %v", err)
+	}
+	return f.Close()
+}
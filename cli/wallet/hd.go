@@ -0,0 +1,123 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/urfave/cli"
+)
+
+var (
+	restoreFlag = cli.BoolFlag{
+		Name:  "restore",
+		Usage: "Ask for an existing mnemonic instead of generating a new one",
+	}
+	mnemonicPassFlag = cli.BoolFlag{
+		Name:  "mnemonic-passphrase",
+		Usage: "Ask for an optional BIP-39 passphrase protecting the mnemonic",
+	}
+)
+
+func newDeriveCommand() cli.Command {
+	return cli.Command{
+		Name:  "derive",
+		Usage: "add an HD account derived from the mnemonic to the existing wallet",
+		UsageText: "derive --path <wallet> [--index <n>] [--mnemonic-passphrase]\n\n" +
+			"   Prompts for the mnemonic (and its passphrase if --mnemonic-passphrase\n" +
+			"   is given) and derives the key using m/44'/888'/0'/0/<n> path, <n>\n" +
+			"   defaults to the next index after the ones used by the wallet HD accounts.",
+		Action: deriveAccount,
+		Flags: []cli.Flag{
+			walletPathFlag,
+			cli.IntFlag{
+				Name:  "index, i",
+				Usage: "Derivation index of the key",
+				Value: -1,
+			},
+			mnemonicPassFlag,
+		},
+	}
+}
+
+func deriveAccount(ctx *cli.Context) error {
+	wall, err := openWallet(ctx.String("path"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+
+	index := wall.NextHDIndex()
+	if i := ctx.Int("index"); i >= 0 {
+		index = uint32(i)
+	}
+	seed, err := readSeed(ctx, false)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	acc, err := createHDAccountWithSeed(wall, seed, index)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	fmt.Printf("%s (%s)\n", acc.Address, acc.Extra.DerivationPath)
+	return nil
+}
+
+// createHDAccount generates (or restores with --restore flag) a mnemonic and
+// adds the first HD account derived from it to the wallet.
+func createHDAccount(ctx *cli.Context, wall *wallet.Wallet) error {
+	seed, err := readSeed(ctx, !ctx.Bool("restore"))
+	if err != nil {
+		return err
+	}
+	_, err = createHDAccountWithSeed(wall, seed, 0)
+	return err
+}
+
+func createHDAccountWithSeed(wall *wallet.Wallet, seed []byte, index uint32) (*wallet.Account, error) {
+	name, phrase, err := readAccountInfo()
+	if err != nil {
+		return nil, err
+	}
+	return wall.CreateHDAccount(seed, index, name, phrase)
+}
+
+// readSeed generates a new mnemonic (printing it) or reads an existing one
+// and returns the seed derived from it.
+func readSeed(ctx *cli.Context, generate bool) ([]byte, error) {
+	var (
+		mnemonic string
+		err      error
+	)
+	if generate {
+		mnemonic, err = wallet.NewMnemonic(wallet.DefaultMnemonicEntropy)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Println("Write down the mnemonic and keep it in a safe place, it's the only way to restore the keys:")
+		fmt.Println()
+		fmt.Println(mnemonic)
+		fmt.Println()
+	} else {
+		mnemonic, err = readPassword("Enter mnemonic > ")
+		if err != nil {
+			return nil, err
+		}
+	}
+	var pass string
+	if ctx.Bool("mnemonic-passphrase") {
+		pass, err = readPassword("Enter mnemonic passphrase > ")
+		if err != nil {
+			return nil, err
+		}
+		if generate {
+			passCheck, err := readPassword("Confirm mnemonic passphrase > ")
+			if err != nil {
+				return nil, err
+			}
+			if pass != passCheck {
+				return nil, errPhraseMismatch
+			}
+		}
+	}
+	return wallet.MnemonicToSeed(mnemonic, pass)
+}
@@ -0,0 +1,129 @@
+package wallet
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/urfave/cli"
+)
+
+func newHistoryCommand() cli.Command {
+	return cli.Command{
+		Name:  "history",
+		Usage: "show account transaction history",
+		UsageText: "history --path <path> [--rpc <node>] [--addr <addr>]\n\n" +
+			"   Shows NEO/GAS outputs received and spent, GAS claims and NEP5\n" +
+			"   transfers for all wallet accounts (or the address given, it doesn't\n" +
+			"   have to be in the wallet). History is kept in the index file next to\n" +
+			"   the wallet and is updated from the RPC node if it's given. Spending\n" +
+			"   transaction and height are only known for unclaimed NEO outputs, other\n" +
+			"   outputs are shown as spent at the block of the update that noticed it\n" +
+			"   and those spent before the first update are not shown at all.",
+		Action: showHistory,
+		Flags: []cli.Flag{
+			walletPathFlag,
+			rpcFlag,
+			timeoutFlag,
+			cli.StringFlag{
+				Name:  "addr",
+				Usage: "Address to show history for",
+			},
+		},
+	}
+}
+
+func showHistory(ctx *cli.Context) error {
+	wall, err := openWallet(ctx.String("path"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+
+	var addrs []string
+	if addr := ctx.String("addr"); addr != "" {
+		if _, err := address.StringToUint160(addr); err != nil {
+			return cli.NewExitError(fmt.Errorf("invalid address: %v", err), 1)
+		}
+		addrs = append(addrs, addr)
+	} else {
+		for _, acc := range wall.Accounts {
+			addrs = append(addrs, acc.Address)
+		}
+	}
+
+	path := wallet.HistoryPath(wall.Path())
+	h, err := wallet.OpenHistory(path)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't read history index: %v", err), 1)
+	}
+
+	if endpoint := ctx.String("rpc"); endpoint != "" {
+		gctx, cancel := getGoContext(ctx)
+		defer cancel()
+
+		c, err := client.New(gctx, endpoint, client.Options{})
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		for _, addr := range addrs {
+			if err := c.UpdateHistory(h.GetAccount(addr), addr); err != nil {
+				return cli.NewExitError(fmt.Errorf("can't update history for %s: %v", addr, err), 1)
+			}
+		}
+		if err := h.Save(path); err != nil {
+			return cli.NewExitError(fmt.Errorf("can't save history index: %v", err), 1)
+		}
+	}
+
+	for _, addr := range addrs {
+		fmt.Println(addr)
+		ah, ok := h.Accounts[addr]
+		if !ok {
+			fmt.Println("\tno history, use --rpc to get it")
+			continue
+		}
+		for _, e := range ah.Entries {
+			fmt.Printf("\t%d\t%s\t%s\t%s %s\t%s", e.Block, formatTimestamp(e.Timestamp),
+				e.Type, e.Amount, assetName(wall, e.Asset), e.Tx.StringLE())
+			if e.Address != "" {
+				fmt.Printf("\t%s", e.Address)
+			}
+			if e.SpentBy != nil {
+				fmt.Printf("\tspent by %s", e.SpentBy.StringLE())
+			}
+			if e.Approximate {
+				fmt.Print("\tspent at or before this block")
+			}
+			fmt.Println()
+		}
+	}
+	return nil
+}
+
+func formatTimestamp(ts uint32) string {
+	if ts == 0 {
+		return "-"
+	}
+	return time.Unix(int64(ts), 0).UTC().Format(time.RFC3339)
+}
+
+// assetName returns the name of UTXO asset or the symbol of NEP5 token
+// imported into the wallet falling back to the hash.
+func assetName(w *wallet.Wallet, asset string) string {
+	switch asset {
+	case core.GoverningTokenID().StringLE():
+		return "NEO"
+	case core.UtilityTokenID().StringLE():
+		return "GAS"
+	}
+	for _, tok := range w.Extra.Tokens {
+		if tok.Hash.StringLE() == asset {
+			return tok.Symbol
+		}
+	}
+	return asset
+}
@@ -0,0 +1,280 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/nspcc-dev/neo-go/cli/flags"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/context"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/urfave/cli"
+)
+
+var (
+	msgFlag = cli.StringFlag{
+		Name:  "msg",
+		Usage: "Message text",
+	}
+	msgFileFlag = cli.StringFlag{
+		Name:  "msg-file",
+		Usage: "File with the message",
+	}
+	networkFlag = cli.StringFlag{
+		Name:  "network",
+		Usage: "Network the message is for: mainnet (default), testnet, privnet or magic number",
+	}
+)
+
+func newSignMessageCommand() cli.Command {
+	return cli.Command{
+		Name:  "sign-message",
+		Usage: "sign a message to prove address ownership",
+		UsageText: "sign-message --path <path> --addr <addr> [--msg <text> | --msg-file <file> | --in <ctx>]" +
+			" [--network <net>] [--out <ctx>] [--signer <socket>]\n\n" +
+			"   Signs the message bound to the network (so that signature for one\n" +
+			"   network is not valid for another one and can't be used as a\n" +
+			"   transaction signature). Public key and signature are printed for\n" +
+			"   simple accounts, multisignature accounts require --out to save the\n" +
+			"   context which is then passed via --in to other cosigners.",
+		Action: signMessage,
+		Flags: []cli.Flag{
+			walletPathFlag,
+			timeoutFlag,
+			msgFlag,
+			msgFileFlag,
+			networkFlag,
+			signerFlag,
+			flags.AddressFlag{
+				Name:  "addr",
+				Usage: "Address to sign the message with",
+			},
+			cli.StringFlag{
+				Name:  "in",
+				Usage: "File with the message context to add signatures to",
+			},
+			cli.StringFlag{
+				Name:  "out",
+				Usage: "File to put the message context to",
+			},
+		},
+	}
+}
+
+func newVerifyMessageCommand() cli.Command {
+	return cli.Command{
+		Name:  "verify-message",
+		Usage: "verify message signature",
+		UsageText: "verify-message (--msg <text> | --msg-file <file>) --pubkey <hex> --sig <hex> [--network <net>] [--addr <addr>]\n" +
+			"   verify-message --in <ctx> [--network <net>] [--addr <addr>]\n\n" +
+			"   Checks the message signature made with sign-message and prints the\n" +
+			"   address it proves the ownership of. If the address is given it must\n" +
+			"   match the signer's one.",
+		Action: verifyMessage,
+		Flags: []cli.Flag{
+			msgFlag,
+			msgFileFlag,
+			networkFlag,
+			flags.AddressFlag{
+				Name:  "addr",
+				Usage: "Expected signer address",
+			},
+			cli.StringFlag{
+				Name:  "pubkey",
+				Usage: "Public key of the signer in hex",
+			},
+			cli.StringFlag{
+				Name:  "sig",
+				Usage: "Signature in hex",
+			},
+			cli.StringFlag{
+				Name:  "in",
+				Usage: "File with the signed message context",
+			},
+		},
+	}
+}
+
+func signMessage(ctx *cli.Context) error {
+	wall, err := openWallet(ctx.String("path"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+
+	addrFlag := ctx.Generic("addr").(*flags.Address)
+	if !addrFlag.IsSet {
+		return cli.NewExitError("address was not provided", 1)
+	}
+	acc := wall.GetAccount(addrFlag.Uint160())
+	if acc == nil {
+		return cli.NewExitError(fmt.Errorf("wallet contains no account for '%s'", addrFlag), 1)
+	}
+	if acc.Contract == nil {
+		return cli.NewExitError(fmt.Errorf("account %s has no verification script", acc.Address), 1)
+	}
+	simple := vm.IsSignatureContract(acc.Contract.Script)
+
+	var c *context.ParameterContext
+	if in := ctx.String("in"); in != "" {
+		c, err = readMessageContext(in, ctx.String("network"))
+	} else {
+		var m *wallet.Message
+		m, err = getMessage(ctx)
+		c = context.NewParameterContext(wallet.MessageContextType, m)
+	}
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	out := ctx.String("out")
+	if out == "" && !simple {
+		return cli.NewExitError(errors.New("output file is required for multisignature accounts"), 1)
+	}
+
+	m := c.Verifiable.(*wallet.Message)
+	fmt.Printf("Message hash: %s\n", m.Hash().StringLE())
+	if ctx.String("signer") == "" {
+		fmt.Println("Enter password to unlock wallet and sign the message")
+	}
+	s, err := getSigner(ctx, acc, "Password > ")
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if err := signContext(c, acc, s); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	if out != "" {
+		if err := writeParameterContext(c, out); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		return nil
+	}
+	pub, err := keys.NewPublicKeyFromBytes(acc.Contract.Script[2:35])
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	fmt.Printf("Address: %s\n", acc.Address)
+	fmt.Printf("Public key: %s\n", hex.EncodeToString(pub.Bytes()))
+	fmt.Printf("Signature: %s\n", hex.EncodeToString(c.Items[acc.Contract.ScriptHash()].GetSignature(pub)))
+	return nil
+}
+
+func verifyMessage(ctx *cli.Context) error {
+	var signers []string
+	if in := ctx.String("in"); in != "" {
+		c, err := readMessageContext(in, ctx.String("network"))
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		ws, err := c.GetWitnesses()
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("message is not fully signed: %v", err), 1)
+		}
+		for i := range ws {
+			h, err := wallet.VerifyMessageWitness(c.Verifiable.(*wallet.Message), &ws[i])
+			if err != nil {
+				return cli.NewExitError(fmt.Errorf("invalid signature of %s: %v", address.Uint160ToString(h), err), 1)
+			}
+			signers = append(signers, address.Uint160ToString(h))
+		}
+	} else {
+		m, err := getMessage(ctx)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		pub, err := keys.NewPublicKeyFromString(ctx.String("pubkey"))
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("invalid public key: %v", err), 1)
+		}
+		sig, err := hex.DecodeString(ctx.String("sig"))
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("invalid signature: %v", err), 1)
+		}
+		if !wallet.VerifyMessage(m, pub, sig) {
+			return cli.NewExitError("signature is invalid", 1)
+		}
+		signers = append(signers, pub.Address())
+	}
+
+	if addrFlag := ctx.Generic("addr").(*flags.Address); addrFlag.IsSet {
+		var found bool
+		for _, s := range signers {
+			found = found || s == addrFlag.String()
+		}
+		if !found {
+			return cli.NewExitError(fmt.Errorf("message is not signed by %s", addrFlag), 1)
+		}
+	}
+	for _, s := range signers {
+		fmt.Printf("Valid signature of %s\n", s)
+	}
+	return nil
+}
+
+// getMessage returns the message given via command-line flags.
+func getMessage(ctx *cli.Context) (*wallet.Message, error) {
+	magic, err := parseNetwork(ctx.String("network"))
+	if err != nil {
+		return nil, err
+	}
+	text, file := ctx.String("msg"), ctx.String("msg-file")
+	switch {
+	case text != "" && file != "":
+		return nil, errors.New("either message text or file must be given, not both")
+	case text != "":
+		return wallet.NewMessage(magic, []byte(text)), nil
+	case file != "":
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("can't read message file: %v", err)
+		}
+		return wallet.NewMessage(magic, data), nil
+	default:
+		return nil, errors.New("message was not provided")
+	}
+}
+
+// readMessageContext reads the message context from the file checking the
+// message to be for the specified network.
+func readMessageContext(filename string, network string) (*context.ParameterContext, error) {
+	magic, err := parseNetwork(network)
+	if err != nil {
+		return nil, err
+	}
+	c, err := readParameterContext(filename)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := c.Verifiable.(*wallet.Message)
+	if !ok {
+		return nil, errors.New("verifiable item is not a message")
+	}
+	if m.Magic != magic {
+		return nil, fmt.Errorf("message is for another network (magic %d)", m.Magic)
+	}
+	return c, nil
+}
+
+// parseNetwork returns the magic of the network given by its name or number.
+func parseNetwork(s string) (uint32, error) {
+	switch s {
+	case "", "mainnet":
+		return uint32(config.ModeMainNet), nil
+	case "testnet":
+		return uint32(config.ModeTestNet), nil
+	case "privnet":
+		return uint32(config.ModePrivNet), nil
+	}
+	magic, err := strconv.ParseUint(s, 0, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid network: %s", s)
+	}
+	return uint32(magic), nil
+}
@@ -17,7 +17,7 @@ func newMultisigCommands() []cli.Command {
 		{
 			Name:      "sign",
 			Usage:     "sign a transaction",
-			UsageText: "multisig sign --path <path> --addr <addr> --in <file.in> --out <file.out>",
+			UsageText: "multisig sign --path <path> --addr <addr> --in <file.in> --out <file.out> [--signer <socket>]",
 			Action:    signMultisig,
 			Flags: []cli.Flag{
 				walletPathFlag,
@@ -25,6 +25,7 @@ func newMultisigCommands() []cli.Command {
 				timeoutFlag,
 				outFlag,
 				inFlag,
+				signerFlag,
 				cli.StringFlag{
 					Name:  "addr",
 					Usage: "Address to use",
@@ -60,18 +61,16 @@ func signMultisig(ctx *cli.Context) error {
 		return cli.NewExitError("verifiable item is not a transaction", 1)
 	}
 	printTxInfo(tx)
-	fmt.Println("Enter password to unlock wallet and sign the transaction")
-	pass, err := readPassword("Password > ")
+	if ctx.String("signer") == "" {
+		fmt.Println("Enter password to unlock wallet and sign the transaction")
+	}
+	s, err := getSigner(ctx, acc, "Password > ")
 	if err != nil {
 		return cli.NewExitError(err, 1)
-	} else if err := acc.Decrypt(pass); err != nil {
-		return cli.NewExitError(fmt.Errorf("can't unlock an account: %v", err), 1)
 	}
 
-	priv := acc.PrivateKey()
-	sign := priv.Sign(tx.GetSignedPart())
-	if err := c.AddSignature(acc.Contract, priv.PublicKey(), sign); err != nil {
-		return cli.NewExitError(fmt.Errorf("can't add signature: %v", err), 1)
+	if err := signContext(c, acc, s); err != nil {
+		return cli.NewExitError(err, 1)
 	} else if err := writeParameterContext(c, ctx.String("out")); err != nil {
 		return cli.NewExitError(err, 1)
 	}
@@ -105,14 +104,14 @@ func readParameterContext(filename string) (*context.ParameterContext, error) {
 
 	c := new(context.ParameterContext)
 	if err := json.Unmarshal(data, c); err != nil {
-		return nil, fmt.Errorf("can't parse transaction: %v", err)
+		return nil, fmt.Errorf("can't parse context: %v", err)
 	}
 	return c, nil
 }
 
 func writeParameterContext(c *context.ParameterContext, filename string) error {
 	if data, err := json.Marshal(c); err != nil {
-		return fmt.Errorf("can't marshal transaction: %v", err)
+		return fmt.Errorf("can't marshal context: %v", err)
 	} else if err := ioutil.WriteFile(filename, data, 0644); err != nil {
 		return fmt.Errorf("can't write transaction to file: %v", err)
 	}
@@ -7,6 +7,7 @@ import (
 	"github.com/nspcc-dev/neo-go/cli/flags"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/context"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"github.com/urfave/cli"
@@ -77,12 +78,13 @@ func newNEP5Commands() []cli.Command {
 		{
 			Name:      "transfer",
 			Usage:     "transfer NEP5 tokens",
-			UsageText: "transfer --path <path> --rpc <node> --from <addr> --to <addr> --token <hash> --amount string",
+			UsageText: "transfer --path <path> --rpc <node> --from <addr> --to <addr> --token <hash> --amount string [--out <path>]",
 			Action:    transferNEP5,
 			Flags: []cli.Flag{
 				walletPathFlag,
 				rpcFlag,
 				timeoutFlag,
+				outFlag,
 				fromAddrFlag,
 				toAddrFlag,
 				cli.StringFlag{
@@ -335,6 +337,20 @@ func transferNEP5(ctx *cli.Context) error {
 
 	gas := flags.Fixed8FromContext(ctx, "gas")
 
+	if outFile := ctx.String("out"); outFile != "" {
+		// The transaction is to be signed with `wallet sign`.
+		tx, err := c.CreateNEP5TransferTx(acc, to, token, amount, gas)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		pc := context.NewParameterContext("Neo.Core.InvocationTransaction", tx)
+		if err := writeParameterContext(pc, outFile); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		fmt.Println(tx.Hash().StringLE())
+		return nil
+	}
+
 	if pass, err := readPassword("Password > "); err != nil {
 		return cli.NewExitError(err, 1)
 	} else if err := acc.Decrypt(pass); err != nil {
@@ -0,0 +1,134 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/urfave/cli"
+)
+
+func newSignCommand() cli.Command {
+	return cli.Command{
+		Name:  "sign",
+		Usage: "sign a transaction offline",
+		UsageText: "sign --path <path> --in <file.in> [--out <file.out>] [--addr <addr>] [--signer <socket>]\n\n" +
+			"   Adds the signature of the account (transaction sender by default) to\n" +
+			"   the transaction context created with `transfer --out` (or signed\n" +
+			"   by someone else), no network connection is needed. The context is\n" +
+			"   written back to the input file if no output file is given.",
+		Action: signOffline,
+		Flags: []cli.Flag{
+			walletPathFlag,
+			inFlag,
+			outFlag,
+			signerFlag,
+			timeoutFlag,
+			cli.StringFlag{
+				Name:  "addr",
+				Usage: "Address to sign with",
+			},
+		},
+	}
+}
+
+func newBroadcastCommand() cli.Command {
+	return cli.Command{
+		Name:      "broadcast",
+		Usage:     "send a signed transaction",
+		UsageText: "broadcast --rpc <node> --in <file.in>",
+		Action:    broadcastTx,
+		Flags: []cli.Flag{
+			rpcFlag,
+			timeoutFlag,
+			inFlag,
+		},
+	}
+}
+
+func signOffline(ctx *cli.Context) error {
+	wall, err := openWallet(ctx.String("path"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+
+	in := ctx.String("in")
+	c, err := readParameterContext(in)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	tx, ok := c.Verifiable.(*transaction.Transaction)
+	if !ok {
+		return cli.NewExitError("verifiable item is not a transaction", 1)
+	}
+
+	var sh util.Uint160
+	if addr := ctx.String("addr"); addr != "" {
+		sh, err = address.StringToUint160(addr)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("invalid address: %v", err), 1)
+		}
+	} else {
+		sh = tx.Sender
+	}
+	acc := wall.GetAccount(sh)
+	if acc == nil {
+		return cli.NewExitError(fmt.Errorf("can't find account for the address: %s", address.Uint160ToString(sh)), 1)
+	}
+
+	printTxInfo(tx)
+	if ctx.String("signer") == "" {
+		fmt.Println("Enter password to unlock wallet and sign the transaction")
+	}
+	s, err := getSigner(ctx, acc, "Password > ")
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if err := signContext(c, acc, s); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	out := ctx.String("out")
+	if out == "" {
+		out = in
+	}
+	if err := writeParameterContext(c, out); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	fmt.Println(tx.Hash().StringLE())
+	return nil
+}
+
+func broadcastTx(ctx *cli.Context) error {
+	c, err := readParameterContext(ctx.String("in"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	tx, ok := c.Verifiable.(*transaction.Transaction)
+	if !ok {
+		return cli.NewExitError("verifiable item is not a transaction", 1)
+	}
+	ws, err := c.GetWitnesses()
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	} else if len(ws) == 0 {
+		return cli.NewExitError("transaction is not signed", 1)
+	}
+	tx.Scripts = append(tx.Scripts[:0], ws...)
+
+	gctx, cancel := getGoContext(ctx)
+	defer cancel()
+
+	rpc, err := client.New(gctx, ctx.String("rpc"), client.Options{})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	} else if err := rpc.SendRawTransaction(tx); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	fmt.Println(tx.Hash().StringLE())
+	return nil
+}
@@ -0,0 +1,117 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/urfave/cli"
+)
+
+func newChangePasswordCommand() cli.Command {
+	return cli.Command{
+		Name:  "change-password",
+		Usage: "change wallet password",
+		UsageText: "change-password --path <path>\n\n" +
+			"   Re-encrypts keys of all wallet accounts with the new password, the\n" +
+			"   wallet file is replaced atomically.",
+		Action: changePassword,
+		Flags: []cli.Flag{
+			walletPathFlag,
+		},
+	}
+}
+
+func newUpgradeScryptCommand() cli.Command {
+	def := keys.NEP2ScryptParams()
+	return cli.Command{
+		Name:  "upgrade-scrypt",
+		Usage: "re-encrypt wallet keys with stronger scrypt parameters",
+		UsageText: "upgrade-scrypt --path <path> [--n <N>] [--r <r>] [--p <p>]\n\n" +
+			"   Re-encrypts keys of all wallet accounts with the same password and\n" +
+			"   new scrypt parameters (they're stored in the wallet). Keys exported\n" +
+			"   from such wallet can only be imported by software supporting\n" +
+			"   non-standard NEP-2 parameters.",
+		Action: upgradeScrypt,
+		Flags: []cli.Flag{
+			walletPathFlag,
+			cli.IntFlag{
+				Name:  "n",
+				Usage: "CPU/memory cost parameter (power of 2)",
+				Value: def.N * 4,
+			},
+			cli.IntFlag{
+				Name:  "r",
+				Usage: "Block size parameter",
+				Value: def.R,
+			},
+			cli.IntFlag{
+				Name:  "p",
+				Usage: "Parallelization parameter",
+				Value: def.P,
+			},
+		},
+	}
+}
+
+func changePassword(ctx *cli.Context) error {
+	wall, err := openWallet(ctx.String("path"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+
+	oldPass, err := readPassword("Enter current password > ")
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	newPass, err := readPassword("Enter new password > ")
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	passCheck, err := readPassword("Confirm new password > ")
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if newPass != passCheck {
+		return cli.NewExitError(errPhraseMismatch, 1)
+	}
+
+	if err := wall.Reencrypt(oldPass, newPass, wall.Scrypt); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if err := wall.SaveAtomic(); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
+
+func upgradeScrypt(ctx *cli.Context) error {
+	wall, err := openWallet(ctx.String("path"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+
+	params := keys.ScryptParams{
+		N: ctx.Int("n"),
+		R: ctx.Int("r"),
+		P: ctx.Int("p"),
+	}
+	if params.N*params.R*params.P <= wall.Scrypt.N*wall.Scrypt.R*wall.Scrypt.P {
+		return cli.NewExitError(errors.New("new parameters are not stronger than the current ones"), 1)
+	}
+
+	pass, err := readPassword("Enter password > ")
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if err := wall.Reencrypt(pass, pass, params); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if err := wall.SaveAtomic(); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	fmt.Printf("Wallet keys are now encrypted with N=%d, r=%d, p=%d\n", params.N, params.R, params.P)
+	return nil
+}
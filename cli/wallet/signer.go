@@ -0,0 +1,147 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/context"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/wallet/remote"
+	"github.com/urfave/cli"
+)
+
+var signerFlag = cli.StringFlag{
+	Name:  "signer",
+	Usage: "Unix socket of the remote signer to use instead of wallet keys",
+}
+
+func newSignerCommand() cli.Command {
+	return cli.Command{
+		Name:  "signer",
+		Usage: "run remote signer process for wallet keys",
+		UsageText: "signer --path <path> --socket <socket>\n\n" +
+			"   Unlocks wallet keys and signs data for the clients connected to the\n" +
+			"   socket (like consensus node with UnlockWallet.RemoteSigner set or\n" +
+			"   wallet commands with --signer flag) until interrupted.",
+		Action: runSigner,
+		Flags: []cli.Flag{
+			walletPathFlag,
+			cli.StringFlag{
+				Name:  "socket, s",
+				Usage: "Unix socket to listen on",
+			},
+		},
+	}
+}
+
+func runSigner(ctx *cli.Context) error {
+	path := ctx.String("socket")
+	if path == "" {
+		return cli.NewExitError("socket path is mandatory", 1)
+	}
+	wall, err := openWallet(ctx.String("path"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+
+	pass, err := readPassword("Enter password > ")
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	s, err := wallet.NewWalletSigner(wall, pass)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer os.Remove(path)
+	if err := os.Chmod(path, 0600); err != nil {
+		_ = l.Close()
+		return cli.NewExitError(err, 1)
+	}
+
+	srv := remote.NewServer(s)
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	go func() {
+		<-stop
+		srv.Shutdown()
+	}()
+
+	pubs, _ := s.PublicKeys()
+	for _, pub := range pubs {
+		fmt.Printf("Serving key for %s\n", pub.Address())
+	}
+	_ = srv.Serve(l)
+	return nil
+}
+
+// getSigner returns the signer to use for the account. It's either a remote
+// signer if --signer flag is set or the account key unlocked with the
+// password entered.
+func getSigner(ctx *cli.Context, acc *wallet.Account, prompt string) (wallet.Signer, error) {
+	if path := ctx.String("signer"); path != "" {
+		return remote.NewClient(path, ctx.Duration("timeout")), nil
+	}
+	if acc.IsWatchOnly() {
+		return nil, fmt.Errorf("account %s is watch-only, use remote signer or sign elsewhere", acc.Address)
+	}
+	pass, err := readPassword(prompt)
+	if err != nil {
+		return nil, err
+	} else if err := acc.Decrypt(pass); err != nil {
+		return nil, fmt.Errorf("can't unlock an account: %v", err)
+	}
+	return wallet.NewLocalSigner(acc.PrivateKey()), nil
+}
+
+// signContext adds signatures for the account contract made with all suitable
+// signer keys to the context.
+func signContext(c *context.ParameterContext, acc *wallet.Account, s wallet.Signer) error {
+	if acc.Contract == nil {
+		return errors.New("account has no contract")
+	}
+	var ctrPubs [][]byte
+	if vm.IsSignatureContract(acc.Contract.Script) {
+		ctrPubs = [][]byte{acc.Contract.Script[2:35]}
+	} else if pubs, ok := vm.ParseMultiSigContract(acc.Contract.Script); ok {
+		ctrPubs = pubs
+	} else {
+		return errors.New("account contract is not a standard one")
+	}
+
+	available, err := s.PublicKeys()
+	if err != nil {
+		return err
+	}
+	var signed int
+	for i := range ctrPubs {
+		pub, err := keys.NewPublicKeyFromBytes(ctrPubs[i])
+		if err != nil {
+			return err
+		}
+		if !available.Contains(pub) {
+			continue
+		}
+		if item := c.Items[acc.Contract.ScriptHash()]; item != nil && item.GetSignature(pub) != nil {
+			continue
+		}
+		if err := c.Sign(acc.Contract, s, pub); err != nil {
+			return fmt.Errorf("can't add signature: %v", err)
+		}
+		signed++
+	}
+	if signed == 0 {
+		return errors.New("no keys available to sign with")
+	}
+	return nil
+}
@@ -4,10 +4,8 @@ import (
 	"bufio"
 	"context"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"strings"
 	"syscall"
@@ -72,6 +70,10 @@ var (
 		Name:  "force",
 		Usage: "Do not ask for a confirmation",
 	}
+	strategyFlag = cli.StringFlag{
+		Name:  "strategy",
+		Usage: "Coin selection strategy: smallest-first (default), largest-first or exact",
+	}
 )
 
 // NewCommands returns 'wallet' command.
@@ -104,8 +106,15 @@ func NewCommands() []cli.Command {
 						Name:  "account, a",
 						Usage: "Create a new account",
 					},
+					cli.BoolFlag{
+						Name:  "mnemonic",
+						Usage: "Generate BIP-39 mnemonic and create an HD account derived from it",
+					},
+					restoreFlag,
+					mnemonicPassFlag,
 				},
 			},
+			newDeriveCommand(),
 			{
 				Name:   "create",
 				Usage:  "add an account to the existing wallet",
@@ -183,13 +192,18 @@ func NewCommands() []cli.Command {
 				Name:  "transfer",
 				Usage: "transfer NEO/GAS",
 				UsageText: "transfer --path <path> --from <addr> --to <addr>" +
-					" --amount <amount> --asset [NEO|GAS|<hex-id>] [--out <path>]",
+					" --amount <amount> --asset [NEO|GAS|<hex-id>] [--out <path>]" +
+					" [--signer <socket>] [--strategy <name>] [--change <addr>]\n\n" +
+					"   Change is sent to --change address if it's given, to the wallet change\n" +
+					"   address (default account) if the wallet has a default account and to\n" +
+					"   the sender otherwise.",
 				Action: transferAsset,
 				Flags: []cli.Flag{
 					walletPathFlag,
 					rpcFlag,
 					timeoutFlag,
 					outFlag,
+					signerFlag,
 					fromAddrFlag,
 					toAddrFlag,
 					cli.StringFlag{
@@ -200,8 +214,38 @@ func NewCommands() []cli.Command {
 						Name:  "asset",
 						Usage: "Asset ID",
 					},
+					strategyFlag,
+					flags.AddressFlag{
+						Name:  "change",
+						Usage: "Address to send change to",
+					},
 				},
 			},
+			newConsolidateCommand(),
+			newGenerateCommand(),
+			newHistoryCommand(),
+			newChangePasswordCommand(),
+			newUpgradeScryptCommand(),
+			newSignMessageCommand(),
+			newVerifyMessageCommand(),
+			newSignCommand(),
+			newBroadcastCommand(),
+			newSignerCommand(),
+			{
+				Name:        "backup",
+				Usage:       "back up account keys with Shamir's secret sharing",
+				Subcommands: newBackupCommands(),
+			},
+			{
+				Name:        "watch",
+				Usage:       "work with watch-only accounts",
+				Subcommands: newWatchCommands(),
+			},
+			{
+				Name:        "contacts",
+				Usage:       "work with address book",
+				Subcommands: newContactsCommands(),
+			},
 			{
 				Name:        "multisig",
 				Usage:       "work with multisig address",
@@ -325,7 +369,7 @@ func exportKeys(ctx *cli.Context) error {
 
 loop:
 	for _, a := range wall.Accounts {
-		if addr != "" && a.Address != addr {
+		if (addr != "" && a.Address != addr) || a.IsWatchOnly() {
 			continue
 		}
 
@@ -345,7 +389,7 @@ loop:
 				return cli.NewExitError(err, 1)
 			}
 
-			pk, err := keys.NEP2Decrypt(wif, pass)
+			pk, err := keys.NEP2DecryptWithParams(wif, pass, wall.Scrypt)
 			if err != nil {
 				return cli.NewExitError(err, 1)
 			}
@@ -382,7 +426,7 @@ func importMultisig(ctx *cli.Context) error {
 		}
 	}
 
-	acc, err := newAccountFromWIF(ctx.String("wif"))
+	acc, err := newAccountFromWIF(ctx.String("wif"), wall.Scrypt)
 	if err != nil {
 		return cli.NewExitError(err, 1)
 	}
@@ -406,7 +450,7 @@ func importWallet(ctx *cli.Context) error {
 
 	defer wall.Close()
 
-	acc, err := newAccountFromWIF(ctx.String("wif"))
+	acc, err := newAccountFromWIF(ctx.String("wif"), wall.Scrypt)
 	if err != nil {
 		return cli.NewExitError(err, 1)
 	}
@@ -500,28 +544,34 @@ func transferAsset(ctx *cli.Context) error {
 		return cli.NewExitError(fmt.Errorf("invalid amount: %v", err), 1)
 	}
 
-	pass, err := readPassword("Enter wallet password > ")
-	if err != nil {
-		return cli.NewExitError(err, 1)
-	} else if err := acc.Decrypt(pass); err != nil {
-		return cli.NewExitError(err, 1)
-	}
-
 	gctx, cancel := getGoContext(ctx)
 	defer cancel()
 
-	c, err := client.New(gctx, ctx.String("rpc"), client.Options{})
+	selector, err := client.GetCoinSelector(ctx.String("strategy"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	c, err := client.New(gctx, ctx.String("rpc"), client.Options{CoinSelector: selector})
 	if err != nil {
 		return cli.NewExitError(err, 1)
 	}
 
+	change := from
+	if changeFlag := ctx.Generic("change").(*flags.Address); changeFlag.IsSet {
+		change = changeFlag.Uint160()
+	} else if hasDefaultAccount(wall) {
+		if addr := wall.GetChangeAddress(); !addr.Equals(util.Uint160{}) {
+			change = addr
+		}
+	}
+
 	tx := transaction.NewContractTX()
 	validUntilBlock, err := c.CalculateValidUntilBlock()
 	if err != nil {
 		return cli.NewExitError(err, 1)
 	}
 	tx.ValidUntilBlock = validUntilBlock
-	if err := request.AddInputsAndUnspentsToTx(tx, fromFlag.String(), asset, amount, c); err != nil {
+	if err := request.AddInputsAndChangeToTx(tx, fromFlag.String(), asset, amount, c, change); err != nil {
 		return cli.NewExitError(err, 1)
 	}
 	tx.Sender = from
@@ -538,29 +588,47 @@ func transferAsset(ctx *cli.Context) error {
 		Position:   1,
 	})
 
-	if outFile := ctx.String("out"); outFile != "" {
-		priv := acc.PrivateKey()
-		pub := priv.PublicKey()
-		sign := priv.Sign(tx.GetSignedPart())
-		c := context2.NewParameterContext("Neo.Core.ContractTransaction", tx)
-		if err := c.AddSignature(acc.Contract, pub, sign); err != nil {
-			return cli.NewExitError(fmt.Errorf("can't add signature: %v", err), 1)
-		} else if data, err := json.Marshal(c); err != nil {
-			return cli.NewExitError(fmt.Errorf("can't marshal tx to JSON: %v", err), 1)
-		} else if err := ioutil.WriteFile(outFile, data, 0644); err != nil {
-			return cli.NewExitError(fmt.Errorf("can't write tx to file: %v", err), 1)
-		}
-	} else {
-		_ = acc.SignTx(tx)
-		if err := c.SendRawTransaction(tx); err != nil {
-			return cli.NewExitError(err, 1)
-		}
+	if err := signAndSendOrWrite(ctx, c, tx, acc); err != nil {
+		return cli.NewExitError(err, 1)
 	}
-
 	fmt.Println(tx.Hash().StringLE())
 	return nil
 }
 
+// signAndSendOrWrite writes the unsigned contract transaction context to the
+// file given by --out flag or signs the transaction with the account and sends
+// it if there is no such flag.
+func signAndSendOrWrite(ctx *cli.Context, c *client.Client, tx *transaction.Transaction, acc *wallet.Account) error {
+	pc := context2.NewParameterContext("Neo.Core.ContractTransaction", tx)
+	if outFile := ctx.String("out"); outFile != "" {
+		// The transaction is to be signed with `wallet sign`.
+		return writeParameterContext(pc, outFile)
+	}
+	s, err := getSigner(ctx, acc, "Enter wallet password > ")
+	if err != nil {
+		return err
+	}
+	if err := signContext(pc, acc, s); err != nil {
+		return err
+	}
+	w, err := pc.GetWitness(acc.Contract)
+	if err != nil {
+		return err
+	}
+	tx.Scripts = append(tx.Scripts, *w)
+	return c.SendRawTransaction(tx)
+}
+
+// hasDefaultAccount returns true if some wallet account is marked as default.
+func hasDefaultAccount(w *wallet.Wallet) bool {
+	for _, acc := range w.Accounts {
+		if acc.Default {
+			return true
+		}
+	}
+	return false
+}
+
 func getGoContext(ctx *cli.Context) (context.Context, func()) {
 	if dur := ctx.Duration("timeout"); dur != 0 {
 		return context.WithTimeout(context.Background(), dur)
@@ -579,6 +647,9 @@ func dumpWallet(ctx *cli.Context) error {
 			return cli.NewExitError(err, 1)
 		}
 		for i := range wall.Accounts {
+			if wall.Accounts[i].IsWatchOnly() {
+				continue
+			}
 			// Just testing the decryption here.
 			err := wall.Accounts[i].Decrypt(pass)
 			if err != nil {
@@ -603,7 +674,11 @@ func createWallet(ctx *cli.Context) error {
 		return cli.NewExitError(err, 1)
 	}
 
-	if ctx.Bool("account") {
+	if ctx.Bool("mnemonic") {
+		if err := createHDAccount(ctx, wall); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	} else if ctx.Bool("account") {
 		if err := createAccount(ctx, wall); err != nil {
 			return cli.NewExitError(err, 1)
 		}
@@ -662,7 +737,10 @@ func getAssetID(s string) (util.Uint256, error) {
 	}
 }
 
-func newAccountFromWIF(wif string) (*wallet.Account, error) {
+// newAccountFromWIF creates an account from plain or NEP-2 encrypted WIF
+// (using standard NEP-2 scrypt parameters), the key is then encrypted with
+// the given (wallet) scrypt parameters.
+func newAccountFromWIF(wif string, scrypt keys.ScryptParams) (*wallet.Account, error) {
 	// note: NEP2 strings always have length of 58 even though
 	// base58 strings can have different lengths even if slice lengths are equal
 	if len(wif) == 58 {
@@ -671,7 +749,16 @@ func newAccountFromWIF(wif string) (*wallet.Account, error) {
 			return nil, err
 		}
 
-		return wallet.NewAccountFromEncryptedWIF(wif, pass)
+		acc, err := wallet.NewAccountFromEncryptedWIF(wif, pass)
+		if err != nil {
+			return nil, err
+		}
+		if scrypt != keys.NEP2ScryptParams() {
+			if err := acc.EncryptWithParams(pass, scrypt); err != nil {
+				return nil, err
+			}
+		}
+		return acc, nil
 	}
 
 	acc, err := wallet.NewAccountFromWIF(wif)
@@ -686,7 +773,7 @@ func newAccountFromWIF(wif string) (*wallet.Account, error) {
 	}
 
 	acc.Label = name
-	if err := acc.Encrypt(pass); err != nil {
+	if err := acc.EncryptWithParams(pass, scrypt); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,235 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/urfave/cli"
+)
+
+func newWatchCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:  "add",
+			Usage: "add watch-only account",
+			UsageText: "add --path <path> [--name <name>] [--script <hex>] [<addr-or-hash>]\n\n" +
+				"   Adds an account without a key for the address (or LE script hash),\n" +
+				"   verification script (optional if the address is given) allows to\n" +
+				"   create unsigned transactions for it.",
+			Action: addWatchOnly,
+			Flags: []cli.Flag{
+				walletPathFlag,
+				cli.StringFlag{
+					Name:  "name, n",
+					Usage: "Optional account name",
+				},
+				cli.StringFlag{
+					Name:  "script",
+					Usage: "Verification script in hex",
+				},
+			},
+		},
+		{
+			Name:      "remove",
+			Usage:     "remove watch-only account",
+			UsageText: "remove --path <path> <addr>",
+			Action:    removeWatchOnly,
+			Flags: []cli.Flag{
+				walletPathFlag,
+			},
+		},
+	}
+}
+
+func newContactsCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:      "list",
+			Usage:     "print address book",
+			UsageText: "list --path <path>",
+			Action:    listContacts,
+			Flags: []cli.Flag{
+				walletPathFlag,
+			},
+		},
+		{
+			Name:      "add",
+			Usage:     "add address book entry",
+			UsageText: "add --path <path> --name <name> [--note <note>] <addr>",
+			Action:    addContact,
+			Flags: []cli.Flag{
+				walletPathFlag,
+				cli.StringFlag{
+					Name:  "name, n",
+					Usage: "Contact name",
+				},
+				cli.StringFlag{
+					Name:  "note",
+					Usage: "Optional note",
+				},
+			},
+		},
+		{
+			Name:      "remove",
+			Usage:     "remove address book entry",
+			UsageText: "remove --path <path> <name>",
+			Action:    removeContact,
+			Flags: []cli.Flag{
+				walletPathFlag,
+			},
+		},
+	}
+}
+
+func addWatchOnly(ctx *cli.Context) error {
+	wall, err := openWallet(ctx.String("path"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+
+	var script []byte
+	if s := ctx.String("script"); s != "" {
+		script, err = hex.DecodeString(s)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("invalid script: %v", err), 1)
+		}
+	}
+
+	var addr string
+	switch {
+	case ctx.NArg() > 0:
+		h, err := parseAddressOrHash(ctx.Args().First())
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		addr = address.Uint160ToString(h)
+	case script != nil:
+		addr = address.Uint160ToString(hash.Hash160(script))
+	default:
+		return cli.NewExitError(errors.New("address or script must be provided"), 1)
+	}
+
+	acc, err := wallet.NewWatchOnlyAccount(addr, script)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	acc.Label = ctx.String("name")
+	if err := addAccountAndSave(wall, acc); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	fmt.Println(acc.Address)
+	return nil
+}
+
+func removeWatchOnly(ctx *cli.Context) error {
+	wall, err := openWallet(ctx.String("path"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+
+	if ctx.NArg() == 0 {
+		return cli.NewExitError("address must be provided", 1)
+	}
+	h, err := parseAddressOrHash(ctx.Args().First())
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	acc := wall.GetAccount(h)
+	if acc == nil {
+		return cli.NewExitError("account wasn't found", 1)
+	}
+	if !acc.IsWatchOnly() {
+		return cli.NewExitError("account has a key, use `wallet remove` to remove it", 1)
+	}
+	if err := wall.RemoveAccount(acc.Address); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if err := wall.Save(); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
+
+func listContacts(ctx *cli.Context) error {
+	wall, err := openWallet(ctx.String("path"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+
+	for _, c := range wall.Extra.Contacts {
+		fmt.Printf("%s\t%s", c.Name, c.Address)
+		if c.Note != "" {
+			fmt.Printf("\t%s", c.Note)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func addContact(ctx *cli.Context) error {
+	wall, err := openWallet(ctx.String("path"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+
+	name := ctx.String("name")
+	if name == "" {
+		return cli.NewExitError("name must be provided", 1)
+	}
+	if ctx.NArg() == 0 {
+		return cli.NewExitError("address must be provided", 1)
+	}
+	h, err := parseAddressOrHash(ctx.Args().First())
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if err := wall.AddContact(wallet.NewContact(name, h, ctx.String("note"))); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if err := wall.Save(); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
+
+func removeContact(ctx *cli.Context) error {
+	wall, err := openWallet(ctx.String("path"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+
+	if ctx.NArg() == 0 {
+		return cli.NewExitError("name must be provided", 1)
+	}
+	if err := wall.RemoveContact(ctx.Args().First()); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if err := wall.Save(); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
+
+// parseAddressOrHash parses the string as an address or as a script hash in
+// LE form.
+func parseAddressOrHash(s string) (util.Uint160, error) {
+	h, err := address.StringToUint160(s)
+	if err == nil {
+		return h, nil
+	}
+	h, err = util.Uint160DecodeStringLE(s)
+	if err != nil {
+		return h, fmt.Errorf("%s is neither an address nor a script hash", s)
+	}
+	return h, nil
+}
@@ -6,6 +6,7 @@ require (
 	github.com/dgraph-io/badger/v2 v2.0.3
 	github.com/go-redis/redis v6.10.2+incompatible
 	github.com/go-yaml/yaml v2.1.0+incompatible
+	github.com/gorilla/websocket v1.4.2
 	github.com/mr-tron/base58 v1.1.2
 	github.com/nspcc-dev/dbft v0.0.0-20200303183127-36d3da79c682
 	github.com/nspcc-dev/rfc6979 v0.2.0
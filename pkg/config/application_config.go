@@ -5,6 +5,7 @@ import (
 
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/network/metrics"
+	"github.com/nspcc-dev/neo-go/pkg/network/secure"
 	"github.com/nspcc-dev/neo-go/pkg/rpc"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
 )
@@ -19,6 +20,7 @@ type ApplicationConfiguration struct {
 	MaxPeers          int                     `yaml:"MaxPeers"`
 	MinPeers          int                     `yaml:"MinPeers"`
 	NodePort          uint16                  `yaml:"NodePort"`
+	P2PSecurity       secure.Config           `yaml:"P2PSecurity"`
 	PingInterval      time.Duration           `yaml:"PingInterval"`
 	PingTimeout       time.Duration           `yaml:"PingTimeout"`
 	Pprof             metrics.Config          `yaml:"Pprof"`
@@ -26,5 +28,6 @@ type ApplicationConfiguration struct {
 	ProtoTickInterval time.Duration           `yaml:"ProtoTickInterval"`
 	Relay             bool                    `yaml:"Relay"`
 	RPC               rpc.Config              `yaml:"RPC"`
+	Transport         string                  `yaml:"Transport"`
 	UnlockWallet      wallet.Config           `yaml:"UnlockWallet"`
 }
@@ -33,6 +33,19 @@ type (
 		// Maximum number of low priority transactions accepted into block.
 		MaxFreeTransactionsPerBlock int `yaml:"MaxFreeTransactionsPerBlock"`
 		MemPoolSize                 int `yaml:"MemPoolSize"`
+		// ObjectCacheSize is the number of most recently used accounts,
+		// assets and contracts kept decoded in memory.
+		ObjectCacheSize int `yaml:"ObjectCacheSize"`
+		// PruneAppLogs enables pruning mode keeping application execution
+		// results (application logs) for the given number of latest blocks
+		// only. Blocks, transactions and coin states (even fully spent and
+		// claimed ones) are always kept as contracts can access them. 0
+		// (default) disables pruning.
+		PruneAppLogs uint32 `yaml:"PruneAppLogs"`
+		// RollbackDepth is the number of latest blocks that can be
+		// reverted with `db rollback` command, undo data is stored for
+		// them. 0 (default) disables undo data recording.
+		RollbackDepth uint32 `yaml:"RollbackDepth"`
 		// SaveStorageBatch enables storage batch saving before every persist.
 		SaveStorageBatch  bool      `yaml:"SaveStorageBatch"`
 		SecondsPerBlock   int       `yaml:"SecondsPerBlock"`
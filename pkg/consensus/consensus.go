@@ -2,14 +2,17 @@ package consensus
 
 import (
 	"errors"
+	"fmt"
 	"math/rand"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/nspcc-dev/dbft"
 	"github.com/nspcc-dev/dbft/block"
 	"github.com/nspcc-dev/dbft/crypto"
 	"github.com/nspcc-dev/dbft/payload"
+	"github.com/nspcc-dev/dbft/timer"
 	"github.com/nspcc-dev/neo-go/pkg/core"
 	coreb "github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/blockchainer"
@@ -21,6 +24,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 )
 
@@ -46,6 +50,12 @@ type Service interface {
 	// OnNewBlock notifies consensus service that there is a new block in
 	// the chain (without explicitly passing it to the service).
 	OnNewBlock()
+	// GetState returns a snapshot of the current consensus state, it's nil
+	// if the service is not running.
+	GetState() *State
+	// Shutdown stops the event loop of the service, it does nothing if the
+	// service is not running. Stopped service can't be started again.
+	Shutdown()
 }
 
 type service struct {
@@ -65,7 +75,23 @@ type service struct {
 	// process.
 	blockEvents  chan struct{}
 	lastProposal []util.Uint256
-	wallet       *wallet.Wallet
+	// lastCommit is the last commit sent by this node, it's used to
+	// prevent sending different commits for the same height.
+	lastCommit *Payload
+	// wallet is only available for local signers.
+	wallet *wallet.Wallet
+	signer wallet.Signer
+
+	started *atomic.Bool
+	// quit is closed on shutdown, finished is closed by the event loop
+	// when it exits.
+	quit     chan struct{}
+	finished chan struct{}
+	// stateLock protects state and roundStart which are updated by the
+	// event loop and read by GetState.
+	stateLock  sync.RWMutex
+	state      State
+	roundStart time.Time
 }
 
 // Config is a configuration for consensus services.
@@ -87,6 +113,9 @@ type Config struct {
 	TimePerBlock time.Duration
 	// Wallet is a local-node wallet configuration.
 	Wallet *wallet.Config
+	// Signer is used to sign consensus payloads. If it's nil, the signer
+	// is created using Wallet configuration.
+	Signer wallet.Signer
 }
 
 // NewService returns new consensus.Service instance.
@@ -109,26 +138,30 @@ func NewService(cfg Config) (Service, error) {
 
 		transactions: make(chan *transaction.Transaction, 100),
 		blockEvents:  make(chan struct{}, 1),
+		started:      atomic.NewBool(false),
+		quit:         make(chan struct{}),
+		finished:     make(chan struct{}),
 	}
 
-	if cfg.Wallet == nil {
+	if cfg.Wallet == nil && cfg.Signer == nil {
 		return srv, nil
 	}
 
-	var err error
+	srv.signer = cfg.Signer
+	if srv.signer == nil {
+		var err error
 
-	if srv.wallet, err = wallet.NewWalletFromFile(cfg.Wallet.Path); err != nil {
-		return nil, err
+		if srv.signer, srv.wallet, err = newSigner(cfg.Wallet, srv.log); err != nil {
+			return nil, err
+		}
 	}
 
-	defer srv.wallet.Close()
-
 	srv.dbft = dbft.New(
 		dbft.WithLogger(srv.log),
 		dbft.WithSecondsPerBlock(cfg.TimePerBlock),
 		dbft.WithGetKeyPair(srv.getKeyPair),
 		dbft.WithTxPerBlock(10000),
-		dbft.WithRequestTx(cfg.RequestTx),
+		dbft.WithRequestTx(srv.requestTx),
 		dbft.WithGetTx(srv.getTx),
 		dbft.WithGetVerified(srv.getVerifiedTx),
 		dbft.WithBroadcast(srv.broadcast),
@@ -164,56 +197,108 @@ var (
 )
 
 func (s *service) Start() {
+	s.start()
+	go s.eventLoop()
+}
+
+// start initializes dBFT and restores its saved state, events are to be
+// processed by the caller after that.
+func (s *service) start() {
 	s.dbft.Start()
+	s.started.Store(true)
+	s.updateState(reasonOther)
+	s.restoreState()
+	s.updateState(reasonRecovery)
+}
 
-	go s.eventLoop()
+// Shutdown implements Service interface.
+func (s *service) Shutdown() {
+	if s.started.CAS(true, false) {
+		close(s.quit)
+		<-s.finished
+	}
 }
 
 func (s *service) eventLoop() {
 	for {
 		select {
+		case <-s.quit:
+			close(s.finished)
+			return
 		case hv := <-s.dbft.Timer.C():
-			s.log.Debug("timer fired",
-				zap.Uint32("height", hv.Height),
-				zap.Uint("view", uint(hv.View)))
-			s.dbft.OnTimeout(hv)
+			s.handleTimeout(hv)
 		case msg := <-s.messages:
-			fields := []zap.Field{
-				zap.Uint16("from", msg.validatorIndex),
-				zap.Stringer("type", msg.Type()),
-			}
-
-			if msg.Type() == payload.RecoveryMessageType {
-				rec := msg.GetRecoveryMessage().(*recoveryMessage)
-				if rec.preparationHash == nil {
-					req := rec.GetPrepareRequest(&msg, s.dbft.Validators, uint16(s.dbft.PrimaryIndex))
-					if req != nil {
-						h := req.Hash()
-						rec.preparationHash = &h
-					}
-				}
-
-				fields = append(fields,
-					zap.Int("#preparation", len(rec.preparationPayloads)),
-					zap.Int("#commit", len(rec.commitPayloads)),
-					zap.Int("#changeview", len(rec.changeViewPayloads)),
-					zap.Bool("#request", rec.prepareRequest != nil),
-					zap.Bool("#hash", rec.preparationHash != nil))
-			}
-
-			s.log.Debug("received message", fields...)
-			s.dbft.OnReceive(&msg)
+			s.handleMessage(msg)
 		case tx := <-s.transactions:
-			s.dbft.OnTransaction(tx)
+			s.handleTransaction(tx)
 		case <-s.blockEvents:
-			s.log.Debug("new block in the chain",
-				zap.Uint32("dbft index", s.dbft.BlockIndex),
-				zap.Uint32("chain index", s.Chain.BlockHeight()))
-			s.dbft.InitializeConsensus(0)
+			s.handleNewBlock()
+		}
+	}
+}
+
+// handleTimeout passes timer event to dBFT.
+func (s *service) handleTimeout(hv timer.HV) {
+	s.log.Debug("timer fired",
+		zap.Uint32("height", hv.Height),
+		zap.Uint("view", uint(hv.View)))
+	s.dbft.OnTimeout(hv)
+	s.updateState(reasonTimeout)
+}
+
+// handleMessage passes validated consensus payload to dBFT.
+func (s *service) handleMessage(msg Payload) {
+	fields := []zap.Field{
+		zap.Uint16("from", msg.validatorIndex),
+		zap.Stringer("type", msg.Type()),
+	}
+
+	if msg.Type() == payload.RecoveryMessageType {
+		rec := msg.GetRecoveryMessage().(*recoveryMessage)
+		if rec.preparationHash == nil {
+			req := rec.GetPrepareRequest(&msg, s.dbft.Validators, uint16(s.dbft.PrimaryIndex))
+			if req != nil {
+				h := req.Hash()
+				rec.preparationHash = &h
+			}
 		}
+
+		fields = append(fields,
+			zap.Int("#preparation", len(rec.preparationPayloads)),
+			zap.Int("#commit", len(rec.commitPayloads)),
+			zap.Int("#changeview", len(rec.changeViewPayloads)),
+			zap.Bool("#request", rec.prepareRequest != nil),
+			zap.Bool("#hash", rec.preparationHash != nil))
+	}
+
+	s.log.Debug("received message", fields...)
+	s.onReceiveEvent(&msg)
+	s.dbft.OnReceive(&msg)
+	switch msg.Type() {
+	case payload.ChangeViewType:
+		s.updateState(reasonChangeView)
+	case payload.RecoveryMessageType:
+		s.updateState(reasonRecovery)
+	default:
+		s.updateState(reasonOther)
 	}
 }
 
+// handleTransaction passes new transaction to dBFT.
+func (s *service) handleTransaction(tx *transaction.Transaction) {
+	s.dbft.OnTransaction(tx)
+	s.updateState(reasonOther)
+}
+
+// handleNewBlock reinitializes dBFT for the next height.
+func (s *service) handleNewBlock() {
+	s.log.Debug("new block in the chain",
+		zap.Uint32("dbft index", s.dbft.BlockIndex),
+		zap.Uint32("chain index", s.Chain.BlockHeight()))
+	s.dbft.InitializeConsensus(0)
+	s.updateState(reasonOther)
+}
+
 func (s *service) validatePayload(p *Payload) bool {
 	validators := s.getValidators()
 	if int(p.validatorIndex) >= len(validators) {
@@ -227,19 +312,19 @@ func (s *service) validatePayload(p *Payload) bool {
 }
 
 func (s *service) getKeyPair(pubs []crypto.PublicKey) (int, crypto.PrivateKey, crypto.PublicKey) {
-	for i := range pubs {
-		sh := pubs[i].(*publicKey).GetScriptHash()
-		acc := s.wallet.GetAccount(sh)
-		if acc == nil {
-			continue
-		}
+	available, err := s.signer.PublicKeys()
+	if err != nil {
+		s.log.Error("can't get keys from signer", zap.Error(err))
+		return -1, nil, nil
+	}
 
-		key, err := keys.NEP2Decrypt(acc.EncryptedWIF, s.Config.Wallet.Password)
-		if err != nil {
+	for i := range pubs {
+		pub := pubs[i].(*publicKey).PublicKey
+		if !available.Contains(pub) {
 			continue
 		}
 
-		return i, &privateKey{PrivateKey: key}, &publicKey{PublicKey: key.PublicKey()}
+		return i, &signerKey{signer: s.signer, pub: pub}, &publicKey{PublicKey: pub}
 	}
 
 	return -1, nil, nil
@@ -280,12 +365,18 @@ func (s *service) OnPayload(cp *Payload) {
 		s.lastProposal = req.transactionHashes
 	}
 
-	s.messages <- *cp
+	select {
+	case s.messages <- *cp:
+	case <-s.quit:
+	}
 }
 
 func (s *service) OnTransaction(tx *transaction.Transaction) {
 	if s.dbft != nil {
-		s.transactions <- tx
+		select {
+		case s.transactions <- tx:
+		case <-s.quit:
+		}
 	}
 }
 
@@ -322,12 +413,40 @@ func (s *service) broadcast(p payload.ConsensusPayload) {
 		pr.minerTx = *s.txx.Get(pr.transactionHashes[0]).(*transaction.Transaction)
 	}
 
-	if err := p.(*Payload).Sign(s.dbft.Priv.(*privateKey)); err != nil {
+	if err := p.(*Payload).Sign(s.dbft.Priv.(signingKey)); err != nil {
 		s.log.Warn("can't sign consensus payload", zap.Error(err))
 	}
 
-	s.cache.Add(p)
-	s.Config.Broadcast(p.(*Payload))
+	cp := p.(*Payload)
+	if cp.Type() == payload.CommitType {
+		if last := s.conflictingCommit(cp); last != nil {
+			s.log.Warn("refusing to send a different commit for the same height, resending the old one",
+				zap.Uint32("height", cp.Height()))
+			cp = last
+			s.setOwnCommit(cp)
+		} else {
+			s.lastCommit = cp
+		}
+	}
+	if isPersisted(cp.Type()) {
+		// The payload must be saved before it's sent, so that the node
+		// doesn't contradict itself after restart.
+		if err := s.savePayload(cp); err != nil {
+			s.log.Error("can't save consensus payload", zap.Error(err))
+			return
+		}
+	}
+
+	s.cache.Add(cp)
+	s.onBroadcastEvent(cp)
+	s.Config.Broadcast(cp)
+}
+
+// requestTx requests missing transactions from the network.
+func (s *service) requestTx(hashes ...util.Uint256) {
+	updateRequestedTransactionsMetric(len(hashes))
+	s.recordEvent(EventTxRequested, -1, fmt.Sprintf("%d transactions", len(hashes)))
+	s.Config.RequestTx(hashes...)
 }
 
 func (s *service) getTx(h util.Uint256) block.Transaction {
@@ -451,7 +570,10 @@ func (s *service) getVerifiedTx(count int) []block.Transaction {
 
 	var txOuts []transaction.Output
 	if netFee != 0 {
-		sh := s.wallet.GetChangeAddress()
+		var sh util.Uint160
+		if s.wallet != nil {
+			sh = s.wallet.GetChangeAddress()
+		}
 		if sh.Equals(util.Uint160{}) {
 			pk := s.dbft.Pub.(*publicKey)
 			sh = pk.GetScriptHash()
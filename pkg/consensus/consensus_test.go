@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/nspcc-dev/dbft/block"
+	"github.com/nspcc-dev/dbft/crypto"
 	"github.com/nspcc-dev/dbft/payload"
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core"
@@ -35,6 +36,56 @@ func TestNewService(t *testing.T) {
 	srv.Chain.Close()
 }
 
+func TestService_getKeyPair(t *testing.T) {
+	priv, pub := getTestValidator(2)
+	srv, err := NewService(Config{
+		Logger:    zaptest.NewLogger(t),
+		Broadcast: func(*Payload) {},
+		Chain:     newTestChain(t),
+		RequestTx: func(...util.Uint256) {},
+		Signer:    wallet.NewLocalSigner(priv.PrivateKey),
+	})
+	require.NoError(t, err)
+	s := srv.(*service)
+	defer s.Chain.Close()
+
+	_, other := getTestValidator(0)
+	i, key, pk := s.getKeyPair([]crypto.PublicKey{other, pub})
+	require.Equal(t, 1, i)
+	require.Equal(t, pub.PublicKey, pk.(*publicKey).PublicKey)
+
+	p := new(Payload)
+	p.message = &message{}
+	p.SetType(payload.CommitType)
+	p.SetPayload(&commit{})
+	require.NoError(t, p.Sign(key.(signingKey)))
+	require.True(t, p.Verify(pub.GetScriptHash()))
+
+	i, _, _ = s.getKeyPair([]crypto.PublicKey{other})
+	require.Equal(t, -1, i)
+}
+
+func TestService_WrongPassword(t *testing.T) {
+	srv, err := NewService(Config{
+		Logger:    zaptest.NewLogger(t),
+		Broadcast: func(*Payload) {},
+		Chain:     newTestChain(t),
+		RequestTx: func(...util.Uint256) {},
+		Wallet: &wallet.Config{
+			Path:     "./testdata/wallet1.json",
+			Password: "wrong",
+		},
+	})
+	require.NoError(t, err)
+	s := srv.(*service)
+	defer s.Chain.Close()
+
+	// Node works as a non-validator.
+	_, pub := getTestValidator(0)
+	i, _, _ := s.getKeyPair([]crypto.PublicKey{pub})
+	require.Equal(t, -1, i)
+}
+
 func TestService_GetVerified(t *testing.T) {
 	srv := newTestService(t)
 	var txs []*transaction.Transaction
@@ -173,6 +224,125 @@ func TestService_OnPayload(t *testing.T) {
 	srv.Chain.Close()
 }
 
+func TestService_PersistPayloads(t *testing.T) {
+	srv := newTestService(t)
+	defer srv.Chain.Close()
+
+	priv, _ := getTestValidator(1)
+	newCommit := func(height uint32, sig byte) *Payload {
+		p := new(Payload)
+		p.message = &message{}
+		p.SetType(payload.CommitType)
+		p.SetHeight(height)
+		p.SetValidatorIndex(1)
+		p.SetPayload(&commit{signature: [signatureSize]byte{sig}})
+		require.NoError(t, p.Sign(priv))
+		return p
+	}
+
+	height := srv.Chain.BlockHeight() + 1
+	p := newCommit(height, 1)
+	require.NoError(t, srv.savePayload(p))
+
+	loaded := srv.loadPayloads(height)
+	require.Equal(t, 1, len(loaded))
+	require.Equal(t, p.Hash(), loaded[0].Hash())
+	require.Equal(t, 0, len(srv.loadPayloads(height+1)))
+
+	t.Run("views", func(t *testing.T) {
+		p1 := newCommit(height, 3)
+		p1.SetViewNumber(1)
+		require.NoError(t, p1.Sign(priv))
+		require.NoError(t, srv.savePayload(p1))
+
+		loaded := srv.loadPayloads(height)
+		require.Equal(t, 2, len(loaded))
+		require.Equal(t, p.Hash(), loaded[0].Hash())
+		require.Equal(t, p1.Hash(), loaded[1].Hash())
+	})
+
+	t.Run("conflicting commit", func(t *testing.T) {
+		srv.lastCommit = p
+		require.Nil(t, srv.conflictingCommit(newCommit(height, 1)))
+		require.Equal(t, p, srv.conflictingCommit(newCommit(height, 2)))
+		require.Nil(t, srv.conflictingCommit(newCommit(height+1, 2)))
+	})
+
+	t.Run("old heights are removed", func(t *testing.T) {
+		require.NoError(t, srv.savePayload(newCommit(height+1, 1)))
+		require.Equal(t, 0, len(srv.loadPayloads(height)))
+		require.Equal(t, 1, len(srv.loadPayloads(height+1)))
+	})
+}
+
+func TestService_RestorePayloads(t *testing.T) {
+	srv := newTestService(t)
+	defer srv.Chain.Close()
+
+	var sent []*Payload
+	srv.Config.Broadcast = func(p *Payload) { sent = append(sent, p) }
+
+	// Start dBFT once to get own index and key.
+	srv.dbft.Start()
+	myIndex := srv.dbft.MyIndex
+	priv := srv.dbft.Priv.(signingKey)
+	height := srv.dbft.BlockIndex
+	newCommit := func(sig byte) *Payload {
+		p := new(Payload)
+		p.message = &message{}
+		p.SetType(payload.CommitType)
+		p.SetHeight(height)
+		p.SetValidatorIndex(uint16(myIndex))
+		p.SetPayload(&commit{signature: [signatureSize]byte{sig}})
+		require.NoError(t, p.Sign(priv))
+		return p
+	}
+
+	p := newCommit(1)
+	require.NoError(t, srv.savePayload(p))
+
+	srv.Start()
+	srv.Shutdown()
+
+	require.Equal(t, 1, len(sent))
+	require.Equal(t, p.Hash(), sent[0].Hash())
+	require.Equal(t, p.Hash(), srv.lastCommit.Hash())
+	require.NotNil(t, srv.GetPayload(p.Hash()))
+	require.Equal(t, p.Hash(), srv.dbft.CommitPayloads[myIndex].Hash())
+
+	// A different commit for the same height is replaced by the restored
+	// one both on the wire and in dBFT context.
+	srv.broadcast(newCommit(2))
+	require.Equal(t, 2, len(sent))
+	require.Equal(t, p.Hash(), sent[1].Hash())
+	require.Equal(t, p.Hash(), srv.dbft.CommitPayloads[myIndex].Hash())
+}
+
+func TestService_GetState(t *testing.T) {
+	srv := newTestService(t)
+	defer srv.Chain.Close()
+
+	require.Nil(t, srv.GetState())
+
+	srv.dbft.Start()
+	srv.started.Store(true)
+	srv.updateState(reasonOther)
+
+	st := srv.GetState()
+	require.NotNil(t, st)
+	require.Equal(t, srv.Chain.BlockHeight()+1, st.Height)
+	require.Equal(t, byte(0), st.View)
+	require.Equal(t, len(srv.dbft.Validators), len(st.Validators))
+	require.Equal(t, 1, len(st.Events))
+	require.Equal(t, EventRoundStarted, st.Events[0].Type)
+
+	srv.requestTx(util.Uint256{1}, util.Uint256{2})
+	st = srv.GetState()
+	require.Equal(t, 2, len(st.Events))
+	require.Equal(t, EventTxRequested, st.Events[1].Type)
+	require.Equal(t, -1, st.Events[1].Validator)
+}
+
 func shouldReceive(t *testing.T, ch chan Payload) {
 	select {
 	case <-ch:
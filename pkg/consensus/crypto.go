@@ -5,8 +5,15 @@ import (
 	"errors"
 
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
 )
 
+// signingKey is a key that can be used to sign consensus payloads.
+type signingKey interface {
+	Sign(data []byte) ([]byte, error)
+	PublicKey() *keys.PublicKey
+}
+
 // privateKey is a wrapper around keys.PrivateKey
 // which implements crypto.PrivateKey interface.
 type privateKey struct {
@@ -29,6 +36,33 @@ func (p *privateKey) Sign(data []byte) ([]byte, error) {
 	return p.PrivateKey.Sign(data), nil
 }
 
+// signerKey implements crypto.PrivateKey interface using wallet.Signer, the
+// key itself is kept by the signer and can't be marshaled.
+type signerKey struct {
+	signer wallet.Signer
+	pub    *keys.PublicKey
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+func (p signerKey) MarshalBinary() ([]byte, error) {
+	return nil, errors.New("private key is kept by the signer")
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+func (p *signerKey) UnmarshalBinary([]byte) error {
+	return errors.New("private key is kept by the signer")
+}
+
+// Sign implements dbft's crypto.PrivateKey interface.
+func (p *signerKey) Sign(data []byte) ([]byte, error) {
+	return p.signer.Sign(p.pub, data)
+}
+
+// PublicKey returns the public key corresponding to the private one.
+func (p *signerKey) PublicKey() *keys.PublicKey {
+	return p.pub
+}
+
 // publicKey is a wrapper around keys.PublicKey
 // which implements crypto.PublicKey interface.
 type publicKey struct {
@@ -186,7 +186,7 @@ func (p *Payload) EncodeBinary(w *io.BinWriter) {
 
 // Sign signs payload using the private key.
 // It also sets corresponding verification and invocation scripts.
-func (p *Payload) Sign(key *privateKey) error {
+func (p *Payload) Sign(key signingKey) error {
 	sig, err := key.Sign(p.GetSignedPart())
 	if err != nil {
 		return err
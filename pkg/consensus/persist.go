@@ -0,0 +1,172 @@
+package consensus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+
+	"github.com/nspcc-dev/dbft/payload"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"go.uber.org/zap"
+)
+
+// persistedTypes are the types of own payloads that are saved to the node
+// DB before broadcasting, the order is the one they're restored in.
+var persistedTypes = []payload.MessageType{
+	payload.PrepareRequestType,
+	payload.PrepareResponseType,
+	payload.CommitType,
+}
+
+// heightKey returns the DB key prefix for payloads saved for the given
+// height.
+func heightKey(height uint32) []byte {
+	key := make([]byte, 5)
+	key[0] = byte(storage.SYSConsensus)
+	binary.BigEndian.PutUint32(key[1:], height)
+	return key
+}
+
+// stateKey returns the DB key for the saved payload of the given height, view
+// and type.
+func stateKey(height uint32, view byte, t payload.MessageType) []byte {
+	return append(heightKey(height), view, byte(t))
+}
+
+// typeOrder returns the position of the given type in persistedTypes.
+func typeOrder(t payload.MessageType) int {
+	for i, pt := range persistedTypes {
+		if t == pt {
+			return i
+		}
+	}
+	return len(persistedTypes)
+}
+
+// isPersisted checks whether payloads of the given type are saved to the DB.
+func isPersisted(t payload.MessageType) bool {
+	return typeOrder(t) < len(persistedTypes)
+}
+
+// savePayload saves own signed payload to the DB synchronously, payloads
+// saved for other heights are removed.
+func (s *service) savePayload(p *Payload) error {
+	store := s.Chain.GetStore()
+	if store == nil {
+		return nil
+	}
+	buf := io.NewBufBinWriter()
+	p.EncodeBinary(buf.BinWriter)
+	if buf.Err != nil {
+		return buf.Err
+	}
+	if err := s.removeOldPayloads(store, p.Height()); err != nil {
+		return err
+	}
+	return storage.PutSync(store, stateKey(p.Height(), p.ViewNumber(), p.Type()), buf.Bytes())
+}
+
+// removeOldPayloads removes payloads saved for heights other than the given
+// one.
+func (s *service) removeOldPayloads(store storage.Store, height uint32) error {
+	var (
+		cur   = heightKey(height)
+		batch = store.Batch()
+		found bool
+	)
+	store.Seek(storage.SYSConsensus.Bytes(), func(k, _ []byte) {
+		if len(k) < len(cur) || !bytes.Equal(k[:len(cur)], cur) {
+			key := make([]byte, len(k))
+			copy(key, k)
+			batch.Delete(key)
+			found = true
+		}
+	})
+	if !found {
+		return nil
+	}
+	return store.PutBatch(batch)
+}
+
+// loadPayloads returns own payloads saved for the given height ordered by
+// view and then by type as in persistedTypes.
+func (s *service) loadPayloads(height uint32) []*Payload {
+	store := s.Chain.GetStore()
+	if store == nil {
+		return nil
+	}
+	var res []*Payload
+	store.Seek(heightKey(height), func(k, v []byte) {
+		p := new(Payload)
+		r := io.NewBinReaderFromBuf(v)
+		p.DecodeBinary(r)
+		if r.Err == nil {
+			r.Err = p.decodeData()
+		}
+		if r.Err != nil {
+			s.log.Warn("can't decode saved consensus payload",
+				zap.Binary("key", k),
+				zap.Error(r.Err))
+			return
+		}
+		if p.Height() == height && isPersisted(p.Type()) {
+			res = append(res, p)
+		}
+	})
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].ViewNumber() != res[j].ViewNumber() {
+			return res[i].ViewNumber() < res[j].ViewNumber()
+		}
+		return typeOrder(res[i].Type()) < typeOrder(res[j].Type())
+	})
+	return res
+}
+
+// restoreState feeds own payloads saved for the current height to dBFT and
+// relays them again. It must be called before the event loop is started.
+func (s *service) restoreState() {
+	for _, p := range s.loadPayloads(s.dbft.BlockIndex) {
+		s.log.Info("restoring saved consensus payload",
+			zap.Uint32("height", p.Height()),
+			zap.Uint("view", uint(p.ViewNumber())),
+			zap.Stringer("type", p.Type()))
+
+		switch p.Type() {
+		case payload.PrepareRequestType:
+			req := p.GetPrepareRequest().(*prepareRequest)
+			s.txx.Add(&req.minerTx)
+			s.lastProposal = req.transactionHashes
+		case payload.CommitType:
+			s.lastCommit = p
+		}
+		s.cache.Add(p)
+		s.dbft.OnReceive(p)
+		if p.Type() == payload.CommitType {
+			s.setOwnCommit(p)
+		}
+		s.Config.Broadcast(p)
+	}
+}
+
+// setOwnCommit makes dBFT context hold the given commit as the one sent by
+// this node, so that it doesn't try to commit again and collects signatures
+// consistent with what was actually sent.
+func (s *service) setOwnCommit(p *Payload) {
+	if i := s.dbft.MyIndex; i >= 0 && i < len(s.dbft.CommitPayloads) {
+		s.dbft.CommitPayloads[i] = p
+	}
+}
+
+// conflictingCommit returns the commit sent earlier for the same height if
+// it differs from the given one.
+func (s *service) conflictingCommit(p *Payload) *Payload {
+	last := s.lastCommit
+	if last == nil || last.Height() != p.Height() {
+		return nil
+	}
+	if bytes.Equal(last.GetCommit().Signature(), p.GetCommit().Signature()) {
+		return nil
+	}
+	return last
+}
@@ -0,0 +1,107 @@
+package consensus
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics used in monitoring service.
+var (
+	consensusHeight = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Height of the current consensus round",
+			Name:      "consensus_height",
+			Namespace: "neogo",
+		},
+	)
+
+	consensusView = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "View number of the current consensus round",
+			Name:      "consensus_view",
+			Namespace: "neogo",
+		},
+	)
+
+	viewChanges = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of consensus view changes",
+			Name:      "consensus_view_changes_total",
+			Namespace: "neogo",
+		},
+		[]string{"reason"},
+	)
+
+	prepareRequestDelay = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Help:      "Time from the consensus round start to PrepareRequest arrival",
+			Name:      "consensus_prepare_request_delay_seconds",
+			Namespace: "neogo",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 10),
+		},
+	)
+
+	commitDelay = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Help:      "Time from the consensus round start to Commit being sent",
+			Name:      "consensus_commit_delay_seconds",
+			Namespace: "neogo",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 10),
+		},
+	)
+
+	requestedTransactions = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Help:      "Number of missing transactions requested by consensus",
+			Name:      "consensus_requested_transactions_total",
+			Namespace: "neogo",
+		},
+	)
+
+	recoveryMessages = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of consensus recovery requests and messages",
+			Name:      "consensus_recovery_messages_total",
+			Namespace: "neogo",
+		},
+		[]string{"type", "direction"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		consensusHeight,
+		consensusView,
+		viewChanges,
+		prepareRequestDelay,
+		commitDelay,
+		requestedTransactions,
+		recoveryMessages,
+	)
+}
+
+func updateRoundMetrics(height uint32, view byte) {
+	consensusHeight.Set(float64(height))
+	consensusView.Set(float64(view))
+}
+
+func updateViewChangesMetric(reason string) {
+	viewChanges.WithLabelValues(reason).Inc()
+}
+
+func updatePrepareRequestDelayMetric(d time.Duration) {
+	prepareRequestDelay.Observe(d.Seconds())
+}
+
+func updateCommitDelayMetric(d time.Duration) {
+	commitDelay.Observe(d.Seconds())
+}
+
+func updateRequestedTransactionsMetric(n int) {
+	requestedTransactions.Add(float64(n))
+}
+
+func updateRecoveryMessagesMetric(typ EventType, direction string) {
+	recoveryMessages.WithLabelValues(string(typ), direction).Inc()
+}
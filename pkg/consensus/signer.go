@@ -0,0 +1,29 @@
+package consensus
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/wallet/remote"
+	"go.uber.org/zap"
+)
+
+// newSigner creates a signer for the given wallet configuration. The wallet
+// itself is returned for local signers only. If no wallet keys can be
+// decrypted the signer has no keys, so the node works as a non-validator.
+func newSigner(cfg *wallet.Config, log *zap.Logger) (wallet.Signer, *wallet.Wallet, error) {
+	if cfg.RemoteSigner != "" {
+		return remote.NewClient(cfg.RemoteSigner, 0), nil, nil
+	}
+
+	w, err := wallet.NewWalletFromFile(cfg.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer w.Close()
+
+	s, err := wallet.NewWalletSigner(w, cfg.Password)
+	if err != nil {
+		log.Warn("can't unlock wallet keys, node can't be a validator", zap.Error(err))
+		s = wallet.NewLocalSigner()
+	}
+	return s, w, nil
+}
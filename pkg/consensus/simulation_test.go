@@ -0,0 +1,528 @@
+package consensus
+
+import (
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/dbft/timer"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	coreb "github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/internal/testchain"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+// Consensus simulation runs several consensus services over an in-memory
+// network that can drop, delay (and thus reorder) and partition messages.
+// Services don't run their event loops, the simulation processes all events
+// (message deliveries, block relays and dBFT timers) one by one in a single
+// goroutine using a virtual clock. All fault decisions are made using a
+// single random generator, its seed is logged and can be set via simSeedEnv
+// to replay a failing run exactly.
+
+const (
+	// simSeedEnv is an environment variable to set simulation seed.
+	simSeedEnv = "NEOGO_CONSENSUS_SEED"
+	// simNodes is the number of nodes in the simulation, it's the number
+	// of standby validators in unit test network.
+	simNodes = 4
+	// simTimePerBlock is a block time used by simulated nodes.
+	simTimePerBlock = 200 * time.Millisecond
+	// simWaitTimeout is the maximum virtual time to wait for some height.
+	simWaitTimeout = 100 * simTimePerBlock
+)
+
+// simEpoch is the virtual time simulation starts at.
+var simEpoch = time.Unix(1600000000, 0)
+
+type (
+	// simConfig describes network faults.
+	simConfig struct {
+		// DropRate is a probability of a consensus message to be lost.
+		DropRate float64
+		// MaxDelay is the maximum message delivery delay, every message
+		// is delayed by a random value not exceeding it.
+		MaxDelay time.Duration
+	}
+
+	// simNode is a single consensus node with its own chain.
+	simNode struct {
+		chain *core.Blockchain
+		// srv is the current service instance, it's nil for crashed
+		// nodes.
+		srv *service
+		// timer is dBFT timer of the current service instance.
+		timer *simTimer
+		// gen is incremented on every node start and crash, so that
+		// messages from the old instances are ignored.
+		gen int
+	}
+
+	// simEvent is a scheduled network event.
+	simEvent struct {
+		at  time.Duration
+		seq uint64
+		run func()
+	}
+
+	// simTimer is a dBFT timer working on the simulation clock.
+	simTimer struct {
+		net    *simNet
+		hv     timer.HV
+		at     time.Duration
+		active bool
+	}
+
+	// simNet is an in-memory network of consensus nodes.
+	simNet struct {
+		t    *testing.T
+		cfg  simConfig
+		seed int64
+
+		rand   *rand.Rand
+		nodes  []*simNode
+		groups []int
+		// now is the virtual time passed since simEpoch.
+		now time.Duration
+		// events are scheduled events ordered by time and sequence
+		// number.
+		events []simEvent
+		seq    uint64
+		// blocks are the hashes of blocks produced by nodes at each
+		// height, used to detect safety violations.
+		blocks    map[uint32]util.Uint256
+		conflicts []uint32
+	}
+)
+
+// Now implements timer.Timer interface.
+func (t *simTimer) Now() time.Time { return simEpoch.Add(t.net.now) }
+
+// Reset implements timer.Timer interface.
+func (t *simTimer) Reset(hv timer.HV, d time.Duration) {
+	t.hv = hv
+	t.at = t.net.now + d
+	t.active = true
+}
+
+// Sleep implements timer.Timer interface, it does nothing as there is
+// nothing to wait for in the simulation.
+func (t *simTimer) Sleep(time.Duration) {}
+
+// Extend implements timer.Timer interface.
+func (t *simTimer) Extend(d time.Duration) { t.at += d }
+
+// Stop implements timer.Timer interface.
+func (t *simTimer) Stop() { t.active = false }
+
+// HV implements timer.Timer interface.
+func (t *simTimer) HV() timer.HV { return t.hv }
+
+// C implements timer.Timer interface. Timer events are delivered by the
+// simulation directly, so the channel is never ready.
+func (t *simTimer) C() <-chan timer.HV { return nil }
+
+// newSimNet creates a network of simNodes nodes and starts them.
+func newSimNet(t *testing.T, cfg simConfig) *simNet {
+	seed := time.Now().UnixNano()
+	if s := os.Getenv(simSeedEnv); s != "" {
+		var err error
+		seed, err = strconv.ParseInt(s, 10, 64)
+		require.NoError(t, err)
+	}
+	t.Logf("simulation seed: %d (set %s to replay)", seed, simSeedEnv)
+
+	n := &simNet{
+		t:      t,
+		cfg:    cfg,
+		seed:   seed,
+		rand:   rand.New(rand.NewSource(seed)),
+		nodes:  make([]*simNode, simNodes),
+		groups: make([]int, simNodes),
+		blocks: make(map[uint32]util.Uint256),
+	}
+	for i := range n.nodes {
+		n.nodes[i] = &simNode{chain: newTestChain(t)}
+	}
+	for i := range n.nodes {
+		n.start(i)
+	}
+	return n
+}
+
+// start starts a new consensus service instance for the node.
+func (n *simNet) start(i int) {
+	node := n.nodes[i]
+	node.gen++
+	gen := node.gen
+
+	srv, err := NewService(Config{
+		Logger:       zaptest.NewLogger(n.t).With(zap.Int("node", i)),
+		Broadcast:    func(p *Payload) { n.broadcast(i, gen, p) },
+		RelayBlock:   func(b *coreb.Block) { n.relayBlock(i, gen, b) },
+		Chain:        node.chain,
+		RequestTx:    func(...util.Uint256) {},
+		TimePerBlock: simTimePerBlock,
+		Signer:       wallet.NewLocalSigner(testchain.PrivateKey(i)),
+	})
+	require.NoError(n.t, err)
+
+	node.srv = srv.(*service)
+	node.timer = &simTimer{net: n}
+	node.srv.dbft.Timer = node.timer
+	node.srv.start()
+	n.process(node.srv)
+}
+
+// crash stops the node, all messages sent to it are lost until restart.
+func (n *simNet) crash(i int) {
+	node := n.nodes[i]
+	node.srv = nil
+	node.timer = nil
+	node.gen++
+}
+
+// partition splits the network into the given groups, messages between
+// nodes from different groups are lost. Nodes not mentioned form a separate
+// group.
+func (n *simNet) partition(groups ...[]int) {
+	for i := range n.groups {
+		n.groups[i] = 0
+	}
+	for g := range groups {
+		for _, i := range groups[g] {
+			n.groups[i] = g + 1
+		}
+	}
+}
+
+// heal removes network partitions and synchronizes all chains.
+func (n *simNet) heal() {
+	n.partition()
+
+	var best int
+	for i := range n.nodes {
+		if n.nodes[i].chain.BlockHeight() > n.nodes[best].chain.BlockHeight() {
+			best = i
+		}
+	}
+	for i := range n.nodes {
+		if i != best {
+			n.syncNode(i, best)
+		}
+	}
+}
+
+// stop shuts all nodes down and checks safety.
+func (n *simNet) stop() {
+	for i := range n.nodes {
+		n.crash(i)
+	}
+	n.checkSafety()
+	for i := range n.nodes {
+		n.nodes[i].chain.Close()
+	}
+}
+
+// schedule adds an event to be run after the given delay.
+func (n *simNet) schedule(d time.Duration, f func()) {
+	ev := simEvent{at: n.now + d, seq: n.seq, run: f}
+	n.seq++
+	i := sort.Search(len(n.events), func(i int) bool {
+		return n.events[i].at > ev.at
+	})
+	n.events = append(n.events, simEvent{})
+	copy(n.events[i+1:], n.events[i:])
+	n.events[i] = ev
+}
+
+// step runs the next event (either a scheduled one or a timer) advancing the
+// clock. It returns false if there are no events left.
+func (n *simNet) step() bool {
+	timerNode := -1
+	for i, node := range n.nodes {
+		if node.srv != nil && node.timer.active &&
+			(timerNode < 0 || node.timer.at < n.nodes[timerNode].timer.at) {
+			timerNode = i
+		}
+	}
+	if len(n.events) != 0 && (timerNode < 0 || n.events[0].at <= n.nodes[timerNode].timer.at) {
+		ev := n.events[0]
+		n.events = n.events[1:]
+		if ev.at > n.now {
+			n.now = ev.at
+		}
+		ev.run()
+		return true
+	}
+	if timerNode < 0 {
+		return false
+	}
+	node := n.nodes[timerNode]
+	if node.timer.at > n.now {
+		n.now = node.timer.at
+	}
+	node.timer.active = false
+	node.srv.handleTimeout(node.timer.hv)
+	n.process(node.srv)
+	return true
+}
+
+// process handles all events queued by the service the way its event loop
+// does.
+func (n *simNet) process(srv *service) {
+	for {
+		select {
+		case msg := <-srv.messages:
+			srv.handleMessage(msg)
+			continue
+		default:
+		}
+		select {
+		case <-srv.blockEvents:
+			srv.handleNewBlock()
+			continue
+		default:
+		}
+		select {
+		case tx := <-srv.transactions:
+			srv.handleTransaction(tx)
+			continue
+		default:
+		}
+		return
+	}
+}
+
+// deliverable checks whether the message from one node reaches the other.
+func (n *simNet) deliverable(from, to int) bool {
+	return n.groups[from] == n.groups[to] && n.rand.Float64() >= n.cfg.DropRate
+}
+
+// delay returns random delivery delay.
+func (n *simNet) delay() time.Duration {
+	if n.cfg.MaxDelay <= 0 {
+		return 0
+	}
+	return time.Duration(n.rand.Int63n(int64(n.cfg.MaxDelay) + 1))
+}
+
+// isActive checks whether the message from the given node instance should
+// be sent.
+func (n *simNet) isActive(i int, gen int) bool {
+	return n.nodes[i].gen == gen && n.nodes[i].srv != nil
+}
+
+// broadcast sends consensus payload to all other nodes.
+func (n *simNet) broadcast(from int, gen int, p *Payload) {
+	if !n.isActive(from, gen) {
+		return
+	}
+
+	buf := io.NewBufBinWriter()
+	p.EncodeBinary(buf.BinWriter)
+	if buf.Err != nil {
+		n.t.Errorf("can't encode payload: %v", buf.Err)
+		return
+	}
+	data := buf.Bytes()
+	for to := range n.nodes {
+		if to == from || !n.deliverable(from, to) {
+			continue
+		}
+		to := to
+		n.schedule(n.delay(), func() { n.deliver(from, to, data) })
+	}
+}
+
+// deliver passes serialized payload to the node unless they're partitioned
+// or the node is down.
+func (n *simNet) deliver(from, to int, data []byte) {
+	srv := n.nodes[to].srv
+	if srv == nil || n.groups[from] != n.groups[to] {
+		return
+	}
+
+	p := new(Payload)
+	r := io.NewBinReaderFromBuf(data)
+	p.DecodeBinary(r)
+	if r.Err != nil {
+		n.t.Errorf("can't decode payload: %v", r.Err)
+		return
+	}
+	srv.OnPayload(p)
+	n.process(srv)
+}
+
+// relayBlock records the block produced by the node and sends it to all
+// other nodes. Blocks are never dropped, but they're delayed and they don't
+// cross partitions.
+func (n *simNet) relayBlock(from int, gen int, b *coreb.Block) {
+	if !n.isActive(from, gen) {
+		return
+	}
+	if h, ok := n.blocks[b.Index]; ok && !h.Equals(b.Hash()) {
+		n.conflicts = append(n.conflicts, b.Index)
+	} else {
+		n.blocks[b.Index] = b.Hash()
+	}
+	for to := range n.nodes {
+		if to == from || n.groups[from] != n.groups[to] {
+			continue
+		}
+		to := to
+		n.schedule(n.delay(), func() {
+			if n.groups[from] == n.groups[to] {
+				n.syncNode(to, from)
+			}
+		})
+	}
+}
+
+// syncNode adds blocks missing in one node's chain from the other node's
+// chain and notifies the consensus service if anything was added.
+func (n *simNet) syncNode(to, from int) {
+	dst, src := n.nodes[to], n.nodes[from]
+
+	var added bool
+	for h := dst.chain.BlockHeight() + 1; h <= src.chain.BlockHeight(); h++ {
+		b, err := src.chain.GetBlock(src.chain.GetHeaderHash(int(h)))
+		if err != nil {
+			break
+		}
+		b, err = cloneBlock(b)
+		if err != nil {
+			n.t.Errorf("can't copy block: %v", err)
+			break
+		}
+		if err := dst.chain.AddBlock(b); err != nil {
+			break
+		}
+		added = true
+	}
+	if added && dst.srv != nil {
+		dst.srv.OnNewBlock()
+		n.process(dst.srv)
+	}
+}
+
+// height returns the minimum chain height among the given nodes.
+func (n *simNet) height(nodes ...int) uint32 {
+	h := n.nodes[nodes[0]].chain.BlockHeight()
+	for _, i := range nodes[1:] {
+		if nh := n.nodes[i].chain.BlockHeight(); nh < h {
+			h = nh
+		}
+	}
+	return h
+}
+
+// runFor runs the simulation for the given virtual time.
+func (n *simNet) runFor(d time.Duration) {
+	deadline := n.now + d
+	for n.now < deadline && n.step() {
+	}
+	if n.now < deadline {
+		n.now = deadline
+	}
+}
+
+// waitHeight runs the simulation until all the given nodes reach the height.
+func (n *simNet) waitHeight(height uint32, nodes ...int) {
+	deadline := n.now + simWaitTimeout
+	for n.height(nodes...) < height && n.now < deadline && n.step() {
+	}
+	require.True(n.t, n.height(nodes...) >= height,
+		"height %d is not reached by %v (seed %d)", height, nodes, n.seed)
+	n.checkSafety()
+}
+
+// checkSafety ensures that no two different blocks were produced at the same
+// height and that all chains are the same.
+func (n *simNet) checkSafety() {
+	require.Empty(n.t, n.conflicts, "different blocks produced at these heights (seed %d)", n.seed)
+
+	all := make([]int, len(n.nodes))
+	for i := range all {
+		all[i] = i
+	}
+	for h := uint32(1); h <= n.height(all...); h++ {
+		expected := n.nodes[0].chain.GetHeaderHash(int(h))
+		for i := 1; i < len(n.nodes); i++ {
+			require.Equal(n.t, expected, n.nodes[i].chain.GetHeaderHash(int(h)),
+				"node %d has a different block at height %d (seed %d)", i, h, n.seed)
+		}
+	}
+}
+
+// cloneBlock makes a deep copy of the block, so that chains don't share it.
+func cloneBlock(b *coreb.Block) (*coreb.Block, error) {
+	buf := io.NewBufBinWriter()
+	b.EncodeBinary(buf.BinWriter)
+	if buf.Err != nil {
+		return nil, buf.Err
+	}
+
+	res := new(coreb.Block)
+	r := io.NewBinReaderFromBuf(buf.Bytes())
+	res.DecodeBinary(r)
+	return res, r.Err
+}
+
+func TestConsensusSimulation(t *testing.T) {
+	all := []int{0, 1, 2, 3}
+
+	t.Run("no faults", func(t *testing.T) {
+		n := newSimNet(t, simConfig{})
+		defer n.stop()
+
+		n.waitHeight(n.height(all...)+3, all...)
+	})
+
+	t.Run("lossy network with faulty node", func(t *testing.T) {
+		n := newSimNet(t, simConfig{
+			DropRate: 0.1,
+			MaxDelay: simTimePerBlock / 2,
+		})
+		defer n.stop()
+
+		n.crash(3)
+		n.waitHeight(n.height(0, 1, 2)+3, 0, 1, 2)
+	})
+
+	t.Run("partition", func(t *testing.T) {
+		n := newSimNet(t, simConfig{MaxDelay: simTimePerBlock / 4})
+		defer n.stop()
+
+		n.partition([]int{0, 1}, []int{2, 3})
+		h := n.height(all...)
+		// Neither of groups has enough nodes to make a block.
+		n.runFor(simTimePerBlock * 12)
+		n.checkSafety()
+		require.Equal(t, h, n.height(all...), "blocks made in partitioned network (seed %d)", n.seed)
+
+		n.heal()
+		n.waitHeight(n.height(all...)+2, all...)
+	})
+
+	t.Run("restart", func(t *testing.T) {
+		n := newSimNet(t, simConfig{MaxDelay: simTimePerBlock / 4})
+		defer n.stop()
+
+		n.waitHeight(n.height(all...)+1, all...)
+		n.crash(1)
+		n.waitHeight(n.height(0, 2, 3)+1, 0, 2, 3)
+		n.start(1)
+		n.heal()
+		n.crash(2)
+		n.waitHeight(n.height(0, 1, 3)+2, 0, 1, 3)
+	})
+}
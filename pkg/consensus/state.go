@@ -0,0 +1,207 @@
+package consensus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nspcc-dev/dbft/payload"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"go.uber.org/zap"
+)
+
+// maxRoundEvents is the maximum number of events stored for a single round.
+const maxRoundEvents = 128
+
+// EventType is a type of consensus round event.
+type EventType string
+
+// Consensus round events.
+const (
+	// EventRoundStarted is recorded when consensus for a new height
+	// starts.
+	EventRoundStarted EventType = "round_started"
+	// EventViewChanged is recorded when the view number changes.
+	EventViewChanged EventType = "view_changed"
+	// EventPrepareRequest is recorded when PrepareRequest for the current
+	// height is received.
+	EventPrepareRequest EventType = "prepare_request"
+	// EventTxRequested is recorded when missing transactions are requested
+	// from the network.
+	EventTxRequested EventType = "tx_requested"
+	// EventCommitSent is recorded when the node sends its Commit.
+	EventCommitSent EventType = "commit_sent"
+	// EventRecoveryRequest is recorded when RecoveryRequest is sent or
+	// received.
+	EventRecoveryRequest EventType = "recovery_request"
+	// EventRecoveryMessage is recorded when RecoveryMessage is sent or
+	// received.
+	EventRecoveryMessage EventType = "recovery_message"
+)
+
+// View change reasons.
+const (
+	reasonTimeout    = "timeout"
+	reasonChangeView = "change_view"
+	reasonRecovery   = "recovery"
+	reasonOther      = "other"
+)
+
+type (
+	// State is a snapshot of the consensus process state.
+	State struct {
+		Height uint32
+		View   byte
+		// Primary is the index of the primary node in Validators.
+		Primary    uint
+		Validators []ValidatorState
+		// Events are the events recorded during the current round.
+		Events []Event
+	}
+
+	// ValidatorState describes payloads received from the validator in
+	// the current round.
+	ValidatorState struct {
+		PublicKey   *keys.PublicKey
+		Preparation bool
+		Commit      bool
+		ChangeView  bool
+	}
+
+	// Event is a consensus round event.
+	Event struct {
+		Time time.Time
+		Type EventType
+		View byte
+		// Validator is the index of the validator that has sent the
+		// message, it's -1 for local events.
+		Validator int
+		// Details contains event-specific information like view change
+		// reason or the number of requested transactions.
+		Details string
+	}
+)
+
+// recordEvent adds the event to the current round's list and logs it.
+func (s *service) recordEvent(typ EventType, validator int, details string) {
+	s.stateLock.Lock()
+	ev := Event{
+		Time:      time.Now(),
+		Type:      typ,
+		View:      s.state.View,
+		Validator: validator,
+		Details:   details,
+	}
+	if len(s.state.Events) < maxRoundEvents {
+		s.state.Events = append(s.state.Events, ev)
+	}
+	height := s.state.Height
+	s.stateLock.Unlock()
+
+	s.log.Info("consensus event",
+		zap.Uint32("height", height),
+		zap.Uint("view", uint(ev.View)),
+		zap.String("type", string(typ)),
+		zap.Int("validator", validator),
+		zap.String("details", details))
+}
+
+// sinceRoundStart returns the time passed since the current round start.
+func (s *service) sinceRoundStart() time.Duration {
+	s.stateLock.RLock()
+	defer s.stateLock.RUnlock()
+	return time.Since(s.roundStart)
+}
+
+// onReceiveEvent records events for the message that is about to be
+// processed by dBFT.
+func (s *service) onReceiveEvent(msg *Payload) {
+	if msg.Height() != s.dbft.BlockIndex {
+		return
+	}
+	switch msg.Type() {
+	case payload.PrepareRequestType:
+		d := s.sinceRoundStart()
+		updatePrepareRequestDelayMetric(d)
+		s.recordEvent(EventPrepareRequest, int(msg.ValidatorIndex()), fmt.Sprintf("delay %s", d))
+	case payload.RecoveryRequestType:
+		updateRecoveryMessagesMetric(EventRecoveryRequest, "received")
+		s.recordEvent(EventRecoveryRequest, int(msg.ValidatorIndex()), "received")
+	case payload.RecoveryMessageType:
+		updateRecoveryMessagesMetric(EventRecoveryMessage, "received")
+		s.recordEvent(EventRecoveryMessage, int(msg.ValidatorIndex()), "received")
+	}
+}
+
+// onBroadcastEvent records events for the message sent by this node.
+func (s *service) onBroadcastEvent(p *Payload) {
+	switch p.Type() {
+	case payload.CommitType:
+		d := s.sinceRoundStart()
+		updateCommitDelayMetric(d)
+		s.recordEvent(EventCommitSent, -1, fmt.Sprintf("delay %s", d))
+	case payload.RecoveryRequestType:
+		updateRecoveryMessagesMetric(EventRecoveryRequest, "sent")
+		s.recordEvent(EventRecoveryRequest, -1, "sent")
+	case payload.RecoveryMessageType:
+		updateRecoveryMessagesMetric(EventRecoveryMessage, "sent")
+		s.recordEvent(EventRecoveryMessage, -1, "sent")
+	}
+}
+
+// updateState refreshes the state snapshot after dBFT has processed some
+// event, reason is used for view changes caused by it.
+func (s *service) updateState(reason string) {
+	var (
+		newRound   bool
+		viewChange bool
+	)
+
+	s.stateLock.Lock()
+	if s.dbft.BlockIndex != s.state.Height {
+		newRound = true
+		s.roundStart = time.Now()
+		s.state.Events = nil
+	} else if s.dbft.ViewNumber != s.state.View {
+		viewChange = true
+	}
+	oldView := s.state.View
+	s.state.Height = s.dbft.BlockIndex
+	s.state.View = s.dbft.ViewNumber
+	s.state.Primary = s.dbft.PrimaryIndex
+	s.state.Validators = make([]ValidatorState, len(s.dbft.Validators))
+	for i, pub := range s.dbft.Validators {
+		vs := &s.state.Validators[i]
+		vs.PublicKey = pub.(*publicKey).PublicKey
+		if p := s.dbft.PreparationPayloads[i]; p != nil && p.ViewNumber() == s.dbft.ViewNumber {
+			vs.Preparation = true
+		}
+		if p := s.dbft.CommitPayloads[i]; p != nil && p.ViewNumber() == s.dbft.ViewNumber {
+			vs.Commit = true
+		}
+		if p := s.dbft.ChangeViewPayloads[i]; p != nil && p.GetChangeView().NewViewNumber() > s.dbft.ViewNumber {
+			vs.ChangeView = true
+		}
+	}
+	s.stateLock.Unlock()
+
+	updateRoundMetrics(s.dbft.BlockIndex, s.dbft.ViewNumber)
+	if newRound {
+		s.recordEvent(EventRoundStarted, -1, "")
+	} else if viewChange {
+		updateViewChangesMetric(reason)
+		s.recordEvent(EventViewChanged, -1, fmt.Sprintf("from view %d, reason: %s", oldView, reason))
+	}
+}
+
+// GetState implements Service interface.
+func (s *service) GetState() *State {
+	if s.dbft == nil || !s.started.Load() {
+		return nil
+	}
+	s.stateLock.RLock()
+	defer s.stateLock.RUnlock()
+	st := s.state
+	st.Validators = append([]ValidatorState(nil), s.state.Validators...)
+	st.Events = append([]Event(nil), s.state.Events...)
+	return &st
+}
@@ -41,6 +41,8 @@ const (
 	registeredAssetLifetime = 2 * 2000000
 
 	defaultMemPoolSize = 50000
+
+	defaultObjectCacheSize = 10000
 )
 
 var (
@@ -54,6 +56,12 @@ var (
 	// ErrPolicy is returned on attempt to add transaction that doesn't
 	// comply with node's configured policy into the mempool.
 	ErrPolicy = errors.New("not allowed by policy")
+	// ErrPruned is returned when requested application execution result
+	// was removed by pruning.
+	ErrPruned = errors.New("data is pruned")
+	// ErrReadOnly is returned on attempt to add blocks, headers or
+	// transactions to the secondary (read-only) Blockchain.
+	ErrReadOnly = errors.New("blockchain is read-only")
 	// ErrInvalidBlockIndex is returned when trying to add block with index
 	// other than expected height of the blockchain.
 	ErrInvalidBlockIndex error = errors.New("invalid block index")
@@ -83,6 +91,8 @@ type Blockchain struct {
 
 	// Data access object for CRUD operations around storage.
 	dao *dao.Simple
+	// Underlying persistent storage.
+	store storage.Store
 
 	// Current index/height of the highest block.
 	// Read access should always be called by BlockHeight().
@@ -125,6 +135,10 @@ type Blockchain struct {
 	lastBatch *storage.MemBatch
 
 	contracts native.Contracts
+
+	// readOnly is set for the secondary Blockchain following changes made
+	// to the store by some other node.
+	readOnly bool
 }
 
 type headersOpFunc func(headerList *HeaderHashList)
@@ -132,6 +146,18 @@ type headersOpFunc func(headerList *HeaderHashList)
 // NewBlockchain returns a new blockchain object the will use the
 // given Store as its underlying storage.
 func NewBlockchain(s storage.Store, cfg config.ProtocolConfiguration, log *zap.Logger) (*Blockchain, error) {
+	return newBlockchain(s, cfg, log, false)
+}
+
+// NewSecondaryBlockchain returns a new read-only blockchain object for the
+// Store that is written to by some other (primary) node. It doesn't accept
+// blocks or transactions, but follows the chain changes made to the Store by
+// the primary node. The Store must already contain the chain.
+func NewSecondaryBlockchain(s storage.Store, cfg config.ProtocolConfiguration, log *zap.Logger) (*Blockchain, error) {
+	return newBlockchain(s, cfg, log, true)
+}
+
+func newBlockchain(s storage.Store, cfg config.ProtocolConfiguration, log *zap.Logger, readOnly bool) (*Blockchain, error) {
 	if log == nil {
 		return nil, errors.New("empty logger")
 	}
@@ -140,6 +166,10 @@ func NewBlockchain(s storage.Store, cfg config.ProtocolConfiguration, log *zap.L
 		cfg.MemPoolSize = defaultMemPoolSize
 		log.Info("mempool size is not set or wrong, setting default value", zap.Int("MemPoolSize", cfg.MemPoolSize))
 	}
+	if cfg.ObjectCacheSize <= 0 {
+		cfg.ObjectCacheSize = defaultObjectCacheSize
+		log.Info("object cache size is not set or wrong, setting default value", zap.Int("ObjectCacheSize", cfg.ObjectCacheSize))
+	}
 	if cfg.MaxTransactionsPerBlock <= 0 {
 		cfg.MaxTransactionsPerBlock = 0
 		log.Info("MaxTransactionsPerBlock is not set or wrong, setting default value (unlimited)", zap.Int("MaxTransactionsPerBlock", cfg.MaxTransactionsPerBlock))
@@ -158,7 +188,8 @@ func NewBlockchain(s storage.Store, cfg config.ProtocolConfiguration, log *zap.L
 	}
 	bc := &Blockchain{
 		config:        cfg,
-		dao:           dao.NewSimple(s),
+		dao:           dao.NewSimpleWithObjectCache(storage.NewInstrumentedStore(s), cfg.ObjectCacheSize),
+		store:         s,
 		headersOp:     make(chan headersOpFunc),
 		headersOpDone: make(chan struct{}),
 		stopCh:        make(chan struct{}),
@@ -171,6 +202,7 @@ func NewBlockchain(s storage.Store, cfg config.ProtocolConfiguration, log *zap.L
 		decrementInterval: decrementInterval,
 
 		contracts: *native.NewContracts(),
+		readOnly:  readOnly,
 	}
 
 	if err := bc.init(); err != nil {
@@ -183,6 +215,9 @@ func NewBlockchain(s storage.Store, cfg config.ProtocolConfiguration, log *zap.L
 func (bc *Blockchain) init() error {
 	// If we could not find the version in the Store, we know that there is nothing stored.
 	ver, err := bc.dao.GetVersion()
+	if err != nil && bc.readOnly {
+		return errors.New("no chain found in the storage, it must be initialized by the primary node")
+	}
 	if err != nil {
 		bc.log.Info("no storage version found! creating genesis block")
 		if err = bc.dao.PutVersion(version); err != nil {
@@ -218,58 +253,66 @@ func (bc *Blockchain) init() error {
 	bc.blockHeight = bHeight
 	bc.persistedHeight = bHeight
 
+	bc.headerList, bc.storedHeaderCount, err = bc.loadHeaderHashList(true)
+	return err
+}
+
+// loadHeaderHashList reads header hash list from the store, it also returns
+// the number of hashes stored in batches (the rest is restored from headers
+// following the last stored hash up to the current header). Headers read are
+// verified if verify is set.
+func (bc *Blockchain) loadHeaderHashList(verify bool) (*HeaderHashList, uint32, error) {
 	hashes, err := bc.dao.GetHeaderHashes()
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
 
-	bc.headerList = NewHeaderHashList(hashes...)
-	bc.storedHeaderCount = uint32(len(hashes))
+	headerList := NewHeaderHashList(hashes...)
+	storedHeaderCount := uint32(len(hashes))
 
 	currHeaderHeight, currHeaderHash, err := bc.dao.GetCurrentHeaderHeight()
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
-	if bc.storedHeaderCount == 0 && currHeaderHeight == 0 {
-		bc.headerList.Add(currHeaderHash)
+	if storedHeaderCount == 0 && currHeaderHeight == 0 {
+		headerList.Add(currHeaderHash)
 	}
 
 	// There is a high chance that the Node is stopped before the next
 	// batch of 2000 headers was stored. Via the currentHeaders stored we can sync
 	// that with stored blocks.
-	if currHeaderHeight >= bc.storedHeaderCount {
+	if currHeaderHeight >= storedHeaderCount {
 		hash := currHeaderHash
 		var targetHash util.Uint256
-		if bc.headerList.Len() > 0 {
-			targetHash = bc.headerList.Get(bc.headerList.Len() - 1)
+		if headerList.Len() > 0 {
+			targetHash = headerList.Get(headerList.Len() - 1)
 		} else {
 			genesisBlock, err := createGenesisBlock(bc.config)
 			if err != nil {
-				return err
+				return nil, 0, err
 			}
 			targetHash = genesisBlock.Hash()
-			bc.headerList.Add(targetHash)
+			headerList.Add(targetHash)
 		}
 		headers := make([]*block.Header, 0)
 
 		for hash != targetHash {
 			header, err := bc.GetHeader(hash)
 			if err != nil {
-				return fmt.Errorf("could not get header %s: %s", hash, err)
+				return nil, 0, fmt.Errorf("could not get header %s: %s", hash, err)
 			}
 			headers = append(headers, header)
 			hash = header.PrevHash
 		}
 		headerSliceReverse(headers)
 		for _, h := range headers {
-			if !h.Verify() {
-				return fmt.Errorf("bad header %d/%s in the storage", h.Index, h.Hash())
+			if verify && !h.Verify() {
+				return nil, 0, fmt.Errorf("bad header %d/%s in the storage", h.Index, h.Hash())
 			}
-			bc.headerList.Add(h.Hash())
+			headerList.Add(h.Hash())
 		}
 	}
-
-	return nil
+	return headerList, storedHeaderCount, nil
 }
 
 func (bc *Blockchain) initNative() error {
@@ -314,6 +357,13 @@ func (bc *Blockchain) Run() {
 			op(bc.headerList)
 			bc.headersOpDone <- struct{}{}
 		case <-persistTimer.C:
+			if bc.readOnly {
+				if err := bc.syncWithStore(); err != nil {
+					bc.log.Warn("failed to sync with the storage", zap.Error(err))
+				}
+				persistTimer.Reset(persistInterval)
+				continue
+			}
 			go func() {
 				err := bc.persist()
 				if err != nil {
@@ -325,6 +375,71 @@ func (bc *Blockchain) Run() {
 	}
 }
 
+// syncWithStore updates the secondary Blockchain in-memory state (block height
+// and header list) to match the store changed by the primary node. It must be
+// called from the Run loop as it changes the header list.
+func (bc *Blockchain) syncWithStore() error {
+	height, err := bc.dao.GetCurrentBlockHeight()
+	if err != nil {
+		return err
+	}
+	hdrHeight, hdrHash, err := bc.dao.GetCurrentHeaderHeight()
+	if err != nil {
+		return err
+	}
+	var (
+		oldHdrHeight = uint32(bc.headerList.Len() - 1)
+		last         = bc.headerList.Last()
+		hashes       []util.Uint256
+	)
+	if height == bc.BlockHeight() && hdrHeight == oldHdrHeight && hdrHash.Equals(last) {
+		return nil
+	}
+	// Cached objects could be changed by the primary node.
+	bc.dao.PurgeObjectCache()
+	if hdrHeight > oldHdrHeight {
+		// Usually there are just some new headers following the known
+		// ones.
+		hash := hdrHash
+		for i := hdrHeight; i > oldHdrHeight; i-- {
+			hashes = append(hashes, hash)
+			header, _, err := bc.dao.GetBlock(hash)
+			if err != nil {
+				return fmt.Errorf("could not get header %s: %s", hash, err)
+			}
+			hash = header.PrevHash
+		}
+		if !hash.Equals(last) {
+			hashes = nil
+		}
+	}
+	if hashes != nil {
+		for i := len(hashes) - 1; i >= 0; i-- {
+			bc.headerList.Add(hashes[i])
+		}
+	} else if hdrHeight != oldHdrHeight || !hdrHash.Equals(last) {
+		// The chain was rolled back, so the list is reread.
+		headerList, storedHeaderCount, err := bc.loadHeaderHashList(false)
+		if err != nil {
+			return err
+		}
+		bc.headerList = headerList
+		bc.storedHeaderCount = storedHeaderCount
+	}
+	updateHeaderHeightMetric(bc.headerList.Len() - 1)
+
+	top, err := bc.GetBlock(bc.headerList.Get(int(height)))
+	if err != nil {
+		return err
+	}
+	bc.topBlock.Store(top)
+	atomic.StoreUint32(&bc.persistedHeight, height)
+	atomic.StoreUint32(&bc.blockHeight, height)
+	updateBlockHeightMetric(height)
+	updatePersistedHeightMetric(height)
+	return nil
+}
+
 // Close stops Blockchain's internal loop, syncs changes to persistent storage
 // and closes it. The Blockchain is no longer functional after the call to Close.
 func (bc *Blockchain) Close() {
@@ -335,6 +450,9 @@ func (bc *Blockchain) Close() {
 // AddBlock accepts successive block for the Blockchain, verifies it and
 // stores internally. Eventually it will be persisted to the backing storage.
 func (bc *Blockchain) AddBlock(block *block.Block) error {
+	if bc.readOnly {
+		return ErrReadOnly
+	}
 	bc.addLock.Lock()
 	defer bc.addLock.Unlock()
 
@@ -376,6 +494,9 @@ func (bc *Blockchain) AddHeaders(headers ...*block.Header) error {
 // addHeaders is an internal implementation of AddHeaders (`verify` parameter
 // tells it to verify or not verify given headers).
 func (bc *Blockchain) addHeaders(verify bool, headers ...*block.Header) (err error) {
+	if bc.readOnly {
+		return ErrReadOnly
+	}
 	var (
 		start = time.Now()
 		batch = bc.dao.Store.Batch()
@@ -727,6 +848,11 @@ func (bc *Blockchain) storeBlock(block *block.Block) error {
 			}
 		}
 	}
+	if n := bc.config.PruneAppLogs; n > 0 && block.Index >= n {
+		if err := cache.PruneAppExecResults(bc.GetHeaderHash(int(block.Index - n))); err != nil {
+			return errors.Wrap(err, "failed to prune application logs")
+		}
+	}
 	bc.lock.Lock()
 	defer bc.lock.Unlock()
 
@@ -741,7 +867,15 @@ func (bc *Blockchain) storeBlock(block *block.Block) error {
 		}
 	}
 
-	_, err := cache.Persist()
+	var err error
+	if n := bc.config.RollbackDepth; n > 0 {
+		_, err = cache.PersistWithUndo(block.Index)
+		if err == nil && block.Index >= n {
+			err = bc.dao.DeleteUndo(block.Index - n)
+		}
+	} else {
+		_, err = cache.Persist()
+	}
 	if err != nil {
 		return err
 	}
@@ -818,13 +952,19 @@ func (bc *Blockchain) processNEP5Transfer(cache *dao.Cached, tx *transaction.Tra
 
 // GetNEP5TransferLog returns NEP5 transfer log for the acc.
 func (bc *Blockchain) GetNEP5TransferLog(acc util.Uint160) *state.NEP5TransferLog {
-	balances, err := bc.dao.GetNEP5Balances(acc)
+	return getNEP5TransferLog(bc.dao, acc)
+}
+
+// getNEP5TransferLog returns the full NEP5 transfer log for the account using
+// the given DAO.
+func getNEP5TransferLog(d dao.DAO, acc util.Uint160) *state.NEP5TransferLog {
+	balances, err := d.GetNEP5Balances(acc)
 	if err != nil {
 		return nil
 	}
 	result := new(state.NEP5TransferLog)
 	for i := uint32(0); i <= balances.NextTransferBatch; i++ {
-		lg, err := bc.dao.GetNEP5TransferLog(acc, i)
+		lg, err := d.GetNEP5TransferLog(acc, i)
 		if err != nil {
 			return nil
 		}
@@ -999,9 +1139,18 @@ func (bc *Blockchain) GetTransaction(hash util.Uint256) (*transaction.Transactio
 }
 
 // GetAppExecResult returns application execution result by the given
-// tx hash.
+// tx hash. ErrPruned is returned for old invocation transactions in pruning
+// mode.
 func (bc *Blockchain) GetAppExecResult(hash util.Uint256) (*state.AppExecResult, error) {
-	return bc.dao.GetAppExecResult(hash)
+	aer, err := bc.dao.GetAppExecResult(hash)
+	if err == storage.ErrKeyNotFound && bc.config.PruneAppLogs > 0 {
+		tx, height, txErr := bc.dao.GetTransaction(hash)
+		if txErr == nil && tx.Type == transaction.InvocationType &&
+			height+bc.config.PruneAppLogs <= bc.BlockHeight() {
+			return nil, ErrPruned
+		}
+	}
+	return aer, err
 }
 
 // GetStorageItem returns an item from storage.
@@ -1278,6 +1427,13 @@ func (bc *Blockchain) GetMemPool() *mempool.Pool {
 	return &bc.memPool
 }
 
+// GetStore returns the persistent storage of the blockchain. It can be used
+// to save node-specific data that is not a part of the chain state, keys of
+// such data must not clash with the ones used by the chain.
+func (bc *Blockchain) GetStore() storage.Store {
+	return bc.store
+}
+
 // ApplyPolicyToTxSet applies configured policies to given transaction set. It
 // expects slice to be ordered by fee and returns a subslice of it.
 func (bc *Blockchain) ApplyPolicyToTxSet(txes []mempool.TxWithFee) []mempool.TxWithFee {
@@ -1541,6 +1697,9 @@ func (bc *Blockchain) VerifyTx(t *transaction.Transaction, block *block.Block) e
 
 // PoolTx verifies and tries to add given transaction into the mempool.
 func (bc *Blockchain) PoolTx(t *transaction.Transaction) error {
+	if bc.readOnly {
+		return ErrReadOnly
+	}
 	bc.lock.RLock()
 	defer bc.lock.RUnlock()
 
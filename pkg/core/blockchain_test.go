@@ -3,11 +3,15 @@ package core
 import (
 	"testing"
 
+	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -217,6 +221,61 @@ func TestGetClaimable(t *testing.T) {
 	})
 }
 
+func TestPruneAppLogs(t *testing.T) {
+	bc := newTestChainWithCustomCfg(t, func(cfg *config.ProtocolConfiguration) {
+		cfg.PruneAppLogs = 2
+	})
+	defer bc.Close()
+
+	minerTx := transaction.NewMinerTX()
+	minerTx.ValidUntilBlock = bc.BlockHeight() + 1
+	tx := transaction.NewInvocationTX([]byte{byte(opcode.PUSH1)}, 0)
+	tx.ValidUntilBlock = bc.BlockHeight() + 1
+	require.NoError(t, addSender(minerTx, tx))
+	require.NoError(t, signTx(bc, minerTx, tx))
+	b := bc.newBlock(minerTx, tx)
+	require.NoError(t, bc.AddBlock(b))
+	_, err := bc.GetAppExecResult(tx.Hash())
+	require.NoError(t, err)
+
+	_, err = bc.genBlocks(2)
+	require.NoError(t, err)
+	_, err = bc.GetAppExecResult(tx.Hash())
+	require.Equal(t, ErrPruned, err)
+	// Other transactions never had application logs.
+	_, err = bc.GetAppExecResult(minerTx.Hash())
+	require.Equal(t, storage.ErrKeyNotFound, err)
+
+	// Everything accessible to contracts is still there.
+	gotTx, height, err := bc.GetTransaction(tx.Hash())
+	require.NoError(t, err)
+	require.Equal(t, tx.Hash(), gotTx.Hash())
+	require.Equal(t, b.Index, height)
+	gotBlock, err := bc.GetBlock(b.Hash())
+	require.NoError(t, err)
+	require.Equal(t, 2, len(gotBlock.Transactions))
+
+	w := io.NewBufBinWriter()
+	emit.Bytes(w.BinWriter, tx.Hash().BytesBE())
+	emit.Syscall(w.BinWriter, "Neo.Blockchain.GetTransaction")
+	emit.Syscall(w.BinWriter, "Neo.Transaction.GetHash")
+	emit.Int(w.BinWriter, int64(b.Index))
+	emit.Syscall(w.BinWriter, "Neo.Blockchain.GetBlock")
+	emit.Syscall(w.BinWriter, "Neo.Block.GetTransactionCount")
+	require.NoError(t, w.Err)
+
+	v := bc.GetTestVM()
+	v.LoadScript(w.Bytes())
+	require.NoError(t, v.Run())
+	require.Equal(t, 2, v.Estack().Len())
+	require.EqualValues(t, 2, v.Estack().Pop().BigInt().Int64())
+	require.Equal(t, tx.Hash().BytesBE(), v.Estack().Pop().Bytes())
+
+	var problems []error
+	require.NoError(t, CheckStore(bc.dao.Store, func(err error) { problems = append(problems, err) }, nil))
+	require.Empty(t, problems)
+}
+
 func TestClose(t *testing.T) {
 	defer func() {
 		r := recover()
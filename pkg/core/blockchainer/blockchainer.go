@@ -5,6 +5,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/mempool"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -41,6 +42,7 @@ type Blockchainer interface {
 	GetScriptHashesForVerifying(*transaction.Transaction) ([]util.Uint160, error)
 	GetStorageItem(scripthash util.Uint160, key []byte) *state.StorageItem
 	GetStorageItems(hash util.Uint160) (map[string]*state.StorageItem, error)
+	GetStateView() (StateView, error)
 	GetTestVM() *vm.VM
 	GetTransaction(util.Uint256) (*transaction.Transaction, uint32, error)
 	GetUnspentCoinState(util.Uint256) *state.UnspentCoin
@@ -49,4 +51,19 @@ type Blockchainer interface {
 	PoolTx(*transaction.Transaction) error
 	VerifyTx(*transaction.Transaction, *block.Block) error
 	GetMemPool() *mempool.Pool
+	GetStore() storage.Store
+}
+
+// StateView is a read-only view of the chain state at some block height, all
+// reads made via it are consistent with each other. It must be released after
+// use.
+type StateView interface {
+	GetAccountState(util.Uint160) *state.Account
+	GetAssetState(util.Uint256) *state.Asset
+	GetNEP5Balances(util.Uint160) *state.NEP5Balances
+	GetNEP5TransferLog(util.Uint160) *state.NEP5TransferLog
+	GetStorageItem(scripthash util.Uint160, key []byte) *state.StorageItem
+	GetUnspentCoinState(util.Uint256) *state.UnspentCoin
+	GetTestVM() *vm.VM
+	Release()
 }
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/dao"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// CheckStore verifies the chain stored in s walking from the current block
+// back to genesis. It checks that blocks are linked properly and match stored
+// header hash list, that all block transactions are present and that their
+// UTXO states agree with the chain. Every inconsistency found is passed to
+// onProblem, the error returned means that the walk can't be completed.
+// progress (if not nil) is called with the index of every block checked.
+func CheckStore(s storage.Store, onProblem func(error), progress func(uint32)) error {
+	d := dao.NewSimple(s)
+	b, err := s.Get(storage.SYSCurrentBlock.Bytes())
+	if err != nil {
+		return fmt.Errorf("can't get current block: %v", err)
+	}
+	if len(b) < 36 {
+		return fmt.Errorf("invalid current block record length %d", len(b))
+	}
+	hash, err := util.Uint256DecodeBytesBE(b[:32])
+	if err != nil {
+		return err
+	}
+	height := binary.LittleEndian.Uint32(b[32:36])
+
+	hdrHeight, _, err := d.GetCurrentHeaderHeight()
+	if err != nil {
+		return fmt.Errorf("can't get current header: %v", err)
+	}
+	if hdrHeight < height {
+		onProblem(fmt.Errorf("current header height %d is lower than block height %d", hdrHeight, height))
+	}
+	hashes, err := d.GetHeaderHashes()
+	if err != nil {
+		return fmt.Errorf("can't get header hash list: %v", err)
+	}
+
+	for i := int64(height); i >= 0; i-- {
+		index := uint32(i)
+		blk, _, err := d.GetBlock(hash)
+		if err != nil {
+			return fmt.Errorf("can't get block %d (%s): %v", index, hash.StringLE(), err)
+		}
+		if blk.Index != index {
+			return fmt.Errorf("block %s has index %d, expected %d", hash.StringLE(), blk.Index, index)
+		}
+		if int(index) < len(hashes) && !hashes[index].Equals(hash) {
+			onProblem(fmt.Errorf("header hash list has %s for block %d, expected %s",
+				hashes[index].StringLE(), index, hash.StringLE()))
+		}
+		for _, t := range blk.Transactions {
+			checkTransaction(d, t.Hash(), index, onProblem)
+		}
+		if progress != nil {
+			progress(index)
+		}
+		hash = blk.PrevHash
+	}
+	return nil
+}
+
+// checkTransaction verifies transaction presence and the state of its outputs
+// and inputs.
+func checkTransaction(d *dao.Simple, h util.Uint256, index uint32, onProblem func(error)) {
+	tx, txHeight, err := d.GetTransaction(h)
+	if err != nil {
+		onProblem(fmt.Errorf("can't get transaction %s from block %d: %v", h.StringLE(), index, err))
+		return
+	}
+	if txHeight != index {
+		onProblem(fmt.Errorf("transaction %s is stored with height %d, expected %d", h.StringLE(), txHeight, index))
+	}
+
+	unspent, err := d.GetUnspentCoinState(h)
+	if err != nil {
+		onProblem(fmt.Errorf("can't get coin state for transaction %s: %v", h.StringLE(), err))
+	} else if unspent.Height != index || len(unspent.States) != len(tx.Outputs) {
+		onProblem(fmt.Errorf("coin state for transaction %s doesn't match it", h.StringLE()))
+	} else {
+		for i := range tx.Outputs {
+			out := &unspent.States[i].Output
+			if !out.AssetID.Equals(tx.Outputs[i].AssetID) || out.Amount != tx.Outputs[i].Amount ||
+				!out.ScriptHash.Equals(tx.Outputs[i].ScriptHash) {
+				onProblem(fmt.Errorf("coin state for output %s/%d doesn't match it", h.StringLE(), i))
+			}
+		}
+	}
+
+	for _, inputs := range transaction.GroupInputsByPrevHash(tx.Inputs) {
+		prevHash := inputs[0].PrevHash
+		prev, err := d.GetUnspentCoinState(prevHash)
+		if err != nil {
+			onProblem(fmt.Errorf("can't get coin state for transaction %s spent by %s: %v",
+				prevHash.StringLE(), h.StringLE(), err))
+			continue
+		}
+		for _, in := range inputs {
+			if int(in.PrevIndex) >= len(prev.States) {
+				onProblem(fmt.Errorf("transaction %s spends non-existent output %s/%d",
+					h.StringLE(), prevHash.StringLE(), in.PrevIndex))
+				continue
+			}
+			st := prev.States[in.PrevIndex]
+			if st.State&state.CoinSpent == 0 || st.SpendHeight != index {
+				onProblem(fmt.Errorf("output %s/%d spent by %s in block %d is not marked as such",
+					prevHash.StringLE(), in.PrevIndex, h.StringLE(), index))
+			}
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckStore(t *testing.T) {
+	bc := newTestChain(t)
+	defer bc.Close()
+	blocks, err := bc.genBlocks(5)
+	require.NoError(t, err)
+
+	var problems []error
+	var checked int
+	onProblem := func(err error) { problems = append(problems, err) }
+	require.NoError(t, CheckStore(bc.dao.Store, onProblem, func(uint32) { checked++ }))
+	require.Empty(t, problems)
+	require.Equal(t, 6, checked)
+
+	tx := blocks[2].Transactions[0]
+	require.NoError(t, bc.dao.Store.Delete(storage.AppendPrefix(storage.DataTransaction, tx.Hash().BytesLE())))
+	require.NoError(t, CheckStore(bc.dao.Store, onProblem, nil))
+	require.Equal(t, 1, len(problems))
+
+	require.NoError(t, bc.dao.Store.Delete(storage.AppendPrefix(storage.DataBlock, blocks[1].Hash().BytesLE())))
+	require.Error(t, CheckStore(bc.dao.Store, onProblem, nil))
+}
@@ -158,26 +158,48 @@ func (cd *Cached) Persist() (int, error) {
 		}
 		return simpleCache.Persist()
 	}
+	if err := cd.flushObjects(); err != nil {
+		return 0, err
+	}
+	return cd.DAO.Persist()
+}
+
+// PersistWithUndo is the same as Persist, but it also stores the data needed
+// to revert the changes made under the given block index (see
+// Simple.PersistWithUndo). The lower DAO must be Simple.
+func (cd *Cached) PersistWithUndo(index uint32) (int, error) {
+	simple, ok := cd.DAO.(*Simple)
+	if !ok {
+		return 0, errors.New("unsupported lower DAO")
+	}
+	if err := cd.flushObjects(); err != nil {
+		return 0, err
+	}
+	return simple.PersistWithUndo(index)
+}
+
+// flushObjects puts all cached objects into the lower DAO.
+func (cd *Cached) flushObjects() error {
 	buf := io.NewBufBinWriter()
 
 	for sc := range cd.accounts {
 		err := cd.DAO.putAccountState(cd.accounts[sc], buf)
 		if err != nil {
-			return 0, err
+			return err
 		}
 		buf.Reset()
 	}
 	for hash := range cd.unspents {
 		err := cd.DAO.putUnspentCoinState(hash, cd.unspents[hash], buf)
 		if err != nil {
-			return 0, err
+			return err
 		}
 		buf.Reset()
 	}
 	for acc, bs := range cd.balances {
 		err := cd.DAO.putNEP5Balances(acc, bs, buf)
 		if err != nil {
-			return 0, err
+			return err
 		}
 		buf.Reset()
 	}
@@ -185,11 +207,11 @@ func (cd *Cached) Persist() (int, error) {
 		for ind, lg := range ts {
 			err := cd.DAO.PutNEP5TransferLog(acc, ind, lg)
 			if err != nil {
-				return 0, err
+				return err
 			}
 		}
 	}
-	return cd.DAO.Persist()
+	return nil
 }
 
 // GetWrapped implements DAO interface.
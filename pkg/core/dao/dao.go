@@ -64,6 +64,7 @@ type DAO interface {
 	PutValidatorState(vs *state.Validator) error
 	PutValidatorsCount(vc *state.ValidatorsCount) error
 	PutVersion(v string) error
+	PruneAppExecResults(hash util.Uint256) error
 	StoreAsBlock(block *block.Block, sysFee uint32) error
 	StoreAsCurrentBlock(block *block.Block) error
 	StoreAsTransaction(tx *transaction.Transaction, index uint32) error
@@ -75,6 +76,12 @@ type DAO interface {
 // Simple is memCached wrapper around DB, simple DAO implementation.
 type Simple struct {
 	Store *storage.MemCachedStore
+
+	// cache is a decoded object cache for Store contents (only used by
+	// the lowest DAO).
+	cache *objectCache
+	// parent is a DAO this one was wrapped from.
+	parent *Simple
 }
 
 // NewSimple creates new simple dao using provided backend store.
@@ -82,6 +89,24 @@ func NewSimple(backend storage.Store) *Simple {
 	return &Simple{Store: storage.NewMemCachedStore(backend)}
 }
 
+// NewSimpleWithObjectCache creates new simple dao using provided backend store
+// and keeping up to cacheSize most recently used accounts, assets and
+// contracts decoded. All changes made to its Store must go through the DAO
+// methods or be followed by PurgeObjectCache call.
+func NewSimpleWithObjectCache(backend storage.Store, cacheSize int) *Simple {
+	dao := NewSimple(backend)
+	dao.cache = newObjectCache(cacheSize)
+	return dao
+}
+
+// PurgeObjectCache drops all decoded objects cached (if any), it's needed
+// when the backend store is changed not via this DAO.
+func (dao *Simple) PurgeObjectCache() {
+	if dao.cache != nil {
+		dao.cache.purge()
+	}
+}
+
 // GetBatch returns currently accumulated DB changeset.
 func (dao *Simple) GetBatch() *storage.MemBatch {
 	return dao.Store.GetBatch()
@@ -90,7 +115,9 @@ func (dao *Simple) GetBatch() *storage.MemBatch {
 // GetWrapped returns new DAO instance with another layer of wrapped
 // MemCachedStore around the current DAO Store.
 func (dao *Simple) GetWrapped() DAO {
-	return NewSimple(dao.Store)
+	d := NewSimple(dao.Store)
+	d.parent = dao
+	return d
 }
 
 // GetAndDecode performs get operation and decoding with serializable structures.
@@ -99,7 +126,11 @@ func (dao *Simple) GetAndDecode(entity io.Serializable, key []byte) error {
 	if err != nil {
 		return err
 	}
-	reader := io.NewBinReaderFromBuf(entityBytes)
+	return decodeBytes(entity, entityBytes)
+}
+
+func decodeBytes(entity io.Serializable, b []byte) error {
+	reader := io.NewBinReaderFromBuf(b)
 	entity.DecodeBinary(reader)
 	return reader.Err
 }
@@ -115,7 +146,59 @@ func (dao *Simple) putWithBuffer(entity io.Serializable, key []byte, buf *io.Buf
 	if buf.Err != nil {
 		return buf.Err
 	}
-	return dao.Store.Put(key, buf.Bytes())
+	return dao.putBytes(key, buf.Bytes())
+}
+
+// putBytes puts the value into the Store invalidating cached object for it.
+func (dao *Simple) putBytes(key, value []byte) error {
+	err := dao.Store.Put(key, value)
+	dao.invalidate(key)
+	return err
+}
+
+// deleteKey deletes the key from the Store invalidating cached object for it.
+func (dao *Simple) deleteKey(key []byte) error {
+	err := dao.Store.Delete(key)
+	dao.invalidate(key)
+	return err
+}
+
+func (dao *Simple) invalidate(key []byte) {
+	if dao.cache != nil && isCacheable(key) {
+		dao.cache.invalidate(key)
+	}
+}
+
+// getObject returns the object stored by the given key decoding it with
+// the decode function. If the key wasn't changed by this DAO or any DAO it
+// was wrapped from, the object is taken from (or added to) the object cache
+// of the lowest DAO. Returned object is always a new one.
+func (dao *Simple) getObject(key []byte, decode func([]byte) (interface{}, error)) (interface{}, error) {
+	d := dao
+	for d.parent != nil && !d.Store.IsChanged(key) {
+		d = d.parent
+	}
+	if d.cache == nil {
+		b, err := dao.Store.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		return decode(b)
+	}
+	obj, gen, ok := d.cache.get(key)
+	if ok {
+		return copyObject(obj), nil
+	}
+	b, err := d.Store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	obj, err = decode(b)
+	if err != nil {
+		return nil, err
+	}
+	d.cache.add(key, obj, gen)
+	return copyObject(obj), nil
 }
 
 // -- start accounts.
@@ -136,13 +219,15 @@ func (dao *Simple) GetAccountStateOrNew(hash util.Uint160) (*state.Account, erro
 // GetAccountState returns Account from the given Store if it's
 // present there. Returns nil otherwise.
 func (dao *Simple) GetAccountState(hash util.Uint160) (*state.Account, error) {
-	account := &state.Account{}
 	key := storage.AppendPrefix(storage.STAccount, hash.BytesBE())
-	err := dao.GetAndDecode(account, key)
+	obj, err := dao.getObject(key, func(b []byte) (interface{}, error) {
+		account := &state.Account{}
+		return account, decodeBytes(account, b)
+	})
 	if err != nil {
 		return nil, err
 	}
-	return account, err
+	return obj.(*state.Account), nil
 }
 
 // PutAccountState saves given Account in given store.
@@ -161,12 +246,15 @@ func (dao *Simple) putAccountState(as *state.Account, buf *io.BufBinWriter) erro
 
 // GetAssetState returns given asset state as recorded in the given store.
 func (dao *Simple) GetAssetState(assetID util.Uint256) (*state.Asset, error) {
-	asset := &state.Asset{}
 	key := storage.AppendPrefix(storage.STAsset, assetID.BytesBE())
-	err := dao.GetAndDecode(asset, key)
+	obj, err := dao.getObject(key, func(b []byte) (interface{}, error) {
+		asset := &state.Asset{}
+		return asset, decodeBytes(asset, b)
+	})
 	if err != nil {
 		return nil, err
 	}
+	asset := obj.(*state.Asset)
 	if asset.ID != assetID {
 		return nil, fmt.Errorf("found asset id is not equal to expected")
 	}
@@ -186,12 +274,15 @@ func (dao *Simple) PutAssetState(as *state.Asset) error {
 // GetContractState returns contract state as recorded in the given
 // store by the given script hash.
 func (dao *Simple) GetContractState(hash util.Uint160) (*state.Contract, error) {
-	contract := &state.Contract{}
 	key := storage.AppendPrefix(storage.STContract, hash.BytesBE())
-	err := dao.GetAndDecode(contract, key)
+	obj, err := dao.getObject(key, func(b []byte) (interface{}, error) {
+		contract := &state.Contract{}
+		return contract, decodeBytes(contract, b)
+	})
 	if err != nil {
 		return nil, err
 	}
+	contract := obj.(*state.Contract)
 	if contract.ScriptHash() != hash {
 		return nil, fmt.Errorf("found script hash is not equal to expected")
 	}
@@ -208,19 +299,25 @@ func (dao *Simple) PutContractState(cs *state.Contract) error {
 // DeleteContractState deletes given contract state in the given store.
 func (dao *Simple) DeleteContractState(hash util.Uint160) error {
 	key := storage.AppendPrefix(storage.STContract, hash.BytesBE())
-	return dao.Store.Delete(key)
+	return dao.deleteKey(key)
 }
 
 // GetNativeContractState retrieves native contract state from the store.
 func (dao *Simple) GetNativeContractState(h util.Uint160) ([]byte, error) {
 	key := storage.AppendPrefix(storage.STNativeContract, h.BytesBE())
-	return dao.Store.Get(key)
+	obj, err := dao.getObject(key, func(b []byte) (interface{}, error) {
+		return b, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj.([]byte), nil
 }
 
 // PutNativeContractState puts native contract state into the store.
 func (dao *Simple) PutNativeContractState(h util.Uint160, value []byte) error {
 	key := storage.AppendPrefix(storage.STNativeContract, h.BytesBE())
-	return dao.Store.Put(key, value)
+	return dao.putBytes(key, value)
 }
 
 // -- end contracts.
@@ -675,6 +772,23 @@ func (dao *Simple) StoreAsCurrentBlock(block *block.Block) error {
 	return dao.Store.Put(storage.SYSCurrentBlock.Bytes(), buf.Bytes())
 }
 
+// PruneAppExecResults removes application execution results of transactions
+// from the block with the given hash. Block and transaction data are kept as
+// they're accessible to contracts.
+func (dao *Simple) PruneAppExecResults(hash util.Uint256) error {
+	b, _, err := dao.GetBlock(hash)
+	if err != nil {
+		return err
+	}
+	for _, tx := range b.Transactions {
+		key := storage.AppendPrefix(storage.STNotification, tx.Hash().BytesBE())
+		if err := dao.deleteKey(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // StoreAsTransaction stores the given TX as DataTransaction.
 func (dao *Simple) StoreAsTransaction(tx *transaction.Transaction, index uint32) error {
 	key := storage.AppendPrefix(storage.DataTransaction, tx.Hash().BytesLE())
@@ -719,5 +833,132 @@ func (dao *Simple) checkUsedInputs(inputs []transaction.Input, coin state.Coin)
 // Persist flushes all the changes made into the (supposedly) persistent
 // underlying store.
 func (dao *Simple) Persist() (int, error) {
-	return dao.Store.Persist()
+	keys := dao.changedCacheableKeys()
+	n, err := dao.Store.Persist()
+	dao.invalidateParent(keys)
+	return n, err
+}
+
+// changedCacheableKeys returns the list of keys changed in this DAO that
+// can have objects cached in the parent DAO.
+func (dao *Simple) changedCacheableKeys() [][]byte {
+	if dao.parent == nil || dao.parent.cache == nil {
+		return nil
+	}
+	var keys [][]byte
+	dao.Store.IterateChanged(func(k []byte) {
+		if isCacheable(k) {
+			keys = append(keys, append([]byte{}, k...))
+		}
+	})
+	return keys
+}
+
+// invalidateParent invalidates parent DAO cached objects for the given keys
+// (after they were persisted into it).
+func (dao *Simple) invalidateParent(keys [][]byte) {
+	if len(keys) != 0 {
+		dao.parent.cache.invalidate(keys...)
+	}
+}
+
+// PersistWithUndo is the same as Persist, but before flushing the changes it
+// stores the changeset reverting them as an undo record for the block with the
+// given index (so that it's persisted atomically with the changes).
+func (dao *Simple) PersistWithUndo(index uint32) (int, error) {
+	undo := dao.Store.GetReverseBatch()
+	buf := io.NewBufBinWriter()
+	buf.WriteVarUint(uint64(len(undo.Put)))
+	for _, kv := range undo.Put {
+		buf.WriteVarBytes(kv.Key)
+		buf.WriteVarBytes(kv.Value)
+	}
+	buf.WriteVarUint(uint64(len(undo.Deleted)))
+	for _, kv := range undo.Deleted {
+		buf.WriteVarBytes(kv.Key)
+	}
+	if buf.Err != nil {
+		return 0, buf.Err
+	}
+	if err := dao.Store.Put(makeUndoKey(index), buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return dao.Persist()
+}
+
+// GetUndo returns the undo record stored for the block with the given index.
+func (dao *Simple) GetUndo(index uint32) (*storage.MemBatch, error) {
+	b, err := dao.Store.Get(makeUndoKey(index))
+	if err != nil {
+		return nil, err
+	}
+	r := io.NewBinReaderFromBuf(b)
+	undo := new(storage.MemBatch)
+	undo.Put = make([]storage.KeyValue, r.ReadVarUint())
+	for i := range undo.Put {
+		undo.Put[i].Key = r.ReadVarBytes()
+		undo.Put[i].Value = r.ReadVarBytes()
+		undo.Put[i].Exists = true
+	}
+	undo.Deleted = make([]storage.KeyValue, r.ReadVarUint())
+	for i := range undo.Deleted {
+		undo.Deleted[i].Key = r.ReadVarBytes()
+	}
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	return undo, nil
+}
+
+// DeleteUndo deletes the undo record stored for the block with the given
+// index.
+func (dao *Simple) DeleteUndo(index uint32) error {
+	return dao.Store.Delete(makeUndoKey(index))
+}
+
+// RevertBlock reverts all changes made by the block with the given index using
+// its undo record which is deleted then. It's only valid for the current
+// block.
+func (dao *Simple) RevertBlock(index uint32) error {
+	undo, err := dao.GetUndo(index)
+	if err != nil {
+		return err
+	}
+	for _, kv := range undo.Put {
+		if err := dao.putBytes(kv.Key, kv.Value); err != nil {
+			return err
+		}
+	}
+	for _, kv := range undo.Deleted {
+		if err := dao.deleteKey(kv.Key); err != nil {
+			return err
+		}
+	}
+	return dao.DeleteUndo(index)
+}
+
+// DeleteHeaderHashes deletes stored header hash batches that contain hashes
+// for blocks with indexes starting from the given one.
+func (dao *Simple) DeleteHeaderHashes(from uint32) error {
+	var keys [][]byte
+	dao.Store.Seek(storage.IXHeaderHashList.Bytes(), func(k, v []byte) {
+		storedCount := binary.LittleEndian.Uint32(k[1:])
+		hashes, err := read2000Uint256Hashes(v)
+		if err != nil || storedCount+uint32(len(hashes)) > from {
+			keys = append(keys, append([]byte{}, k...))
+		}
+	})
+	for _, k := range keys {
+		if err := dao.Store.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func makeUndoKey(index uint32) []byte {
+	key := make([]byte, 5)
+	key[0] = byte(storage.DataUndo)
+	binary.LittleEndian.PutUint32(key[1:], index)
+	return key
 }
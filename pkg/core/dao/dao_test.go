@@ -283,3 +283,68 @@ func TestStoreAsTransaction(t *testing.T) {
 	hasTransaction := dao.HasTransaction(hash)
 	require.True(t, hasTransaction)
 }
+
+func TestPruneAppExecResults(t *testing.T) {
+	dao := NewSimple(storage.NewMemoryStore())
+	tx := transaction.NewIssueTX()
+	b := &block.Block{
+		Base: block.Base{
+			Index: 7,
+			Script: transaction.Witness{
+				VerificationScript: []byte{byte(opcode.PUSH1)},
+				InvocationScript:   []byte{byte(opcode.NOP)},
+			},
+		},
+		Transactions: []*transaction.Transaction{tx},
+	}
+	require.NoError(t, dao.StoreAsBlock(b, 42))
+	require.NoError(t, dao.StoreAsTransaction(tx, b.Index))
+
+	require.NoError(t, dao.PutAppExecResult(&state.AppExecResult{TxHash: tx.Hash()}))
+
+	require.NoError(t, dao.PruneAppExecResults(b.Hash()))
+	_, err := dao.GetAppExecResult(tx.Hash())
+	require.Equal(t, storage.ErrKeyNotFound, err)
+
+	gotBlock, sysfee, err := dao.GetBlock(b.Hash())
+	require.NoError(t, err)
+	require.EqualValues(t, 42, sysfee)
+	require.Equal(t, 1, len(gotBlock.Transactions))
+	gotTx, height, err := dao.GetTransaction(tx.Hash())
+	require.NoError(t, err)
+	require.Equal(t, tx.Hash(), gotTx.Hash())
+	require.Equal(t, b.Index, height)
+}
+
+func TestPersistWithUndo(t *testing.T) {
+	ps := storage.NewMemoryStore()
+	require.NoError(t, ps.Put([]byte{1}, []byte{1}))
+	require.NoError(t, ps.Put([]byte{2}, []byte{2}))
+
+	dao := NewSimple(ps)
+	require.NoError(t, dao.Store.Put([]byte{1}, []byte{10}))
+	require.NoError(t, dao.Store.Delete([]byte{2}))
+	require.NoError(t, dao.Store.Put([]byte{3}, []byte{3}))
+	_, err := dao.PersistWithUndo(42)
+	require.NoError(t, err)
+	v, err := ps.Get([]byte{1})
+	require.NoError(t, err)
+	require.Equal(t, []byte{10}, v)
+
+	_, err = dao.GetUndo(41)
+	require.Error(t, err)
+	require.NoError(t, dao.RevertBlock(42))
+	_, err = dao.Persist()
+	require.NoError(t, err)
+
+	v, err = ps.Get([]byte{1})
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, v)
+	v, err = ps.Get([]byte{2})
+	require.NoError(t, err)
+	require.Equal(t, []byte{2}, v)
+	_, err = ps.Get([]byte{3})
+	require.Equal(t, storage.ErrKeyNotFound, err)
+	_, err = dao.GetUndo(42)
+	require.Equal(t, storage.ErrKeyNotFound, err)
+}
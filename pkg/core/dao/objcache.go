@@ -0,0 +1,165 @@
+package dao
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// objectCacheRequests is a prometheus metric for decoded object cache
+// lookups.
+var objectCacheRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Help:      "Decoded object cache requests by key prefix and result (hit/miss)",
+		Name:      "dao_object_cache_requests_total",
+		Namespace: "neogo",
+	},
+	[]string{"prefix", "result"},
+)
+
+func init() {
+	prometheus.MustRegister(objectCacheRequests)
+}
+
+// isCacheable returns true for keys of objects that can be stored in the
+// objectCache.
+func isCacheable(key []byte) bool {
+	if len(key) == 0 {
+		return false
+	}
+	switch storage.KeyPrefix(key[0]) {
+	case storage.STAccount, storage.STAsset, storage.STContract, storage.STNativeContract:
+		return true
+	}
+	return false
+}
+
+// objectCache is a bounded LRU cache of decoded objects. It's only valid for
+// the particular Store contents, so any change to this Store must be followed
+// by invalidation of the changed keys.
+type objectCache struct {
+	lock  sync.Mutex
+	size  int
+	gen   uint64
+	elems map[string]*list.Element
+	queue *list.List
+}
+
+// cacheEntry is an objectCache element.
+type cacheEntry struct {
+	key string
+	obj interface{}
+}
+
+func newObjectCache(size int) *objectCache {
+	return &objectCache{
+		size:  size,
+		elems: make(map[string]*list.Element),
+		queue: list.New(),
+	}
+}
+
+// get returns the object stored for the given key along with the current
+// cache generation that is to be passed to add if there is no such object.
+func (c *objectCache) get(key []byte) (interface{}, uint64, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	e, ok := c.elems[string(key)]
+	result := "miss"
+	if ok {
+		result = "hit"
+		c.queue.MoveToFront(e)
+	}
+	objectCacheRequests.WithLabelValues(storage.KeyPrefix(key[0]).String(), result).Inc()
+	if !ok {
+		return nil, c.gen, false
+	}
+	return e.Value.(*cacheEntry).obj, c.gen, true
+}
+
+// add adds the object to the cache unless the cache was invalidated since
+// the generation given was obtained (so the object can be outdated).
+func (c *objectCache) add(key []byte, obj interface{}, gen uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if gen != c.gen {
+		return
+	}
+	k := string(key)
+	if e, ok := c.elems[k]; ok {
+		e.Value.(*cacheEntry).obj = obj
+		c.queue.MoveToFront(e)
+		return
+	}
+	c.elems[k] = c.queue.PushFront(&cacheEntry{key: k, obj: obj})
+	for c.queue.Len() > c.size {
+		e := c.queue.Back()
+		c.queue.Remove(e)
+		delete(c.elems, e.Value.(*cacheEntry).key)
+	}
+}
+
+// invalidate removes the given keys from the cache.
+func (c *objectCache) invalidate(keys ...[]byte) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.gen++
+	for _, k := range keys {
+		if e, ok := c.elems[string(k)]; ok {
+			c.queue.Remove(e)
+			delete(c.elems, string(k))
+		}
+	}
+}
+
+// purge removes all objects from the cache.
+func (c *objectCache) purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.gen++
+	c.elems = make(map[string]*list.Element)
+	c.queue.Init()
+}
+
+// copyObject returns a deep copy of the cached object so that callers can
+// freely modify it.
+func copyObject(obj interface{}) interface{} {
+	switch o := obj.(type) {
+	case *state.Account:
+		return copyAccount(o)
+	case *state.Asset:
+		a := *o
+		return &a
+	case *state.Contract:
+		cs := *o
+		cs.Script = append([]byte{}, o.Script...)
+		cs.ParamList = append(cs.ParamList[:0:0], o.ParamList...)
+		return &cs
+	case []byte:
+		return append([]byte{}, o...)
+	default:
+		panic("unexpected cached object type")
+	}
+}
+
+func copyAccount(acc *state.Account) *state.Account {
+	res := &state.Account{
+		Version:    acc.Version,
+		ScriptHash: acc.ScriptHash,
+		IsFrozen:   acc.IsFrozen,
+		Votes:      append(acc.Votes[:0:0], acc.Votes...),
+		Balances:   make(map[util.Uint256][]state.UnspentBalance, len(acc.Balances)),
+		Unclaimed:  state.UnclaimedBalances{Raw: append([]byte{}, acc.Unclaimed.Raw...)},
+	}
+	res.GAS.Balance.Set(&acc.GAS.Balance)
+	res.NEO.Balance.Set(&acc.NEO.Balance)
+	res.NEO.BalanceHeight = acc.NEO.BalanceHeight
+	for id, bs := range acc.Balances {
+		res.Balances[id] = append(bs[:0:0], bs...)
+	}
+	return res
+}
@@ -0,0 +1,104 @@
+package dao
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/internal/random"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectCacheLRU(t *testing.T) {
+	c := newObjectCache(2)
+	keys := [][]byte{{byte(storage.STAccount), 1}, {byte(storage.STAccount), 2}, {byte(storage.STAccount), 3}}
+
+	_, gen, ok := c.get(keys[0])
+	require.False(t, ok)
+	c.add(keys[0], 0, gen)
+	c.add(keys[1], 1, gen)
+	obj, _, ok := c.get(keys[0])
+	require.True(t, ok)
+	require.Equal(t, 0, obj)
+
+	// keys[1] is the least recently used one.
+	c.add(keys[2], 2, gen)
+	_, _, ok = c.get(keys[1])
+	require.False(t, ok)
+	_, _, ok = c.get(keys[0])
+	require.True(t, ok)
+
+	c.invalidate(keys[0])
+	_, _, ok = c.get(keys[0])
+	require.False(t, ok)
+	// Outdated generation.
+	c.add(keys[0], 0, gen)
+	_, _, ok = c.get(keys[0])
+	require.False(t, ok)
+
+	c.purge()
+	_, _, ok = c.get(keys[2])
+	require.False(t, ok)
+}
+
+func TestSimpleObjectCache(t *testing.T) {
+	ps := storage.NewMemoryStore()
+	d := NewSimpleWithObjectCache(ps, 10)
+	hash := random.Uint160()
+	acc := state.NewAccount(hash)
+	acc.GAS.Balance = *big.NewInt(42)
+	acc.Balances[util.Uint256{1}] = []state.UnspentBalance{{Value: 1}}
+	require.NoError(t, d.PutAccountState(acc))
+
+	got, err := d.GetAccountState(hash)
+	require.NoError(t, err)
+	require.Equal(t, acc, got)
+
+	// Returned objects are copies.
+	got.GAS.Balance.SetInt64(1)
+	got.Balances[util.Uint256{1}][0].Value = 2
+	got, err = d.GetAccountState(hash)
+	require.NoError(t, err)
+	require.Equal(t, acc, got)
+
+	// Changes in the wrapped DAO are not visible until persisted.
+	w := d.GetWrapped()
+	acc.IsFrozen = true
+	require.NoError(t, w.PutAccountState(acc))
+	got, err = d.GetAccountState(hash)
+	require.NoError(t, err)
+	require.False(t, got.IsFrozen)
+	got, err = w.GetAccountState(hash)
+	require.NoError(t, err)
+	require.True(t, got.IsFrozen)
+
+	_, err = w.Persist()
+	require.NoError(t, err)
+	got, err = d.GetAccountState(hash)
+	require.NoError(t, err)
+	require.True(t, got.IsFrozen)
+
+	cs := &state.Contract{Script: []byte{1, 2, 3}}
+	require.NoError(t, d.PutContractState(cs))
+	gotCS, err := d.GetContractState(cs.ScriptHash())
+	require.NoError(t, err)
+	require.Equal(t, cs.Script, gotCS.Script)
+
+	w = d.GetWrapped()
+	require.NoError(t, w.DeleteContractState(cs.ScriptHash()))
+	_, err = w.Persist()
+	require.NoError(t, err)
+	_, err = d.GetContractState(cs.ScriptHash())
+	require.Equal(t, storage.ErrKeyNotFound, err)
+
+	require.NoError(t, d.PutNativeContractState(hash, []byte{1}))
+	v, err := d.GetNativeContractState(hash)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, v)
+	require.NoError(t, d.PutNativeContractState(hash, []byte{2}))
+	v, err = d.GetNativeContractState(hash)
+	require.NoError(t, err)
+	require.Equal(t, []byte{2}, v)
+}
@@ -34,8 +34,17 @@ var neoOwner = testchain.MultisigScriptHash()
 // newTestChain should be called before newBlock invocation to properly setup
 // global state.
 func newTestChain(t *testing.T) *Blockchain {
+	return newTestChainWithCustomCfg(t, nil)
+}
+
+// newTestChainWithCustomCfg is the same as newTestChain, but allows to change
+// protocol configuration before blockchain creation.
+func newTestChainWithCustomCfg(t *testing.T, f func(*config.ProtocolConfiguration)) *Blockchain {
 	unitTestNetCfg, err := config.Load("../../config", config.ModeUnitTestNet)
 	require.NoError(t, err)
+	if f != nil {
+		f(&unitTestNetCfg.ProtocolConfiguration)
+	}
 	chain, err := NewBlockchain(storage.NewMemoryStore(), unitTestNetCfg.ProtocolConfiguration, zaptest.NewLogger(t))
 	require.NoError(t, err)
 	go chain.Run()
@@ -0,0 +1,75 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/dao"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// Rollback reverts the chain stored in s to the given height using undo data
+// recorded for every block (see RollbackDepth protocol setting). Current
+// block, current header and header hash list are restored, headers above the
+// height are removed, so the node can resume synchronization from there. All
+// changes are written in one batch, nothing is changed if the rollback fails.
+func Rollback(s storage.Store, height uint32) error {
+	d := dao.NewSimple(s)
+	current, err := d.GetCurrentBlockHeight()
+	if err != nil {
+		return fmt.Errorf("can't get current block: %v", err)
+	}
+	if height >= current {
+		return fmt.Errorf("can't roll back to %d, current height is %d", height, current)
+	}
+	// Headers above the height are removed, these are headers of the
+	// reverted blocks and of the blocks not yet processed.
+	hdrHeight, hdrHash, err := d.GetCurrentHeaderHeight()
+	if err != nil {
+		return fmt.Errorf("can't get current header: %v", err)
+	}
+	var headers []util.Uint256
+	for i := hdrHeight; i > height; i-- {
+		h, _, err := d.GetBlock(hdrHash)
+		if err != nil {
+			return fmt.Errorf("can't get header %d: %v", i, err)
+		}
+		headers = append(headers, hdrHash)
+		hdrHash = h.PrevHash
+	}
+
+	for i := current; i > height; i-- {
+		err := d.RevertBlock(i)
+		if err == storage.ErrKeyNotFound {
+			return fmt.Errorf("no undo data for block %d, it's only kept for RollbackDepth latest blocks", i)
+		}
+		if err != nil {
+			return fmt.Errorf("can't revert block %d: %v", i, err)
+		}
+	}
+	b, err := d.Store.Get(storage.SYSCurrentBlock.Bytes())
+	if err != nil {
+		return fmt.Errorf("can't get current block: %v", err)
+	}
+	hash, err := util.Uint256DecodeBytesBE(b[:32])
+	if err != nil {
+		return err
+	}
+	if !hdrHash.Equals(hash) {
+		return fmt.Errorf("header %d (%s) doesn't match current block %s after revert",
+			height, hdrHash.StringLE(), hash.StringLE())
+	}
+	for _, h := range headers {
+		if err := d.Store.Delete(storage.AppendPrefix(storage.DataBlock, h.BytesLE())); err != nil {
+			return err
+		}
+	}
+	if err := d.PutCurrentHeader(hashAndIndexToBytes(hash, height)); err != nil {
+		return err
+	}
+	if err := d.DeleteHeaderHashes(height + 1); err != nil {
+		return err
+	}
+	_, err = d.Persist()
+	return err
+}
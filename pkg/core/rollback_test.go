@@ -0,0 +1,54 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// dumpStore returns all the store contents except undo data.
+func dumpStore(s storage.Store) map[string]string {
+	m := make(map[string]string)
+	for i := 0; i < 256; i++ {
+		if storage.KeyPrefix(i) == storage.DataUndo {
+			continue
+		}
+		s.Seek([]byte{byte(i)}, func(k, v []byte) {
+			m[string(k)] = string(v)
+		})
+	}
+	return m
+}
+
+func TestRollback(t *testing.T) {
+	bc := newTestChainWithCustomCfg(t, func(cfg *config.ProtocolConfiguration) {
+		cfg.RollbackDepth = 4
+	})
+	defer bc.Close()
+	blocks, err := bc.genBlocks(2)
+	require.NoError(t, err)
+	expected := dumpStore(bc.dao.Store)
+
+	reverted, err := bc.genBlocks(4)
+	require.NoError(t, err)
+	require.Error(t, Rollback(bc.dao.Store, 1), "no undo data")
+	require.Error(t, Rollback(bc.dao.Store, 6), "current height")
+
+	require.NoError(t, Rollback(bc.dao.Store, 2))
+	require.Equal(t, expected, dumpStore(bc.dao.Store))
+
+	var problems []error
+	require.NoError(t, CheckStore(bc.dao.Store, func(err error) { problems = append(problems, err) }, nil))
+	require.Empty(t, problems)
+
+	chain, err := NewBlockchain(storage.NewMemCachedStore(bc.dao.Store), bc.config, bc.log)
+	require.NoError(t, err)
+	go chain.Run()
+	require.Equal(t, uint32(2), chain.BlockHeight())
+	require.Equal(t, uint32(2), chain.HeaderHeight())
+	require.Equal(t, blocks[1].Hash(), chain.CurrentBlockHash())
+	require.NoError(t, chain.AddBlock(reverted[0]))
+	require.Equal(t, uint32(3), chain.BlockHeight())
+}
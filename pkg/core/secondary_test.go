@@ -0,0 +1,63 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/stretchr/testify/require"
+)
+
+// sharedStore is a Store shared between primary and secondary Blockchains in
+// tests, it's closed by the primary one only.
+type sharedStore struct {
+	storage.Store
+}
+
+func (sharedStore) Close() error { return nil }
+
+func waitHeight(t *testing.T, bc *Blockchain, height uint32) {
+	for i := 0; i < 50 && bc.BlockHeight() != height; i++ {
+		time.Sleep(persistInterval / 10)
+	}
+	require.Equal(t, height, bc.BlockHeight())
+}
+
+func TestSecondaryBlockchain(t *testing.T) {
+	bc := newTestChainWithCustomCfg(t, func(cfg *config.ProtocolConfiguration) {
+		cfg.RollbackDepth = 10
+	})
+	defer bc.Close()
+	_, err := NewSecondaryBlockchain(storage.NewMemoryStore(), bc.config, bc.log)
+	require.Error(t, err)
+
+	blocks, err := bc.genBlocks(2)
+	require.NoError(t, err)
+
+	sec, err := NewSecondaryBlockchain(sharedStore{bc.dao.Store}, bc.config, bc.log)
+	require.NoError(t, err)
+	go sec.Run()
+	defer sec.Close()
+	require.Equal(t, uint32(2), sec.BlockHeight())
+	require.Equal(t, uint32(2), sec.HeaderHeight())
+
+	require.Equal(t, ErrReadOnly, sec.AddBlock(blocks[1]))
+	require.Equal(t, ErrReadOnly, sec.AddHeaders(blocks[1].Header()))
+	require.Equal(t, ErrReadOnly, sec.PoolTx(transaction.NewIssueTX()))
+
+	blocks, err = bc.genBlocks(3)
+	require.NoError(t, err)
+	waitHeight(t, sec, 5)
+	require.Equal(t, uint32(5), sec.HeaderHeight())
+	require.Equal(t, blocks[2].Hash(), sec.CurrentBlockHash())
+	b, err := sec.GetBlock(blocks[2].Hash())
+	require.NoError(t, err)
+	require.Equal(t, blocks[2].Hash(), b.Hash())
+
+	require.NoError(t, Rollback(bc.dao.Store, 3))
+	waitHeight(t, sec, 3)
+	require.Equal(t, uint32(3), sec.HeaderHeight())
+	require.Equal(t, blocks[0].Hash(), sec.CurrentBlockHash())
+}
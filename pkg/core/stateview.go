@@ -0,0 +1,96 @@
+package core
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/core/blockchainer"
+	"github.com/nspcc-dev/neo-go/pkg/core/dao"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"go.uber.org/zap"
+)
+
+// stateView is a read-only view of the chain state at some block height.
+// It implements blockchainer.StateView.
+type stateView struct {
+	bc  *Blockchain
+	dao *dao.Simple
+}
+
+// GetStateView returns a consistent read-only view of the chain state as it
+// is after the last stored block. It must be released after use.
+func (bc *Blockchain) GetStateView() (blockchainer.StateView, error) {
+	bc.lock.RLock()
+	snap, err := bc.dao.Store.Snapshot()
+	bc.lock.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	return &stateView{
+		bc:  bc,
+		dao: dao.NewSimple(storage.NewReadOnlyStore(snap)),
+	}, nil
+}
+
+// GetAccountState implements blockchainer.StateView interface.
+func (v *stateView) GetAccountState(scriptHash util.Uint160) *state.Account {
+	as, err := v.dao.GetAccountState(scriptHash)
+	if as == nil && err != storage.ErrKeyNotFound {
+		v.bc.log.Warn("failed to get account state", zap.Error(err))
+	}
+	return as
+}
+
+// GetAssetState implements blockchainer.StateView interface.
+func (v *stateView) GetAssetState(assetID util.Uint256) *state.Asset {
+	asset, err := v.dao.GetAssetState(assetID)
+	if asset == nil && err != storage.ErrKeyNotFound {
+		v.bc.log.Warn("failed to get asset state",
+			zap.Stringer("asset", assetID),
+			zap.Error(err))
+	}
+	return asset
+}
+
+// GetNEP5Balances implements blockchainer.StateView interface.
+func (v *stateView) GetNEP5Balances(acc util.Uint160) *state.NEP5Balances {
+	bs, err := v.dao.GetNEP5Balances(acc)
+	if err != nil {
+		return nil
+	}
+	return bs
+}
+
+// GetNEP5TransferLog implements blockchainer.StateView interface.
+func (v *stateView) GetNEP5TransferLog(acc util.Uint160) *state.NEP5TransferLog {
+	return getNEP5TransferLog(v.dao, acc)
+}
+
+// GetStorageItem implements blockchainer.StateView interface.
+func (v *stateView) GetStorageItem(scripthash util.Uint160, key []byte) *state.StorageItem {
+	return v.dao.GetStorageItem(scripthash, key)
+}
+
+// GetUnspentCoinState implements blockchainer.StateView interface.
+func (v *stateView) GetUnspentCoinState(hash util.Uint256) *state.UnspentCoin {
+	ucs, err := v.dao.GetUnspentCoinState(hash)
+	if ucs == nil && err != storage.ErrKeyNotFound {
+		v.bc.log.Warn("failed to get unspent coin state", zap.Error(err))
+	}
+	return ucs
+}
+
+// GetTestVM implements blockchainer.StateView interface. Storage changes
+// made by the script are never persisted.
+func (v *stateView) GetTestVM() *vm.VM {
+	systemInterop := v.bc.newInteropContext(trigger.Application, dao.NewSimple(v.dao.Store), nil, nil)
+	vm := SpawnVM(systemInterop)
+	vm.SetPriceGetter(getPrice)
+	return vm
+}
+
+// Release implements blockchainer.StateView interface.
+func (v *stateView) Release() {
+	_ = v.dao.Store.Close()
+}
@@ -0,0 +1,34 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStateView(t *testing.T) {
+	bc := newTestChain(t)
+	defer bc.Close()
+
+	h := util.Uint160{1, 2, 3}
+	view, err := bc.GetStateView()
+	require.NoError(t, err)
+	require.NotNil(t, view.GetAccountState(neoOwner))
+
+	si := &state.StorageItem{Value: []byte{4, 5, 6}}
+	require.NoError(t, bc.dao.PutStorageItem(h, []byte{1}, si))
+	require.Nil(t, view.GetStorageItem(h, []byte{1}))
+
+	v := view.GetTestVM()
+	v.LoadScript([]byte{byte(opcode.PUSH1)})
+	require.NoError(t, v.Run())
+	view.Release()
+
+	view, err = bc.GetStateView()
+	require.NoError(t, err)
+	defer view.Release()
+	require.Equal(t, si, view.GetStorageItem(h, []byte{1}))
+}
@@ -1,7 +1,9 @@
 package storage
 
 import (
+	"fmt"
 	"os"
+	"runtime"
 
 	"github.com/dgraph-io/badger/v2"
 )
@@ -97,6 +99,14 @@ func (b *BadgerDBStore) Put(key, value []byte) error {
 	})
 }
 
+// PutSync implements the SyncPutter interface.
+func (b *BadgerDBStore) PutSync(key, value []byte) error {
+	if err := b.Put(key, value); err != nil {
+		return err
+	}
+	return b.db.Sync()
+}
+
 // PutBatch implements the Store interface.
 func (b *BadgerDBStore) PutBatch(batch Batch) error {
 	defer batch.(*BadgerDBBatch).batch.Cancel()
@@ -106,31 +116,92 @@ func (b *BadgerDBStore) PutBatch(batch Batch) error {
 // Seek implements the Store interface.
 func (b *BadgerDBStore) Seek(key []byte, f func(k, v []byte)) {
 	err := b.db.View(func(txn *badger.Txn) error {
-		it := txn.NewIterator(badger.IteratorOptions{
-			PrefetchValues: true,
-			PrefetchSize:   100,
-			Reverse:        false,
-			AllVersions:    false,
-			Prefix:         key,
-			InternalAccess: false,
-		})
-		defer it.Close()
-		for it.Seek(key); it.ValidForPrefix(key); it.Next() {
-			item := it.Item()
-			k := item.Key()
-			v, err := item.ValueCopy(nil)
-			if err != nil {
-				return err
-			}
-			f(k, v)
-		}
-		return nil
+		return seekTxn(txn, key, f)
 	})
 	if err != nil {
 		panic(err)
 	}
 }
 
+// seekTxn iterates over all items with the given key prefix visible in the
+// transaction.
+func seekTxn(txn *badger.Txn, key []byte, f func(k, v []byte)) error {
+	it := txn.NewIterator(badger.IteratorOptions{
+		PrefetchValues: true,
+		PrefetchSize:   100,
+		Reverse:        false,
+		AllVersions:    false,
+		Prefix:         key,
+		InternalAccess: false,
+	})
+	defer it.Close()
+	for it.Seek(key); it.ValidForPrefix(key); it.Next() {
+		item := it.Item()
+		k := item.Key()
+		v, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		f(k, v)
+	}
+	return nil
+}
+
+// Snapshot implements the Snapshotter interface.
+func (b *BadgerDBStore) Snapshot() (Snapshot, error) {
+	return &badgerDBSnapshot{txn: b.db.NewTransaction(false)}, nil
+}
+
+// badgerDBSnapshot is a Snapshot over read-only badger transaction.
+type badgerDBSnapshot struct {
+	txn *badger.Txn
+}
+
+// Get implements the Snapshot interface.
+func (s *badgerDBSnapshot) Get(key []byte) ([]byte, error) {
+	item, err := s.txn.Get(key)
+	if err == badger.ErrKeyNotFound {
+		return nil, ErrKeyNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return item.ValueCopy(nil)
+}
+
+// Seek implements the Snapshot interface.
+func (s *badgerDBSnapshot) Seek(key []byte, f func(k, v []byte)) {
+	if err := seekTxn(s.txn, key, f); err != nil {
+		panic(err)
+	}
+}
+
+// Release implements the Snapshot interface.
+func (s *badgerDBSnapshot) Release() {
+	s.txn.Discard()
+}
+
+// Compact implements the Compactor interface. It compacts LSM tree into a
+// single level and reclaims value log space.
+func (b *BadgerDBStore) Compact() error {
+	if err := b.db.Flatten(runtime.NumCPU()); err != nil {
+		return err
+	}
+	for {
+		err := b.db.RunValueLogGC(0.5)
+		if err == badger.ErrNoRewrite {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// Stats implements the StatsReporter interface.
+func (b *BadgerDBStore) Stats() (string, error) {
+	lsm, vlog := b.db.Size()
+	return fmt.Sprintf("LSM size: %d bytes\nValue log size: %d bytes\n", lsm, vlog), nil
+}
+
 // Close releases all db resources.
 func (b *BadgerDBStore) Close() error {
 	return b.db.Close()
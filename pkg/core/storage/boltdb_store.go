@@ -15,6 +15,9 @@ type BoltDBOptions struct {
 	FilePath string `yaml:"FilePath"`
 }
 
+// boltInitialMmapSize is the initial size of BoltDB memory map.
+const boltInitialMmapSize = 64 * 1024 * 1024
+
 // Bucket represents bucket used in boltdb to store all the data.
 var Bucket = []byte("DB")
 
@@ -26,7 +29,10 @@ type BoltDBStore struct {
 
 // NewBoltDBStore returns a new ready to use BoltDB storage with created bucket.
 func NewBoltDBStore(cfg BoltDBOptions) (*BoltDBStore, error) {
-	var opts *bbolt.Options       // should be exposed via BoltDBOptions if anything needed
+	// Read transactions used for snapshots block database remapping, so
+	// reserve some space for the database to grow without it. Other options
+	// should be exposed via BoltDBOptions if anything needed.
+	opts := &bbolt.Options{InitialMmapSize: boltInitialMmapSize}
 	fileMode := os.FileMode(0600) // should be exposed via BoltDBOptions if anything needed
 	fileName := cfg.FilePath
 	if err := io.MakeDirForFile(fileName, "BoltDB"); err != nil {
@@ -100,11 +106,7 @@ func (s *BoltDBStore) PutBatch(batch Batch) error {
 // Seek implements the Store interface.
 func (s *BoltDBStore) Seek(key []byte, f func(k, v []byte)) {
 	err := s.db.View(func(tx *bbolt.Tx) error {
-		c := tx.Bucket(Bucket).Cursor()
-		prefix := util.BytesPrefix(key)
-		for k, v := c.Seek(prefix.Start); k != nil && bytes.Compare(k, prefix.Limit) <= 0; k, v = c.Next() {
-			f(k, v)
-		}
+		seekBucket(tx.Bucket(Bucket), key, f)
 		return nil
 	})
 	if err != nil {
@@ -112,6 +114,51 @@ func (s *BoltDBStore) Seek(key []byte, f func(k, v []byte)) {
 	}
 }
 
+// seekBucket iterates over all bucket items with the given key prefix.
+func seekBucket(b *bbolt.Bucket, key []byte, f func(k, v []byte)) {
+	c := b.Cursor()
+	prefix := util.BytesPrefix(key)
+	for k, v := c.Seek(prefix.Start); k != nil && bytes.Compare(k, prefix.Limit) <= 0; k, v = c.Next() {
+		f(k, v)
+	}
+}
+
+// Snapshot implements the Snapshotter interface. It holds a read-only
+// transaction open until released, so it should be released as soon as
+// possible, database file growth beyond the memory map size is blocked until
+// then.
+func (s *BoltDBStore) Snapshot() (Snapshot, error) {
+	tx, err := s.db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	return &boltDBSnapshot{tx: tx}, nil
+}
+
+// boltDBSnapshot is a Snapshot over read-only bbolt transaction.
+type boltDBSnapshot struct {
+	tx *bbolt.Tx
+}
+
+// Get implements the Snapshot interface.
+func (s *boltDBSnapshot) Get(key []byte) ([]byte, error) {
+	val := s.tx.Bucket(Bucket).Get(key)
+	if val == nil {
+		return nil, ErrKeyNotFound
+	}
+	return val, nil
+}
+
+// Seek implements the Snapshot interface.
+func (s *boltDBSnapshot) Seek(key []byte, f func(k, v []byte)) {
+	seekBucket(s.tx.Bucket(Bucket), key, f)
+}
+
+// Release implements the Snapshot interface.
+func (s *boltDBSnapshot) Release() {
+	_ = s.tx.Rollback()
+}
+
 // Batch implements the Batch interface and returns a boltdb
 // compatible Batch.
 func (s *BoltDBStore) Batch() Batch {
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// storeOpDuration is a prometheus metric for Store operations latency.
+var storeOpDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Help:      "Store operations duration by key prefix",
+		Name:      "store_op_duration_seconds",
+		Namespace: "neogo",
+		Buckets:   prometheus.ExponentialBuckets(1e-6, 4, 10),
+	},
+	[]string{"op", "prefix"},
+)
+
+func init() {
+	prometheus.MustRegister(storeOpDuration)
+}
+
+// instrumentedStore is a Store reporting its operations latency to
+// Prometheus.
+type instrumentedStore struct {
+	Store
+}
+
+// NewInstrumentedStore returns a Store passing all operations to s and
+// collecting their latency metrics by KeyPrefix. Seek duration includes the
+// time spent in the callback, batches are not broken down by KeyPrefix.
+func NewInstrumentedStore(s Store) Store {
+	return &instrumentedStore{s}
+}
+
+func observeOp(op string, key []byte, start time.Time) {
+	prefix := "none"
+	if len(key) > 0 {
+		prefix = KeyPrefix(key[0]).String()
+	}
+	storeOpDuration.WithLabelValues(op, prefix).Observe(time.Since(start).Seconds())
+}
+
+// Delete implements the Store interface.
+func (s *instrumentedStore) Delete(k []byte) error {
+	defer observeOp("delete", k, time.Now())
+	return s.Store.Delete(k)
+}
+
+// Get implements the Store interface.
+func (s *instrumentedStore) Get(k []byte) ([]byte, error) {
+	defer observeOp("get", k, time.Now())
+	return s.Store.Get(k)
+}
+
+// Put implements the Store interface.
+func (s *instrumentedStore) Put(k, v []byte) error {
+	defer observeOp("put", k, time.Now())
+	return s.Store.Put(k, v)
+}
+
+// PutSync implements the SyncPutter interface using the underlying Store.
+func (s *instrumentedStore) PutSync(k, v []byte) error {
+	defer observeOp("put", k, time.Now())
+	return PutSync(s.Store, k, v)
+}
+
+// PutBatch implements the Store interface.
+func (s *instrumentedStore) PutBatch(b Batch) error {
+	defer observeOp("put_batch", nil, time.Now())
+	return s.Store.PutBatch(b)
+}
+
+// Seek implements the Store interface.
+func (s *instrumentedStore) Seek(k []byte, f func(k, v []byte)) {
+	defer observeOp("seek", k, time.Now())
+	s.Store.Seek(k, f)
+}
+
+// Snapshot implements the Snapshotter interface using the underlying Store.
+func (s *instrumentedStore) Snapshot() (Snapshot, error) {
+	return NewSnapshot(s.Store)
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrumentedStore(t *testing.T) {
+	ps := NewMemoryStore()
+	s := NewInstrumentedStore(ps)
+	key := AppendPrefix(STAccount, []byte{1, 2, 3})
+
+	require.NoError(t, s.Put(key, []byte("value")))
+	v, err := s.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), v)
+
+	var found int
+	s.Seek(STAccount.Bytes(), func(k, v []byte) { found++ })
+	require.Equal(t, 1, found)
+
+	b := s.Batch()
+	b.Put([]byte{}, []byte("empty key"))
+	require.NoError(t, s.PutBatch(b))
+	require.NoError(t, s.Delete(key))
+	_, err = ps.Get(key)
+	require.Equal(t, ErrKeyNotFound, err)
+
+	snap, err := NewSnapshot(s)
+	require.NoError(t, err)
+	v, err = snap.Get([]byte{})
+	require.NoError(t, err)
+	require.Equal(t, []byte("empty key"), v)
+	snap.Release()
+}
@@ -39,6 +39,11 @@ func (s *LevelDBStore) Put(key, value []byte) error {
 	return s.db.Put(key, value, nil)
 }
 
+// PutSync implements the SyncPutter interface.
+func (s *LevelDBStore) PutSync(key, value []byte) error {
+	return s.db.Put(key, value, &opt.WriteOptions{Sync: true})
+}
+
 // Get implements the Store interface.
 func (s *LevelDBStore) Get(key []byte) ([]byte, error) {
 	value, err := s.db.Get(key, nil)
@@ -68,6 +73,54 @@ func (s *LevelDBStore) Seek(key []byte, f func(k, v []byte)) {
 	iter.Release()
 }
 
+// Snapshot implements the Snapshotter interface.
+func (s *LevelDBStore) Snapshot() (Snapshot, error) {
+	snap, err := s.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &levelDBSnapshot{snap: snap}, nil
+}
+
+// levelDBSnapshot is a Snapshot over leveldb.Snapshot.
+type levelDBSnapshot struct {
+	snap *leveldb.Snapshot
+}
+
+// Get implements the Snapshot interface.
+func (s *levelDBSnapshot) Get(key []byte) ([]byte, error) {
+	value, err := s.snap.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		err = ErrKeyNotFound
+	}
+	return value, err
+}
+
+// Seek implements the Snapshot interface.
+func (s *levelDBSnapshot) Seek(key []byte, f func(k, v []byte)) {
+	iter := s.snap.NewIterator(util.BytesPrefix(key), nil)
+	for iter.Next() {
+		f(iter.Key(), iter.Value())
+	}
+	iter.Release()
+}
+
+// Release implements the Snapshot interface.
+func (s *levelDBSnapshot) Release() {
+	s.snap.Release()
+}
+
+// Compact implements the Compactor interface, it compacts the whole key
+// range.
+func (s *LevelDBStore) Compact() error {
+	return s.db.CompactRange(util.Range{})
+}
+
+// Stats implements the StatsReporter interface.
+func (s *LevelDBStore) Stats() (string, error) {
+	return s.db.GetProperty("leveldb.stats")
+}
+
 // Batch implements the Batch interface and returns a leveldb
 // compatible Batch.
 func (s *LevelDBStore) Batch() Batch {
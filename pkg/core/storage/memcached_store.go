@@ -47,6 +47,32 @@ func (s *MemCachedStore) Get(key []byte) ([]byte, error) {
 	return s.ps.Get(key)
 }
 
+// IsChanged returns true if the key was changed (put or deleted) in the
+// MemCachedStore and this change is not yet persisted to the lower Store.
+func (s *MemCachedStore) IsChanged(key []byte) bool {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	k := string(key)
+	if _, ok := s.mem[k]; ok {
+		return true
+	}
+	return s.del[k]
+}
+
+// IterateChanged calls f for every key changed (put or deleted) in the
+// MemCachedStore and not yet persisted to the lower Store. The key passed
+// can't be modified and f can't change the MemCachedStore.
+func (s *MemCachedStore) IterateChanged(f func(k []byte)) {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	for k := range s.mem {
+		f([]byte(k))
+	}
+	for k := range s.del {
+		f([]byte(k))
+	}
+}
+
 // GetBatch returns currently accumulated changeset.
 func (s *MemCachedStore) GetBatch() *MemBatch {
 	s.mut.RLock()
@@ -71,6 +97,32 @@ func (s *MemCachedStore) GetBatch() *MemBatch {
 	return &b
 }
 
+// GetReverseBatch returns a changeset reverting currently accumulated changes
+// when applied to the lower Store: Put has previous values of changed keys and
+// Deleted has keys that didn't exist before.
+func (s *MemCachedStore) GetReverseBatch() *MemBatch {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+
+	var b MemBatch
+	add := func(k string) {
+		key := []byte(k)
+		v, err := s.ps.Get(key)
+		if err == nil {
+			b.Put = append(b.Put, KeyValue{Key: key, Value: v, Exists: true})
+		} else {
+			b.Deleted = append(b.Deleted, KeyValue{Key: key})
+		}
+	}
+	for k := range s.mem {
+		add(k)
+	}
+	for k := range s.del {
+		add(k)
+	}
+	return &b
+}
+
 // Seek implements the Store interface.
 func (s *MemCachedStore) Seek(key []byte, f func(k, v []byte)) {
 	s.mut.RLock()
@@ -90,6 +142,33 @@ func (s *MemCachedStore) Seek(key []byte, f func(k, v []byte)) {
 	})
 }
 
+// Snapshot implements the Snapshotter interface. The snapshot has cached
+// changes on top of the lower Store snapshot, they're shared with the
+// MemCachedStore until it's changed (see MemoryStore.Snapshot).
+func (s *MemCachedStore) Snapshot() (Snapshot, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	lower, err := NewSnapshot(s.ps)
+	if err != nil {
+		return nil, err
+	}
+	snap := &MemCachedStore{
+		MemoryStore: *s.share(),
+		ps:          NewReadOnlyStore(lower),
+	}
+	return memCachedSnapshot{snap}, nil
+}
+
+// memCachedSnapshot is a Snapshot over MemCachedStore data.
+type memCachedSnapshot struct {
+	*MemCachedStore
+}
+
+// Release implements the Snapshot interface.
+func (s memCachedSnapshot) Release() {
+	_ = s.Close()
+}
+
 // Persist flushes all the MemoryStore contents into the (supposedly) persistent
 // store ps.
 func (s *MemCachedStore) Persist() (int, error) {
@@ -134,6 +213,7 @@ func (s *MemCachedStore) Persist() (int, error) {
 	if err == nil {
 		s.mem = make(map[string][]byte)
 		s.del = make(map[string]bool)
+		s.shared = false
 	}
 	return keys, err
 }
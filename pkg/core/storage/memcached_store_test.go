@@ -108,6 +108,86 @@ func TestMemCachedPersist(t *testing.T) {
 	})
 }
 
+func TestMemCachedGetReverseBatch(t *testing.T) {
+	ps := NewMemoryStore()
+	require.NoError(t, ps.Put([]byte("changed"), []byte("old")))
+	require.NoError(t, ps.Put([]byte("deleted"), []byte("value")))
+
+	ts := NewMemCachedStore(ps)
+	require.NoError(t, ts.Put([]byte("changed"), []byte("new")))
+	require.NoError(t, ts.Put([]byte("added"), []byte("value")))
+	require.NoError(t, ts.Delete([]byte("deleted")))
+
+	b := ts.GetReverseBatch()
+	require.ElementsMatch(t, []KeyValue{
+		{Key: []byte("changed"), Value: []byte("old"), Exists: true},
+		{Key: []byte("deleted"), Value: []byte("value"), Exists: true},
+	}, b.Put)
+	require.Equal(t, []KeyValue{{Key: []byte("added")}}, b.Deleted)
+}
+
+func TestMemCachedSnapshot(t *testing.T) {
+	ps := NewMemoryStore()
+	require.NoError(t, ps.Put([]byte("lower"), []byte("old")))
+	require.NoError(t, ps.Put([]byte("deleted"), []byte("value")))
+
+	ts := NewMemCachedStore(ps)
+	require.NoError(t, ts.Put([]byte("cached"), []byte("old")))
+	require.NoError(t, ts.Delete([]byte("deleted")))
+
+	snaps := make([]Snapshot, 2)
+	for i := range snaps {
+		var err error
+		snaps[i], err = ts.Snapshot()
+		require.NoError(t, err)
+		defer snaps[i].Release()
+	}
+	// Data is not copied until the store is changed.
+	require.True(t, ts.shared)
+	require.True(t, ps.shared)
+
+	require.NoError(t, ts.Put([]byte("cached"), []byte("new")))
+	require.NoError(t, ts.Put([]byte("lower"), []byte("new")))
+	require.NoError(t, ts.Put([]byte("deleted"), []byte("new")))
+	require.False(t, ts.shared)
+	_, err := ts.Persist()
+	require.NoError(t, err)
+	require.False(t, ps.shared)
+
+	for _, snap := range snaps {
+		val, err := snap.Get([]byte("cached"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("old"), val)
+		val, err = snap.Get([]byte("lower"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("old"), val)
+		_, err = snap.Get([]byte("deleted"))
+		require.Equal(t, ErrKeyNotFound, err)
+	}
+}
+
+func TestMemCachedIsChanged(t *testing.T) {
+	ps := NewMemoryStore()
+	require.NoError(t, ps.Put([]byte("persisted"), []byte("value")))
+	require.NoError(t, ps.Put([]byte("deleted"), []byte("value")))
+
+	ts := NewMemCachedStore(ps)
+	require.NoError(t, ts.Put([]byte("added"), []byte("value")))
+	require.NoError(t, ts.Delete([]byte("deleted")))
+	require.True(t, ts.IsChanged([]byte("added")))
+	require.True(t, ts.IsChanged([]byte("deleted")))
+	require.False(t, ts.IsChanged([]byte("persisted")))
+
+	var changed []string
+	ts.IterateChanged(func(k []byte) { changed = append(changed, string(k)) })
+	require.ElementsMatch(t, []string{"added", "deleted"}, changed)
+
+	_, err := ts.Persist()
+	require.NoError(t, err)
+	require.False(t, ts.IsChanged([]byte("added")))
+	require.False(t, ts.IsChanged([]byte("deleted")))
+}
+
 func TestCachedGetFromPersistent(t *testing.T) {
 	key := []byte("key")
 	value := []byte("value")
@@ -12,6 +12,9 @@ type MemoryStore struct {
 	mem map[string][]byte
 	// A map, not a slice, to avoid duplicates.
 	del map[string]bool
+	// shared is set when mem and del maps are used by snapshots, so they
+	// have to be copied before any modification.
+	shared bool
 }
 
 // MemoryBatch is an in-memory batch compatible with MemoryStore.
@@ -47,9 +50,27 @@ func (s *MemoryStore) Get(key []byte) ([]byte, error) {
 	return nil, ErrKeyNotFound
 }
 
+// unshare makes private copies of mem and del maps if they're used by
+// snapshots, it's supposed to be called with mutex locked.
+func (s *MemoryStore) unshare() {
+	if !s.shared {
+		return
+	}
+	mem := make(map[string][]byte, len(s.mem))
+	for k, v := range s.mem {
+		mem[k] = v
+	}
+	del := make(map[string]bool, len(s.del))
+	for k := range s.del {
+		del[k] = true
+	}
+	s.mem, s.del, s.shared = mem, del, false
+}
+
 // put puts a key-value pair into the store, it's supposed to be called
 // with mutex locked.
 func (s *MemoryStore) put(key string, value []byte) {
+	s.unshare()
 	s.mem[key] = value
 	delete(s.del, key)
 }
@@ -68,6 +89,7 @@ func (s *MemoryStore) Put(key, value []byte) error {
 // drop deletes a key-value pair from the store, it's supposed to be called
 // with mutex locked.
 func (s *MemoryStore) drop(key string) {
+	s.unshare()
 	s.del[key] = true
 	delete(s.mem, key)
 }
@@ -111,6 +133,33 @@ func (s *MemoryStore) seek(key []byte, f func(k, v []byte)) {
 	}
 }
 
+// Snapshot implements the Snapshotter interface. The snapshot shares store
+// contents with the MemoryStore until the next change made to it, so that
+// only the first change after snapshot creation copies the data.
+func (s *MemoryStore) Snapshot() (Snapshot, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return memorySnapshot{s.share()}, nil
+}
+
+// share returns a MemoryStore sharing mem and del maps with s, it's supposed
+// to be called with mutex locked. Neither of them can be changed in place
+// after that.
+func (s *MemoryStore) share() *MemoryStore {
+	s.shared = true
+	return &MemoryStore{mem: s.mem, del: s.del, shared: true}
+}
+
+// memorySnapshot is a Snapshot over MemoryStore data.
+type memorySnapshot struct {
+	*MemoryStore
+}
+
+// Release implements the Snapshot interface.
+func (s memorySnapshot) Release() {
+	_ = s.Close()
+}
+
 // Batch implements the Batch interface and returns a compatible Batch.
 func (s *MemoryStore) Batch() Batch {
 	return newMemoryBatch()
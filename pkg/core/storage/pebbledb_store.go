@@ -0,0 +1,165 @@
+//go:build pebble
+// +build pebble
+
+package storage
+
+import (
+	"io"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// PebbleDBStore is the storage implementation using PebbleDB, an LSM-tree
+// key-value store. It's only available when built with "pebble" tag.
+type PebbleDBStore struct {
+	db *pebble.DB
+}
+
+// PebbleDBBatch is a wrapper around pebble.Batch, compatible with Batch
+// interface.
+type PebbleDBBatch struct {
+	batch *pebble.Batch
+}
+
+// pebbleReader is the part of pebble API common for DB and Snapshot.
+type pebbleReader interface {
+	Get(key []byte) ([]byte, io.Closer, error)
+	NewIter(o *pebble.IterOptions) *pebble.Iterator
+}
+
+// Delete implements the Batch interface.
+func (b *PebbleDBBatch) Delete(key []byte) {
+	if err := b.batch.Delete(key, nil); err != nil {
+		panic(err)
+	}
+}
+
+// Put implements the Batch interface. Key and value are copied into the
+// batch.
+func (b *PebbleDBBatch) Put(key, value []byte) {
+	if err := b.batch.Set(key, value, nil); err != nil {
+		panic(err)
+	}
+}
+
+// NewPebbleDBStore returns a new PebbleDBStore object that will
+// initialize the database found at the given path.
+func NewPebbleDBStore(cfg PebbleDBOptions) (*PebbleDBStore, error) {
+	var opts *pebble.Options // should be exposed via PebbleDBOptions if anything needed
+
+	db, err := pebble.Open(cfg.DataDirectoryPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &PebbleDBStore{db: db}, nil
+}
+
+// newPebbleDBStore is used by NewStore to create PebbleDBStore.
+func newPebbleDBStore(cfg PebbleDBOptions) (Store, error) {
+	s, err := NewPebbleDBStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Batch implements the Store interface and returns a pebble compatible
+// Batch.
+func (s *PebbleDBStore) Batch() Batch {
+	return &PebbleDBBatch{s.db.NewBatch()}
+}
+
+// Delete implements the Store interface.
+func (s *PebbleDBStore) Delete(key []byte) error {
+	return s.db.Delete(key, pebble.Sync)
+}
+
+// Get implements the Store interface.
+func (s *PebbleDBStore) Get(key []byte) ([]byte, error) {
+	return pebbleGet(s.db, key)
+}
+
+// Put implements the Store interface.
+func (s *PebbleDBStore) Put(key, value []byte) error {
+	return s.db.Set(key, value, pebble.Sync)
+}
+
+// PutBatch implements the Store interface.
+func (s *PebbleDBStore) PutBatch(batch Batch) error {
+	return batch.(*PebbleDBBatch).batch.Commit(pebble.Sync)
+}
+
+// Seek implements the Store interface.
+func (s *PebbleDBStore) Seek(key []byte, f func(k, v []byte)) {
+	pebbleSeek(s.db, key, f)
+}
+
+// Snapshot implements the Snapshotter interface.
+func (s *PebbleDBStore) Snapshot() (Snapshot, error) {
+	return &pebbleDBSnapshot{s.db.NewSnapshot()}, nil
+}
+
+// Compact implements the Compactor interface, it compacts the whole key
+// range used by KeyPrefix constants.
+func (s *PebbleDBStore) Compact() error {
+	return s.db.Compact(nil, []byte{0xff})
+}
+
+// Stats implements the StatsReporter interface.
+func (s *PebbleDBStore) Stats() (string, error) {
+	return s.db.Metrics().String(), nil
+}
+
+// Close implements the Store interface.
+func (s *PebbleDBStore) Close() error {
+	return s.db.Close()
+}
+
+// pebbleDBSnapshot is a Snapshot over pebble.Snapshot.
+type pebbleDBSnapshot struct {
+	snap *pebble.Snapshot
+}
+
+// Get implements the Snapshot interface.
+func (s *pebbleDBSnapshot) Get(key []byte) ([]byte, error) {
+	return pebbleGet(s.snap, key)
+}
+
+// Seek implements the Snapshot interface.
+func (s *pebbleDBSnapshot) Seek(key []byte, f func(k, v []byte)) {
+	pebbleSeek(s.snap, key, f)
+}
+
+// Release implements the Snapshot interface.
+func (s *pebbleDBSnapshot) Release() {
+	_ = s.snap.Close()
+}
+
+// pebbleGet returns a copy of the value stored for the key.
+func pebbleGet(r pebbleReader, key []byte) ([]byte, error) {
+	val, closer, err := r.Get(key)
+	if err == pebble.ErrNotFound {
+		return nil, ErrKeyNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	res := make([]byte, len(val))
+	copy(res, val)
+	return res, closer.Close()
+}
+
+// pebbleSeek iterates over all items with the given key prefix.
+func pebbleSeek(r pebbleReader, key []byte, f func(k, v []byte)) {
+	prefix := util.BytesPrefix(key)
+	iter := r.NewIter(&pebble.IterOptions{
+		LowerBound: prefix.Start,
+		UpperBound: prefix.Limit,
+	})
+	for iter.First(); iter.Valid(); iter.Next() {
+		f(iter.Key(), iter.Value())
+	}
+	if err := iter.Close(); err != nil {
+		panic(err)
+	}
+}
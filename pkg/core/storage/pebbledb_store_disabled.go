@@ -0,0 +1,12 @@
+//go:build !pebble
+// +build !pebble
+
+package storage
+
+import "errors"
+
+// newPebbleDBStore is used by NewStore to create PebbleDBStore, it always
+// fails as PebbleDB support is not compiled in.
+func newPebbleDBStore(PebbleDBOptions) (Store, error) {
+	return nil, errors.New("PebbleDB support is not compiled in, rebuild with \"pebble\" tag")
+}
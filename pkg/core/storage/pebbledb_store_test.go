@@ -0,0 +1,53 @@
+//go:build pebble
+// +build pebble
+
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type tempPebbleDB struct {
+	*PebbleDBStore
+	dir string
+}
+
+func (tpdb *tempPebbleDB) Close() error {
+	err := tpdb.PebbleDBStore.Close()
+	// Make test fail if failed to cleanup, even though technically it's
+	// not a PebbleDBStore problem.
+	osErr := os.RemoveAll(tpdb.dir)
+	if osErr != nil {
+		return osErr
+	}
+	return err
+}
+
+func newPebbleDBForTesting(t *testing.T) Store {
+	pdbDir, err := ioutil.TempDir(os.TempDir(), "testpebbledb")
+	require.Nil(t, err, "failed to setup temporary directory")
+
+	newPebbleStore, err := NewPebbleDBStore(PebbleDBOptions{DataDirectoryPath: pdbDir})
+	require.Nil(t, err, "NewPebbleDBStore error")
+	return &tempPebbleDB{
+		PebbleDBStore: newPebbleStore,
+		dir:           pdbDir,
+	}
+}
+
+func TestPebbleDB(t *testing.T) {
+	for _, test := range storeTests {
+		s := newPebbleDBForTesting(t)
+		twrapper := func(t *testing.T) {
+			test(t, s)
+		}
+		fname := runtime.FuncForPC(reflect.ValueOf(test).Pointer()).Name()
+		t.Run(fname, twrapper)
+	}
+}
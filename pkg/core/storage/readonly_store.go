@@ -0,0 +1,43 @@
+package storage
+
+import "fmt"
+
+// readOnlyDBStore is a Store opened in read-only mode, it rejects all
+// modifications with ErrReadOnly.
+type readOnlyDBStore struct {
+	Store
+}
+
+// newReadOnlyDBStore opens the DB configured in read-only mode. Only Redis
+// supports it as it's the only DB that can be shared with the writer
+// (BoltDB, BadgerDB and LevelDB lock their files for exclusive use).
+func newReadOnlyDBStore(cfg DBConfiguration) (Store, error) {
+	if cfg.Type != "redis" {
+		return nil, fmt.Errorf("read-only mode is not supported for %q DB", cfg.Type)
+	}
+	store, err := NewRedisStore(cfg.RedisDBOptions)
+	if err != nil {
+		return nil, err
+	}
+	return &readOnlyDBStore{store}, nil
+}
+
+// Delete implements the Store interface, it always returns ErrReadOnly.
+func (s *readOnlyDBStore) Delete(k []byte) error {
+	return ErrReadOnly
+}
+
+// Put implements the Store interface, it always returns ErrReadOnly.
+func (s *readOnlyDBStore) Put(k, v []byte) error {
+	return ErrReadOnly
+}
+
+// PutBatch implements the Store interface, it always returns ErrReadOnly.
+func (s *readOnlyDBStore) PutBatch(Batch) error {
+	return ErrReadOnly
+}
+
+// Snapshot implements the Snapshotter interface using the underlying Store.
+func (s *readOnlyDBStore) Snapshot() (Snapshot, error) {
+	return NewSnapshot(s.Store)
+}
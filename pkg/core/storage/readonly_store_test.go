@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/alicebob/miniredis"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnlyStore(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "testreadonly")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, cfg := range []DBConfiguration{
+		{Type: "inmemory"},
+		{Type: "leveldb", LevelDBOptions: LevelDBOptions{DataDirectoryPath: path.Join(dir, "leveldb")}},
+		{Type: "boltdb", BoltDBOptions: BoltDBOptions{FilePath: path.Join(dir, "bolt")}},
+		{Type: "badgerdb", BadgerDBOptions: BadgerDBOptions{Dir: path.Join(dir, "badger")}},
+	} {
+		cfg.ReadOnly = true
+		_, err := NewStore(cfg)
+		require.Error(t, err, cfg.Type)
+	}
+}
+
+func TestReadOnlyStoreRedis(t *testing.T) {
+	mini, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mini.Close()
+
+	cfg := DBConfiguration{
+		Type:           "redis",
+		RedisDBOptions: RedisDBOptions{Addr: mini.Addr()},
+	}
+	w, err := NewStore(cfg)
+	require.NoError(t, err)
+	defer w.Close()
+	require.NoError(t, w.Put([]byte("key"), []byte("value")))
+
+	cfg.ReadOnly = true
+	s, err := NewStore(cfg)
+	require.NoError(t, err)
+	defer s.Close()
+	v, err := s.Get([]byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), v)
+	require.Equal(t, ErrReadOnly, s.Put([]byte("key"), []byte("new")))
+	require.Equal(t, ErrReadOnly, s.Delete([]byte("key")))
+	require.Equal(t, ErrReadOnly, s.PutBatch(s.Batch()))
+
+	// Changes made by the writer are visible to the reader.
+	require.NoError(t, w.Put([]byte("key"), []byte("new")))
+	b := w.Batch()
+	b.Put([]byte("other"), []byte("value"))
+	require.NoError(t, w.PutBatch(b))
+	v, err = s.Get([]byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("new"), v)
+	v, err = s.Get([]byte("other"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), v)
+
+	snap, err := NewSnapshot(s)
+	require.NoError(t, err)
+	defer snap.Release()
+	v, err = snap.Get([]byte("other"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), v)
+}
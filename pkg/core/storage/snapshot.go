@@ -0,0 +1,89 @@
+package storage
+
+import "errors"
+
+// ErrReadOnly is returned by the Store created with NewReadOnlyStore on any
+// attempt to change it.
+var ErrReadOnly = errors.New("store is read-only")
+
+type (
+	// Snapshot is a read-only view of the Store contents as they were at the
+	// moment of snapshot creation. It must be released after use.
+	Snapshot interface {
+		Get([]byte) ([]byte, error)
+		Seek(k []byte, f func(k, v []byte))
+		Release()
+	}
+
+	// Snapshotter is implemented by Stores able to create consistent
+	// Snapshots of their contents.
+	Snapshotter interface {
+		Snapshot() (Snapshot, error)
+	}
+
+	// storeView is a Snapshot for Stores that don't implement Snapshotter,
+	// it just passes all reads to the Store.
+	storeView struct {
+		Store
+	}
+
+	// readOnlyStore is a Store over Snapshot.
+	readOnlyStore struct {
+		snap Snapshot
+	}
+)
+
+// NewSnapshot returns a Snapshot of the given Store. If the Store doesn't
+// implement Snapshotter, reads from the Snapshot are not isolated from the
+// changes made to the Store after its creation.
+func NewSnapshot(s Store) (Snapshot, error) {
+	if ss, ok := s.(Snapshotter); ok {
+		return ss.Snapshot()
+	}
+	return storeView{s}, nil
+}
+
+// Release implements the Snapshot interface, it's a no-op for storeView.
+func (storeView) Release() {}
+
+// NewReadOnlyStore returns a Store reading data from the given Snapshot. All
+// modifications return ErrReadOnly, Close releases the Snapshot.
+func NewReadOnlyStore(snap Snapshot) Store {
+	return &readOnlyStore{snap: snap}
+}
+
+// Batch implements the Store interface.
+func (s *readOnlyStore) Batch() Batch {
+	return newMemoryBatch()
+}
+
+// Delete implements the Store interface, it always returns ErrReadOnly.
+func (s *readOnlyStore) Delete(k []byte) error {
+	return ErrReadOnly
+}
+
+// Get implements the Store interface.
+func (s *readOnlyStore) Get(k []byte) ([]byte, error) {
+	return s.snap.Get(k)
+}
+
+// Put implements the Store interface, it always returns ErrReadOnly.
+func (s *readOnlyStore) Put(k, v []byte) error {
+	return ErrReadOnly
+}
+
+// PutBatch implements the Store interface, it always returns ErrReadOnly.
+func (s *readOnlyStore) PutBatch(Batch) error {
+	return ErrReadOnly
+}
+
+// Seek implements the Store interface.
+func (s *readOnlyStore) Seek(k []byte, f func(k, v []byte)) {
+	s.snap.Seek(k, f)
+}
+
+// Close implements the Store interface and releases the Snapshot.
+func (s *readOnlyStore) Close() error {
+	s.snap.Release()
+	return nil
+}
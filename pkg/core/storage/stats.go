@@ -0,0 +1,47 @@
+package storage
+
+type (
+	// Compactor is implemented by Stores able to compact their data on
+	// demand.
+	Compactor interface {
+		Compact() error
+	}
+
+	// StatsReporter is implemented by Stores able to report some
+	// backend-specific statistics in human-readable form.
+	StatsReporter interface {
+		Stats() (string, error)
+	}
+
+	// PrefixStats contains the number of entries stored with some KeyPrefix
+	// and their total size.
+	PrefixStats struct {
+		Keys      int
+		KeySize   int64
+		ValueSize int64
+	}
+)
+
+// CollectStats iterates over all entries of the Store and returns statistics
+// for every KeyPrefix found.
+func CollectStats(s Store) map[KeyPrefix]*PrefixStats {
+	res := make(map[KeyPrefix]*PrefixStats)
+	for i := 0; i <= 0xff; i++ {
+		prefix := KeyPrefix(i)
+		s.Seek(prefix.Bytes(), func(k, v []byte) {
+			// Some Stores may return keys outside of the prefix range.
+			if len(k) == 0 || KeyPrefix(k[0]) != prefix {
+				return
+			}
+			ps := res[prefix]
+			if ps == nil {
+				ps = new(PrefixStats)
+				res[prefix] = ps
+			}
+			ps.Keys++
+			ps.KeySize += int64(len(k))
+			ps.ValueSize += int64(len(v))
+		})
+	}
+	return res
+}
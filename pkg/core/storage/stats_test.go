@@ -0,0 +1,19 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectStats(t *testing.T) {
+	s := NewMemoryStore()
+	require.NoError(t, s.Put(AppendPrefix(STStorage, []byte{1, 2}), []byte{1, 2, 3}))
+	require.NoError(t, s.Put(AppendPrefix(STStorage, []byte{3}), []byte{4}))
+	require.NoError(t, s.Put(SYSVersion.Bytes(), []byte("0.1.0")))
+
+	require.Equal(t, map[KeyPrefix]*PrefixStats{
+		STStorage:  {Keys: 2, KeySize: 5, ValueSize: 4},
+		SYSVersion: {Keys: 1, KeySize: 1, ValueSize: 5},
+	}, CollectStats(s))
+}
@@ -3,12 +3,14 @@ package storage
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 )
 
 // KeyPrefix constants.
 const (
 	DataBlock         KeyPrefix = 0x01
 	DataTransaction   KeyPrefix = 0x02
+	DataUndo          KeyPrefix = 0x03
 	STAccount         KeyPrefix = 0x40
 	STCoin            KeyPrefix = 0x44
 	STSpentCoin       KeyPrefix = 0x45
@@ -25,9 +27,35 @@ const (
 	IXValidatorsCount KeyPrefix = 0x90
 	SYSCurrentBlock   KeyPrefix = 0xc0
 	SYSCurrentHeader  KeyPrefix = 0xc1
+	SYSConsensus      KeyPrefix = 0xc2
 	SYSVersion        KeyPrefix = 0xf0
 )
 
+// keyPrefixNames contains names of known KeyPrefix constants.
+var keyPrefixNames = map[KeyPrefix]string{
+	DataBlock:         "DataBlock",
+	DataTransaction:   "DataTransaction",
+	DataUndo:          "DataUndo",
+	STAccount:         "STAccount",
+	STCoin:            "STCoin",
+	STSpentCoin:       "STSpentCoin",
+	STNextValidators:  "STNextValidators",
+	STValidator:       "STValidator",
+	STAsset:           "STAsset",
+	STNotification:    "STNotification",
+	STContract:        "STContract",
+	STNativeContract:  "STNativeContract",
+	STStorage:         "STStorage",
+	STNEP5Transfers:   "STNEP5Transfers",
+	STNEP5Balances:    "STNEP5Balances",
+	IXHeaderHashList:  "IXHeaderHashList",
+	IXValidatorsCount: "IXValidatorsCount",
+	SYSCurrentBlock:   "SYSCurrentBlock",
+	SYSCurrentHeader:  "SYSCurrentHeader",
+	SYSConsensus:      "SYSConsensus",
+	SYSVersion:        "SYSVersion",
+}
+
 // ErrKeyNotFound is an error returned by Store implementations
 // when a certain key is not found.
 var ErrKeyNotFound = errors.New("key not found")
@@ -53,11 +81,35 @@ type (
 		Put(k, v []byte)
 	}
 
+	// SyncPutter is implemented by Stores that don't flush every Put to
+	// stable storage, but can do that for particular writes.
+	SyncPutter interface {
+		PutSync(k, v []byte) error
+	}
+
 	// KeyPrefix is a constant byte added as a prefix for each key
 	// stored.
 	KeyPrefix uint8
 )
 
+// PutSync puts the key-value pair into the Store and returns only after it
+// reaches stable storage (if the Store is persistent at all). Stores not
+// implementing SyncPutter are expected to sync every Put.
+func PutSync(s Store, k, v []byte) error {
+	if sp, ok := s.(SyncPutter); ok {
+		return sp.PutSync(k, v)
+	}
+	return s.Put(k, v)
+}
+
+// String implements the fmt.Stringer interface.
+func (k KeyPrefix) String() string {
+	if name, ok := keyPrefixNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%02x", uint8(k))
+}
+
 // Bytes returns the bytes representation of KeyPrefix.
 func (k KeyPrefix) Bytes() []byte {
 	return []byte{byte(k)}
@@ -84,6 +136,9 @@ func AppendPrefixInt(k KeyPrefix, n int) []byte {
 func NewStore(cfg DBConfiguration) (Store, error) {
 	var store Store
 	var err error
+	if cfg.ReadOnly {
+		return newReadOnlyDBStore(cfg)
+	}
 	switch cfg.Type {
 	case "leveldb":
 		store, err = NewLevelDBStore(cfg.LevelDBOptions)
@@ -95,6 +150,8 @@ func NewStore(cfg DBConfiguration) (Store, error) {
 		store, err = NewBoltDBStore(cfg.BoltDBOptions)
 	case "badgerdb":
 		store, err = NewBadgerDBStore(cfg.BadgerDBOptions)
+	case "pebbledb":
+		store, err = newPebbleDBStore(cfg.PebbleDBOptions)
 	}
 	return store, err
 }
@@ -1,12 +1,22 @@
 package storage
 
 type (
-	// DBConfiguration describes configuration for DB. Supported: 'levelDB', 'redisDB', 'boltDB', 'badgerDB'.
+	// DBConfiguration describes configuration for DB. Supported: 'levelDB', 'redisDB', 'boltDB', 'badgerDB'
+	// and 'pebbleDB' (if built with "pebble" tag).
 	DBConfiguration struct {
 		Type            string          `yaml:"Type"`
 		LevelDBOptions  LevelDBOptions  `yaml:"LevelDBOptions"`
 		RedisDBOptions  RedisDBOptions  `yaml:"RedisDBOptions"`
 		BoltDBOptions   BoltDBOptions   `yaml:"BoltDBOptions"`
 		BadgerDBOptions BadgerDBOptions `yaml:"BadgerDBOptions"`
+		PebbleDBOptions PebbleDBOptions `yaml:"PebbleDBOptions"`
+		// ReadOnly opens the DB in read-only mode for the secondary node
+		// (supported for 'redis' only).
+		ReadOnly bool `yaml:"ReadOnly"`
 	}
 )
+
+// PebbleDBOptions configuration for PebbleDB.
+type PebbleDBOptions struct {
+	DataDirectoryPath string `yaml:"DataDirectoryPath"`
+}
@@ -20,6 +20,7 @@ var (
 		IXValidatorsCount,
 		SYSCurrentBlock,
 		SYSCurrentHeader,
+		SYSConsensus,
 		SYSVersion,
 	}
 
@@ -36,6 +37,7 @@ var (
 		0x90,
 		0xc0,
 		0xc1,
+		0xc2,
 		0xf0,
 	}
 )
@@ -55,3 +57,9 @@ func TestAppendPrefixInt(t *testing.T) {
 		assert.Equal(t, KeyPrefix(expected[i]), KeyPrefix(prefix[0]))
 	}
 }
+
+func TestKeyPrefixString(t *testing.T) {
+	assert.Equal(t, "STStorage", STStorage.String())
+	assert.Equal(t, "DataUndo", DataUndo.String())
+	assert.Equal(t, "0x2a", KeyPrefix(0x2a).String())
+}
@@ -40,6 +40,19 @@ func testStorePutAndGet(t *testing.T, s Store) {
 	require.NoError(t, s.Close())
 }
 
+func testStorePutSync(t *testing.T, s Store) {
+	key := []byte("foo")
+	value := []byte("bar")
+
+	require.NoError(t, PutSync(s, key, value))
+
+	result, err := s.Get(key)
+	assert.Nil(t, err)
+	require.Equal(t, value, result)
+
+	require.NoError(t, s.Close())
+}
+
 func testStoreGetNonExistent(t *testing.T, s Store) {
 	key := []byte("sparse")
 
@@ -217,6 +230,83 @@ func testStorePutBatchWithDelete(t *testing.T, s Store) {
 	require.NoError(t, s.Close())
 }
 
+func testStoreSnapshot(t *testing.T, s Store) {
+	require.NoError(t, s.Put([]byte("foo"), []byte("bar")))
+	require.NoError(t, s.Put([]byte("faa"), []byte("bra")))
+
+	snap, err := NewSnapshot(s)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Put([]byte("foo"), []byte("baz")))
+	require.NoError(t, s.Delete([]byte("faa")))
+	require.NoError(t, s.Put([]byte("fee"), []byte("bee")))
+
+	if _, ok := s.(Snapshotter); ok {
+		val, err := snap.Get([]byte("foo"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("bar"), val)
+		val, err = snap.Get([]byte("faa"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("bra"), val)
+		_, err = snap.Get([]byte("fee"))
+		require.Equal(t, ErrKeyNotFound, err)
+
+		seen := make(map[string]string)
+		snap.Seek([]byte("f"), func(k, v []byte) {
+			seen[string(k)] = string(v)
+		})
+		require.Equal(t, map[string]string{"foo": "bar", "faa": "bra"}, seen)
+	}
+
+	ro := NewReadOnlyStore(snap)
+	require.Equal(t, ErrReadOnly, ro.Put([]byte("foo"), []byte("bar")))
+	require.Equal(t, ErrReadOnly, ro.Delete([]byte("foo")))
+	require.Equal(t, ErrReadOnly, ro.PutBatch(ro.Batch()))
+	_, err = ro.Get([]byte("foo"))
+	require.NoError(t, err)
+	require.NoError(t, ro.Close())
+
+	val, err := s.Get([]byte("foo"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("baz"), val)
+	require.NoError(t, s.Close())
+}
+
+func testStoreCompact(t *testing.T, s Store) {
+	c, ok := s.(Compactor)
+	if !ok {
+		require.NoError(t, s.Close())
+		return
+	}
+	for i := 0; i < 100; i++ {
+		require.NoError(t, s.Put([]byte{byte(i)}, []byte{byte(i)}))
+	}
+	for i := 0; i < 100; i += 2 {
+		require.NoError(t, s.Delete([]byte{byte(i)}))
+	}
+	require.NoError(t, c.Compact())
+	for i := 0; i < 100; i++ {
+		val, err := s.Get([]byte{byte(i)})
+		if i%2 == 0 {
+			require.Equal(t, ErrKeyNotFound, err)
+		} else {
+			require.NoError(t, err)
+			require.Equal(t, []byte{byte(i)}, val)
+		}
+	}
+	if r, ok := s.(StatsReporter); ok {
+		_, err := r.Stats()
+		require.NoError(t, err)
+	}
+	require.NoError(t, s.Close())
+}
+
+// storeTests are run for every Store implementation.
+var storeTests = []dbTestFunction{testStoreClose, testStorePutAndGet, testStorePutSync,
+	testStoreGetNonExistent, testStorePutBatch, testStoreSeek,
+	testStoreDeleteNonExistent, testStorePutAndDelete,
+	testStorePutBatchWithDelete, testStoreSnapshot, testStoreCompact}
+
 func TestAllDBs(t *testing.T) {
 	var DBs = []dbSetup{
 		{"BoltDB", newBoltStoreForTesting},
@@ -226,12 +316,8 @@ func TestAllDBs(t *testing.T) {
 		{"RedisDB", newRedisStoreForTesting},
 		{"BadgerDB", newBadgerDBForTesting},
 	}
-	var tests = []dbTestFunction{testStoreClose, testStorePutAndGet,
-		testStoreGetNonExistent, testStorePutBatch, testStoreSeek,
-		testStoreDeleteNonExistent, testStorePutAndDelete,
-		testStorePutBatchWithDelete}
 	for _, db := range DBs {
-		for _, test := range tests {
+		for _, test := range storeTests {
 			s := db.create(t)
 			twrapper := func(t *testing.T) {
 				test(t, s)
@@ -0,0 +1,182 @@
+package keys
+
+import (
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// HardenedKeyStart is the index of the first hardened child key.
+const HardenedKeyStart uint32 = 0x80000000
+
+// masterKeyHMACKey is the SLIP-10 HMAC key used for secp256r1 master key
+// generation.
+var masterKeyHMACKey = []byte("Nist256p1 seed")
+
+// ExtendedKey is a BIP-32 private extended key on secp256r1 curve. Derivation
+// follows SLIP-10 which specifies BIP-32 for curves other than secp256k1.
+type ExtendedKey struct {
+	key       []byte
+	chainCode []byte
+	depth     uint8
+	index     uint32
+}
+
+// NewMasterKey returns the master extended key for the given seed (which is
+// usually derived from BIP-39 mnemonic).
+func NewMasterKey(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("invalid seed length %d", len(seed))
+	}
+	i := hmacSHA512(masterKeyHMACKey, seed)
+	n := elliptic.P256().Params().N
+	for {
+		k := new(big.Int).SetBytes(i[:32])
+		if k.Sign() != 0 && k.Cmp(n) < 0 {
+			break
+		}
+		i = hmacSHA512(masterKeyHMACKey, i)
+	}
+	return &ExtendedKey{key: i[:32], chainCode: i[32:]}, nil
+}
+
+// Child derives the child extended key with the given index, indexes starting
+// from HardenedKeyStart produce hardened keys.
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	if k.depth == 255 {
+		return nil, errors.New("maximum derivation depth reached")
+	}
+	var data []byte
+	if index >= HardenedKeyStart {
+		data = append([]byte{0}, k.key...)
+	} else {
+		data = k.PrivateKey().PublicKey().Bytes()
+	}
+	data = append(data, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[len(data)-4:], index)
+
+	var (
+		n      = elliptic.P256().Params().N
+		parent = new(big.Int).SetBytes(k.key)
+		i      = hmacSHA512(k.chainCode, data)
+	)
+	for {
+		il := new(big.Int).SetBytes(i[:32])
+		if il.Cmp(n) < 0 {
+			il.Add(il, parent)
+			il.Mod(il, n)
+			if il.Sign() != 0 {
+				key := make([]byte, 32)
+				b := il.Bytes()
+				copy(key[32-len(b):], b)
+				return &ExtendedKey{
+					key:       key,
+					chainCode: i[32:],
+					depth:     k.depth + 1,
+					index:     index,
+				}, nil
+			}
+		}
+		data = append([]byte{1}, i[32:]...)
+		data = append(data, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(data[len(data)-4:], index)
+		i = hmacSHA512(k.chainCode, data)
+	}
+}
+
+// Derive derives the extended key for the given path relative to k.
+func (k *ExtendedKey) Derive(path []uint32) (*ExtendedKey, error) {
+	var err error
+	for _, index := range path {
+		k, err = k.Child(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+// DerivePath derives the extended key for the given derivation path string
+// in the "m/44'/888'/0'/0/0" form (k is considered to be the master key).
+func (k *ExtendedKey) DerivePath(path string) (*ExtendedKey, error) {
+	p, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return k.Derive(p)
+}
+
+// PrivateKey returns the private key of the extended key.
+func (k *ExtendedKey) PrivateKey() *PrivateKey {
+	return &PrivateKey{b: k.key}
+}
+
+// ChainCode returns the chain code of the extended key.
+func (k *ExtendedKey) ChainCode() []byte {
+	return k.chainCode
+}
+
+// Depth returns the depth of the extended key (0 for the master key).
+func (k *ExtendedKey) Depth() uint8 {
+	return k.depth
+}
+
+// Index returns the index the extended key was derived with.
+func (k *ExtendedKey) Index() uint32 {
+	return k.index
+}
+
+// ParseDerivationPath parses the derivation path string in the
+// "m/44'/888'/0'/0/0" form, hardened indexes are marked with ' or h suffix.
+func ParseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("derivation path %q doesn't start with 'm'", path)
+	}
+	res := make([]uint32, 0, len(parts)-1)
+	for _, s := range parts[1:] {
+		var hardened bool
+		if strings.HasSuffix(s, "'") || strings.HasSuffix(s, "h") {
+			hardened = true
+			s = s[:len(s)-1]
+		}
+		i, err := strconv.ParseUint(s, 10, 32)
+		if err != nil || uint32(i) >= HardenedKeyStart {
+			return nil, fmt.Errorf("invalid derivation path element %q", s)
+		}
+		if hardened {
+			i += uint64(HardenedKeyStart)
+		}
+		res = append(res, uint32(i))
+	}
+	return res, nil
+}
+
+// FormatDerivationPath returns the string representation of the derivation
+// path, it's the reverse of ParseDerivationPath.
+func FormatDerivationPath(path []uint32) string {
+	var sb strings.Builder
+	sb.WriteString("m")
+	for _, i := range path {
+		sb.WriteByte('/')
+		if i >= HardenedKeyStart {
+			sb.WriteString(strconv.FormatUint(uint64(i-HardenedKeyStart), 10))
+			sb.WriteByte('\'')
+		} else {
+			sb.WriteString(strconv.FormatUint(uint64(i), 10))
+		}
+	}
+	return sb.String()
+}
+
+func hmacSHA512(key, data []byte) []byte {
+	h := hmac.New(sha512.New, key)
+	_, _ = h.Write(data)
+	return h.Sum(nil)
+}
@@ -0,0 +1,47 @@
+package keys
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtendedKeySLIP10(t *testing.T) {
+	// Test vector 1 for nist256p1 from
+	// https://github.com/satoshilabs/slips/blob/master/slip-0010.md
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	require.NoError(t, err)
+	testCases := []struct {
+		path      string
+		chainCode string
+		key       string
+	}{
+		{"m", "beeb672fe4621673f722f38529c07392fecaa61015c80c34f29ce8b41b3cb6ea", "612091aaa12e22dd2abef664f8a01a82cae99ad7441b7ef8110424915c268bc2"},
+		{"m/0'", "3460cea53e6a6bb5fb391eeef3237ffd8724bf0a40e94943c98b83825342ee11", "6939694369114c67917a182c59ddb8cafc3004e63ca5d3b84403ba8613debc0c"},
+		{"m/0'/1", "4187afff1aafa8445010097fb99d23aee9f599450c7bd140b6826ac22ba21d0c", "284e9d38d07d21e4e281b645089a94f4cf5a5a81369acf151a1c3a57f18b2129"},
+		{"m/0'/1/2'", "98c7514f562e64e74170cc3cf304ee1ce54d6b6da4f880f313e8204c2a185318", "694596e8a54f252c960eb771a3c41e7e32496d03b954aeb90f61635b8e092aa7"},
+		{"m/0'/1/2'/2", "ba96f776a5c3907d7fd48bde5620ee374d4acfd540378476019eab70790c63a0", "5996c37fd3dd2679039b23ed6f70b506c6b56b3cb5e424681fb0fa64caf82aaa"},
+		{"m/0'/1/2'/2/1000000000", "b9b7b82d326bb9cb5b5b121066feea4eb93d5241103c9e7a18aad40f1dde8059", "21c4f269ef0a5fd1badf47eeacebeeaa3de22eb8e5b0adcd0f27dd99d34d0119"},
+	}
+	master, err := NewMasterKey(seed)
+	require.NoError(t, err)
+	for _, tc := range testCases {
+		k, err := master.DerivePath(tc.path)
+		require.NoError(t, err, tc.path)
+		require.Equal(t, tc.chainCode, hex.EncodeToString(k.ChainCode()), tc.path)
+		require.Equal(t, tc.key, hex.EncodeToString(k.PrivateKey().Bytes()), tc.path)
+	}
+}
+
+func TestParseDerivationPath(t *testing.T) {
+	p, err := ParseDerivationPath("m/44'/888h/0'/0/7")
+	require.NoError(t, err)
+	require.Equal(t, []uint32{44 + HardenedKeyStart, 888 + HardenedKeyStart, HardenedKeyStart, 0, 7}, p)
+	require.Equal(t, "m/44'/888'/0'/0/7", FormatDerivationPath(p))
+
+	for _, s := range []string{"", "44'/0", "m/", "m/x", "m/2147483648", "m/-1"} {
+		_, err := ParseDerivationPath(s)
+		require.Error(t, err, s)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/nspcc-dev/neo-go/pkg/internal/keytestcases"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNEP2Encrypt(t *testing.T) {
@@ -79,3 +80,22 @@ func TestValidateNEP2Format(t *testing.T) {
 	s[2] = 0xe0
 	assert.NoError(t, validateNEP2Format(s))
 }
+
+func TestNEP2ScryptParams(t *testing.T) {
+	priv, err := NewPrivateKey()
+	require.NoError(t, err)
+
+	params := ScryptParams{N: 1024, R: 8, P: 1}
+	enc, err := NEP2EncryptWithParams(priv, "pass", params)
+	require.NoError(t, err)
+
+	dec, err := NEP2DecryptWithParams(enc, "pass", params)
+	require.NoError(t, err)
+	require.Equal(t, priv.Bytes(), dec.Bytes())
+
+	_, err = NEP2Decrypt(enc, "pass")
+	require.Error(t, err)
+
+	_, err = NEP2EncryptWithParams(priv, "pass", ScryptParams{N: 1000, R: 8, P: 1})
+	require.Error(t, err)
+}
@@ -106,7 +106,8 @@ func (p *PrivateKey) GetScriptHash() util.Uint160 {
 	return pk.GetScriptHash()
 }
 
-// Sign signs arbitrary length data using the private key.
+// Sign signs arbitrary length data using the private key. Signatures are
+// deterministic (RFC 6979) and have low S value.
 func (p *PrivateKey) Sign(data []byte) []byte {
 	var (
 		privateKey = p.ecdsa()
@@ -116,6 +117,11 @@ func (p *PrivateKey) Sign(data []byte) []byte {
 	r, s := rfc6979.SignECDSA(privateKey, digest[:], sha256.New)
 
 	params := privateKey.Curve.Params()
+	// (r, N-s) is a valid signature too, so low-S form is always used to
+	// make signatures non-malleable.
+	if s.Cmp(new(big.Int).Rsh(params.N, 1)) > 0 {
+		s = new(big.Int).Sub(params.N, s)
+	}
 	curveOrderByteSize := params.P.BitLen() / 8
 	rBytes, sBytes := r.Bytes(), s.Bytes()
 	signature := make([]byte, curveOrderByteSize*2)
@@ -1,12 +1,16 @@
 package keys
 
 import (
+	"crypto/elliptic"
+	"crypto/sha256"
 	"encoding/hex"
+	"math/big"
 	"strings"
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/pkg/internal/keytestcases"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPrivateKey(t *testing.T) {
@@ -52,5 +56,26 @@ func TestSigning(t *testing.T) {
 
 	r := "EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716"
 	s := "F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8"
-	assert.Equal(t, strings.ToLower(r+s), hex.EncodeToString(data))
+	// S from RFC is high, so it's normalized to N-S.
+	lowS := "0834E36AD29A83BF2BC9385E491D6099C8FDF9D1ED67AA7EA5F51F93782857A9"
+	assert.Equal(t, strings.ToLower(r+lowS), hex.EncodeToString(data))
+
+	// Both forms are valid.
+	digest := sha256.Sum256([]byte("sample"))
+	highS, _ := hex.DecodeString(r + s)
+	assert.True(t, PrivateKey.PublicKey().Verify(data, digest[:]))
+	assert.True(t, PrivateKey.PublicKey().Verify(highS, digest[:]))
+	assert.False(t, PrivateKey.PublicKey().Verify(data[:63], digest[:]))
+}
+
+func TestSignLowS(t *testing.T) {
+	halfOrder := new(big.Int).Rsh(elliptic.P256().Params().N, 1)
+	for i := 0; i < 50; i++ {
+		priv, err := NewPrivateKey()
+		require.NoError(t, err)
+		data := []byte{byte(i)}
+		sig := priv.Sign(data)
+		require.Equal(t, sig, priv.Sign(data))
+		require.True(t, new(big.Int).SetBytes(sig[32:]).Cmp(halfOrder) <= 0)
+	}
 }
@@ -250,14 +250,14 @@ func (p *PublicKey) Address() string {
 }
 
 // Verify returns true if the signature is valid and corresponds
-// to the hash and public key.
+// to the hash and public key. Both low-S and high-S signatures are accepted.
 func (p *PublicKey) Verify(signature []byte, hash []byte) bool {
 
 	publicKey := &ecdsa.PublicKey{}
 	publicKey.Curve = elliptic.P256()
 	publicKey.X = p.X
 	publicKey.Y = p.Y
-	if p.X == nil || p.Y == nil {
+	if p.X == nil || p.Y == nil || len(signature) != 64 {
 		return false
 	}
 	rBytes := new(big.Int).SetBytes(signature[0:32])
@@ -0,0 +1,117 @@
+package keys
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/shamir"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/base58"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// keyShareVersion is the first byte of encoded key share.
+const keyShareVersion = 0x01
+
+// keyShareLen is the length of decoded key share: version, threshold, share
+// number, script hash and share data.
+const keyShareLen = 3 + util.Uint160Size + 32
+
+// KeyShare is a part of the private key split with Shamir's secret sharing.
+// It's encoded in base58 with checksum and contains the script hash of the
+// key (so that the key can be checked after restoration) and the number of
+// shares needed to restore it.
+type KeyShare struct {
+	Threshold  int
+	Index      int
+	ScriptHash util.Uint160
+	Data       []byte
+}
+
+// SplitPrivateKey splits the private key into n shares any k of which can be
+// used to restore it.
+func SplitPrivateKey(p *PrivateKey, n, k int) ([]*KeyShare, error) {
+	shares, err := shamir.Split(p.Bytes(), n, k)
+	if err != nil {
+		return nil, err
+	}
+	h := p.GetScriptHash()
+	res := make([]*KeyShare, len(shares))
+	for i := range shares {
+		res[i] = &KeyShare{
+			Threshold:  k,
+			Index:      int(shares[i].X),
+			ScriptHash: h,
+			Data:       shares[i].Y,
+		}
+	}
+	return res, nil
+}
+
+// CombineKeyShares restores the private key from shares checking it to match
+// the script hash they were made for.
+func CombineKeyShares(shares []*KeyShare) (*PrivateKey, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("no shares")
+	}
+	ss := make([]shamir.Share, len(shares))
+	for i, s := range shares {
+		if !s.ScriptHash.Equals(shares[0].ScriptHash) || s.Threshold != shares[0].Threshold {
+			return nil, errors.New("shares belong to different keys")
+		}
+		ss[i] = shamir.Share{X: byte(s.Index), Y: s.Data}
+	}
+	if len(shares) < shares[0].Threshold {
+		return nil, fmt.Errorf("%d shares are needed, got %d", shares[0].Threshold, len(shares))
+	}
+	b, err := shamir.Combine(ss)
+	if err != nil {
+		return nil, err
+	}
+	p, err := NewPrivateKeyFromBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	if !p.GetScriptHash().Equals(shares[0].ScriptHash) {
+		return nil, errors.New("restored key doesn't match the address, some shares are corrupted")
+	}
+	return p, nil
+}
+
+// NewKeyShareFromString decodes the key share.
+func NewKeyShareFromString(s string) (*KeyShare, error) {
+	b, err := base58.CheckDecode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != keyShareLen || b[0] != keyShareVersion {
+		return nil, errors.New("invalid key share format")
+	}
+	if b[1] < 2 || b[2] == 0 {
+		return nil, errors.New("invalid key share parameters")
+	}
+	h, err := util.Uint160DecodeBytesBE(b[3 : 3+util.Uint160Size])
+	if err != nil {
+		return nil, err
+	}
+	return &KeyShare{
+		Threshold:  int(b[1]),
+		Index:      int(b[2]),
+		ScriptHash: h,
+		Data:       b[3+util.Uint160Size:],
+	}, nil
+}
+
+// String returns the share encoded in base58 with checksum.
+func (s *KeyShare) String() string {
+	b := make([]byte, 0, keyShareLen)
+	b = append(b, keyShareVersion, byte(s.Threshold), byte(s.Index))
+	b = append(b, s.ScriptHash.BytesBE()...)
+	b = append(b, s.Data...)
+	return base58.CheckEncode(b)
+}
+
+// Address returns the address of the key the share belongs to.
+func (s *KeyShare) Address() string {
+	return address.Uint160ToString(s.ScriptHash)
+}
@@ -0,0 +1,60 @@
+package keys
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitPrivateKey(t *testing.T) {
+	priv, err := NewPrivateKey()
+	require.NoError(t, err)
+
+	shares, err := SplitPrivateKey(priv, 3, 2)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(shares))
+
+	decoded := make([]*KeyShare, len(shares))
+	for i := range shares {
+		require.Equal(t, priv.Address(), shares[i].Address())
+		decoded[i], err = NewKeyShareFromString(shares[i].String())
+		require.NoError(t, err)
+		require.Equal(t, shares[i], decoded[i])
+	}
+
+	res, err := CombineKeyShares([]*KeyShare{decoded[2], decoded[0]})
+	require.NoError(t, err)
+	require.Equal(t, priv.Bytes(), res.Bytes())
+
+	t.Run("not enough shares", func(t *testing.T) {
+		_, err := CombineKeyShares(decoded[:1])
+		require.Error(t, err)
+	})
+	t.Run("different keys", func(t *testing.T) {
+		other, err := NewPrivateKey()
+		require.NoError(t, err)
+		otherShares, err := SplitPrivateKey(other, 3, 2)
+		require.NoError(t, err)
+		_, err = CombineKeyShares([]*KeyShare{decoded[0], otherShares[1]})
+		require.Error(t, err)
+	})
+	t.Run("corrupted share", func(t *testing.T) {
+		bad := *decoded[1]
+		bad.Data = append([]byte{}, bad.Data...)
+		bad.Data[0]++
+		_, err := CombineKeyShares([]*KeyShare{decoded[0], &bad})
+		require.Error(t, err)
+	})
+	t.Run("bad encoding", func(t *testing.T) {
+		s := []byte(shares[0].String())
+		if s[len(s)-1] == '1' {
+			s[len(s)-1] = '2'
+		} else {
+			s[len(s)-1] = '1'
+		}
+		_, err := NewKeyShareFromString(string(s))
+		require.Error(t, err)
+		_, err = NewKeyShareFromString(priv.WIF())
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,131 @@
+/*
+Package shamir implements Shamir's secret sharing over GF(2^8).
+
+Every byte of the secret is shared independently using random polynomial of
+degree k-1 with the free term equal to this byte, share number x (1..255)
+holds values of all polynomials at x. Any k shares are enough to restore
+the secret via Lagrange interpolation, less than k reveal nothing about it.
+*/
+package shamir
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// MaxShares is the maximum number of shares a secret can be split into.
+const MaxShares = 255
+
+// Share is a part of the split secret.
+type Share struct {
+	// X is the share number (the point polynomials are evaluated at),
+	// it's never 0.
+	X byte
+	// Y contains values of polynomials at X, it has the same length as the
+	// secret.
+	Y []byte
+}
+
+// Split splits the secret into n shares any k of which can be used to restore
+// it.
+func Split(secret []byte, n, k int) ([]Share, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("empty secret")
+	}
+	if k < 2 || k > n || n > MaxShares {
+		return nil, fmt.Errorf("invalid threshold/shares: %d/%d", k, n)
+	}
+
+	shares := make([]Share, n)
+	for i := range shares {
+		shares[i] = Share{X: byte(i + 1), Y: make([]byte, len(secret))}
+	}
+	coeffs := make([]byte, k)
+	for j := range secret {
+		if _, err := rand.Read(coeffs[1:]); err != nil {
+			return nil, err
+		}
+		coeffs[0] = secret[j]
+		for i := range shares {
+			shares[i].Y[j] = evaluate(coeffs, shares[i].X)
+		}
+	}
+	for i := range coeffs {
+		coeffs[i] = 0
+	}
+	return shares, nil
+}
+
+// Combine restores the secret from shares. It has no way to check the
+// result, so if less than threshold shares are given it's just some random
+// data.
+func Combine(shares []Share) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("no shares")
+	}
+	size := len(shares[0].Y)
+	for i := range shares {
+		if shares[i].X == 0 {
+			return nil, errors.New("invalid share number 0")
+		}
+		if len(shares[i].Y) != size {
+			return nil, errors.New("shares have different lengths")
+		}
+		for j := 0; j < i; j++ {
+			if shares[i].X == shares[j].X {
+				return nil, fmt.Errorf("duplicate share %d", shares[i].X)
+			}
+		}
+	}
+
+	// Lagrange basis polynomials at 0, subtraction is XOR in GF(2^8).
+	basis := make([]byte, len(shares))
+	for i := range shares {
+		basis[i] = 1
+		for j := range shares {
+			if i != j {
+				basis[i] = mul(basis[i], mul(shares[j].X, inv(shares[i].X^shares[j].X)))
+			}
+		}
+	}
+	secret := make([]byte, size)
+	for j := range secret {
+		for i := range shares {
+			secret[j] ^= mul(shares[i].Y[j], basis[i])
+		}
+	}
+	return secret, nil
+}
+
+// evaluate returns the value of polynomial with the given coefficients
+// (starting from the free term) at x.
+func evaluate(coeffs []byte, x byte) byte {
+	var res byte
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		res = mul(res, x) ^ coeffs[i]
+	}
+	return res
+}
+
+// mul multiplies a and b modulo x^8+x^4+x^3+x+1 (AES polynomial) without
+// data-dependent branches.
+func mul(a, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		p ^= -(b & 1) & a
+		a = (a << 1) ^ (-(a >> 7) & 0x1b)
+		b >>= 1
+	}
+	return p
+}
+
+// inv returns multiplicative inverse of a which is a^254 in GF(2^8).
+func inv(a byte) byte {
+	var r byte = 1
+	for i := 0; i < 7; i++ {
+		a = mul(a, a)
+		r = mul(r, a)
+	}
+	return r
+}
@@ -0,0 +1,57 @@
+package shamir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMulInv(t *testing.T) {
+	// Known AES field products.
+	require.Equal(t, byte(0xc1), mul(0x57, 0x83))
+	require.Equal(t, byte(0xfe), mul(0x57, 0x13))
+	for a := 1; a < 256; a++ {
+		require.Equal(t, byte(1), mul(byte(a), inv(byte(a))), a)
+	}
+}
+
+func TestSplitCombine(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	shares, err := Split(secret, 5, 3)
+	require.NoError(t, err)
+	require.Equal(t, 5, len(shares))
+
+	for _, idx := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		var ss []Share
+		for _, i := range idx {
+			ss = append(ss, shares[i])
+		}
+		res, err := Combine(ss)
+		require.NoError(t, err)
+		require.Equal(t, secret, res, idx)
+	}
+
+	res, err := Combine(shares[:2])
+	require.NoError(t, err)
+	require.NotEqual(t, secret, res)
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := Combine(nil)
+		require.Error(t, err)
+		_, err = Combine([]Share{shares[0], shares[0]})
+		require.Error(t, err)
+		_, err = Combine([]Share{shares[0], {X: 7, Y: []byte{1}}})
+		require.Error(t, err)
+		_, err = Combine([]Share{{X: 0, Y: shares[0].Y}})
+		require.Error(t, err)
+	})
+}
+
+func TestSplitInvalid(t *testing.T) {
+	for _, tc := range []struct{ n, k int }{{3, 1}, {3, 4}, {256, 2}} {
+		_, err := Split([]byte{1}, tc.n, tc.k)
+		require.Error(t, err, tc)
+	}
+	_, err := Split(nil, 3, 2)
+	require.Error(t, err)
+}
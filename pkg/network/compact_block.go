@@ -0,0 +1,152 @@
+package network
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"go.uber.org/zap"
+)
+
+// maxPendingCompactBlocks is the maximum number of compact blocks waiting
+// for missing transactions.
+const maxPendingCompactBlocks = 16
+
+// pendingCompactBlock is a compact block waiting for missing transactions.
+type pendingCompactBlock struct {
+	cb      *payload.CompactBlock
+	txs     []*transaction.Transaction
+	missing []uint16
+	peer    Peer
+}
+
+// supportsCompactBlocks checks whether the peer can receive compact blocks.
+func supportsCompactBlocks(p Peer) bool {
+	v := p.Version()
+	return v != nil && v.Services&payload.CompactBlocksService != 0
+}
+
+// newCompactBlockMsg creates a compact block message for the given block.
+func (s *Server) newCompactBlockMsg(b *block.Block) *Message {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return s.MkMsg(CMDCmpctBlock, payload.NewCompactBlock(b, binary.LittleEndian.Uint64(buf)))
+}
+
+// handleCompactBlockCmd processes the received compact block. It's restored
+// using memory pool transactions, missing ones are requested from the peer.
+func (s *Server) handleCompactBlockCmd(p Peer, cb *payload.CompactBlock) error {
+	if cb.Index <= s.chain.BlockHeight() {
+		return nil
+	}
+	h := cb.Hash()
+
+	s.cbLock.Lock()
+	if _, ok := s.compactBlocks[h]; ok {
+		s.cbLock.Unlock()
+		return nil
+	}
+	s.cbLock.Unlock()
+
+	verified := s.chain.GetMemPool().GetVerifiedTransactions()
+	pool := make([]*transaction.Transaction, len(verified))
+	for i := range verified {
+		pool[i] = verified[i].Tx
+	}
+	txs, missing := cb.Restore(pool)
+	if len(missing) == 0 {
+		return s.completeCompactBlock(p, cb, txs)
+	}
+
+	s.cbLock.Lock()
+	height := s.chain.BlockHeight()
+	for k, pb := range s.compactBlocks {
+		if pb.cb.Index <= height {
+			delete(s.compactBlocks, k)
+		}
+	}
+	if len(s.compactBlocks) >= maxPendingCompactBlocks {
+		s.cbLock.Unlock()
+		return s.requestFullBlock(p, h)
+	}
+	s.compactBlocks[h] = &pendingCompactBlock{
+		cb:      cb,
+		txs:     txs,
+		missing: missing,
+		peer:    p,
+	}
+	s.cbLock.Unlock()
+
+	return p.EnqueueP2PMessage(s.MkMsg(CMDGetBlockTxn, &payload.GetBlockTxn{
+		BlockHash: h,
+		Indexes:   missing,
+	}))
+}
+
+// handleBlockTxnCmd fills pending compact block with transactions received.
+// Transactions for blocks that are not pending anymore (or were requested
+// from some other peer) are ignored.
+func (s *Server) handleBlockTxnCmd(p Peer, bt *payload.BlockTxn) error {
+	s.cbLock.Lock()
+	pb, ok := s.compactBlocks[bt.BlockHash]
+	if !ok || pb.peer != p {
+		s.cbLock.Unlock()
+		return nil
+	}
+	delete(s.compactBlocks, bt.BlockHash)
+	s.cbLock.Unlock()
+
+	if len(bt.Transactions) != len(pb.missing) {
+		return fmt.Errorf("%d transactions received for %d requested", len(bt.Transactions), len(pb.missing))
+	}
+	for i, idx := range pb.missing {
+		pb.txs[idx] = bt.Transactions[i]
+	}
+	return s.completeCompactBlock(p, pb.cb, pb.txs)
+}
+
+// handleGetBlockTxnCmd sends transactions requested for the compact block.
+func (s *Server) handleGetBlockTxnCmd(p Peer, req *payload.GetBlockTxn) error {
+	b, err := s.chain.GetBlock(req.BlockHash)
+	if err != nil {
+		return nil
+	}
+	resp := &payload.BlockTxn{
+		BlockHash:    req.BlockHash,
+		Transactions: make([]*transaction.Transaction, len(req.Indexes)),
+	}
+	for i, idx := range req.Indexes {
+		if int(idx) >= len(b.Transactions) {
+			return fmt.Errorf("invalid transaction index %d for block %s", idx, req.BlockHash.StringLE())
+		}
+		resp.Transactions[i] = b.Transactions[idx]
+	}
+	return p.EnqueueP2PMessage(s.MkMsg(CMDBlockTxn, resp))
+}
+
+// completeCompactBlock makes a block from the compact one and the list of
+// its transactions and puts it into the block queue. If transactions don't
+// match the block (because of short ID collisions) the full block is
+// requested.
+func (s *Server) completeCompactBlock(p Peer, cb *payload.CompactBlock, txs []*transaction.Transaction) error {
+	b := &block.Block{
+		Base:         cb.Base,
+		Transactions: txs,
+	}
+	if err := b.Verify(); err != nil {
+		s.log.Debug("failed to restore compact block",
+			zap.Uint32("index", cb.Index),
+			zap.Error(err))
+		return s.requestFullBlock(p, cb.Hash())
+	}
+	return s.handleBlockCmd(p, b)
+}
+
+// requestFullBlock requests the block with the given hash from the peer.
+func (s *Server) requestFullBlock(p Peer, h util.Uint256) error {
+	return p.EnqueueP2PMessage(s.MkMsg(CMDGetData, payload.NewInventory(payload.BlockType, []util.Uint256{h})))
+}
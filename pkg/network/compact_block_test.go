@@ -0,0 +1,165 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/mempool"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type feerStub struct{}
+
+func (fs feerStub) NetworkFee(*transaction.Transaction) util.Fixed8 { return 0 }
+func (fs feerStub) IsLowPriority(util.Fixed8) bool                  { return false }
+func (fs feerStub) FeePerByte(*transaction.Transaction) util.Fixed8 { return 0 }
+func (fs feerStub) SystemFee(*transaction.Transaction) util.Fixed8  { return 0 }
+
+func newCompactTestBlock(t *testing.T, n int) *block.Block {
+	b := &block.Block{
+		Base: block.Base{
+			PrevHash:  hash.Sha256([]byte("a")),
+			Timestamp: 100500,
+			Index:     1,
+			Script: transaction.Witness{
+				InvocationScript:   []byte{0x0},
+				VerificationScript: []byte{0x1},
+			},
+		},
+		Transactions: []*transaction.Transaction{transaction.NewMinerTXWithNonce(42)},
+	}
+	for i := 0; i < n; i++ {
+		b.Transactions = append(b.Transactions, transaction.NewInvocationTX([]byte{byte(i)}, 0))
+	}
+	require.NoError(t, b.RebuildMerkleRoot())
+	return b
+}
+
+// newCompactTestServer creates a server with the given transactions in the
+// memory pool and a working block queue.
+func newCompactTestServer(t *testing.T, txs ...*transaction.Transaction) (*Server, *testChain) {
+	pool := mempool.NewMemPool(10)
+	for _, tx := range txs {
+		require.NoError(t, pool.Add(tx, feerStub{}))
+	}
+	chain := &testChain{pool: &pool}
+	s := newTestServer(t)
+	s.chain = chain
+	s.bQueue = newBlockQueue(0, chain, zaptest.NewLogger(t), nil)
+	return s, chain
+}
+
+func TestHandleCompactBlock(t *testing.T) {
+	b := newCompactTestBlock(t, 3)
+
+	t.Run("from mempool", func(t *testing.T) {
+		s, _ := newCompactTestServer(t, b.Transactions[1:]...)
+		p := newLocalPeer(t, s)
+		p.messageHandler = func(t *testing.T, msg *Message) {
+			require.Fail(t, "unexpected message", string(msg.CommandType()))
+		}
+		require.NoError(t, s.handleCompactBlockCmd(p, payload.NewCompactBlock(b, 1)))
+		require.Equal(t, 1, s.bQueue.length())
+		require.Equal(t, 0, len(s.compactBlocks))
+	})
+
+	t.Run("missing transactions", func(t *testing.T) {
+		s, _ := newCompactTestServer(t, b.Transactions[1])
+		p := newLocalPeer(t, s)
+		var req *payload.GetBlockTxn
+		p.messageHandler = func(t *testing.T, msg *Message) {
+			require.Equal(t, CMDGetBlockTxn, msg.CommandType())
+			req = msg.Payload.(*payload.GetBlockTxn)
+		}
+		require.NoError(t, s.handleCompactBlockCmd(p, payload.NewCompactBlock(b, 1)))
+		require.NotNil(t, req)
+		require.Equal(t, b.Hash(), req.BlockHash)
+		require.Equal(t, []uint16{2, 3}, req.Indexes)
+		require.Equal(t, 0, s.bQueue.length())
+		require.Equal(t, 1, len(s.compactBlocks))
+
+		// Transactions from some other peer are ignored.
+		other := newLocalPeer(t, s)
+		require.NoError(t, s.handleBlockTxnCmd(other, &payload.BlockTxn{
+			BlockHash:    b.Hash(),
+			Transactions: b.Transactions[2:],
+		}))
+		require.Equal(t, 0, s.bQueue.length())
+
+		// Sending peer can serve them.
+		srv, _ := newCompactTestServer(t)
+		srv.chain.(*testChain).blocks = map[util.Uint256]*block.Block{b.Hash(): b}
+		var resp *payload.BlockTxn
+		sp := newLocalPeer(t, srv)
+		sp.messageHandler = func(t *testing.T, msg *Message) {
+			require.Equal(t, CMDBlockTxn, msg.CommandType())
+			resp = msg.Payload.(*payload.BlockTxn)
+		}
+		require.NoError(t, srv.handleGetBlockTxnCmd(sp, req))
+		require.NotNil(t, resp)
+		require.Equal(t, 2, len(resp.Transactions))
+		require.Equal(t, b.Transactions[2].Hash(), resp.Transactions[0].Hash())
+		require.Equal(t, b.Transactions[3].Hash(), resp.Transactions[1].Hash())
+
+		require.NoError(t, s.handleBlockTxnCmd(p, resp))
+		require.Equal(t, 1, s.bQueue.length())
+		require.Equal(t, 0, len(s.compactBlocks))
+	})
+
+	t.Run("invalid index requested", func(t *testing.T) {
+		s, _ := newCompactTestServer(t)
+		s.chain.(*testChain).blocks = map[util.Uint256]*block.Block{b.Hash(): b}
+		p := newLocalPeer(t, s)
+		require.Error(t, s.handleGetBlockTxnCmd(p, &payload.GetBlockTxn{
+			BlockHash: b.Hash(),
+			Indexes:   []uint16{4},
+		}))
+	})
+
+	t.Run("wrong number of transactions", func(t *testing.T) {
+		s, _ := newCompactTestServer(t)
+		p := newLocalPeer(t, s)
+		p.messageHandler = func(t *testing.T, msg *Message) {}
+		require.NoError(t, s.handleCompactBlockCmd(p, payload.NewCompactBlock(b, 1)))
+		require.Error(t, s.handleBlockTxnCmd(p, &payload.BlockTxn{
+			BlockHash:    b.Hash(),
+			Transactions: b.Transactions[1:2],
+		}))
+		require.Equal(t, 0, s.bQueue.length())
+	})
+
+	t.Run("short ID collision", func(t *testing.T) {
+		// Some other memory pool transaction matches the short ID of
+		// the block's one, so the restored block is invalid and the
+		// full one has to be requested.
+		wrong := transaction.NewInvocationTX([]byte{0xff}, 0)
+		s, _ := newCompactTestServer(t, wrong, b.Transactions[2], b.Transactions[3])
+		p := newLocalPeer(t, s)
+		var inv *payload.Inventory
+		p.messageHandler = func(t *testing.T, msg *Message) {
+			require.Equal(t, CMDGetData, msg.CommandType())
+			inv = msg.Payload.(*payload.Inventory)
+		}
+		cb := payload.NewCompactBlock(b, 1)
+		cb.ShortIDs[0] = cb.ShortID(wrong.Hash())
+		require.NoError(t, s.handleCompactBlockCmd(p, cb))
+		require.NotNil(t, inv)
+		require.Equal(t, payload.BlockType, inv.Type)
+		require.Equal(t, []util.Uint256{b.Hash()}, inv.Hashes)
+		require.Equal(t, 0, s.bQueue.length())
+		require.Equal(t, 0, len(s.compactBlocks))
+	})
+
+	t.Run("old block", func(t *testing.T) {
+		s, chain := newCompactTestServer(t, b.Transactions[1:]...)
+		chain.blockheight = 1
+		p := newLocalPeer(t, s)
+		require.NoError(t, s.handleCompactBlockCmd(p, payload.NewCompactBlock(b, 1)))
+		require.Equal(t, 0, s.bQueue.length())
+	})
+}
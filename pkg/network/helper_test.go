@@ -9,8 +9,10 @@ import (
 
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/blockchainer"
 	"github.com/nspcc-dev/neo-go/pkg/core/mempool"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/io"
@@ -22,6 +24,8 @@ import (
 
 type testChain struct {
 	blockheight uint32
+	pool        *mempool.Pool
+	blocks      map[util.Uint256]*block.Block
 }
 
 func (chain testChain) ApplyPolicyToTxSet([]mempool.TxWithFee) []mempool.TxWithFee {
@@ -72,7 +76,10 @@ func (chain testChain) GetAppExecResult(hash util.Uint256) (*state.AppExecResult
 	panic("TODO")
 }
 func (chain testChain) GetBlock(hash util.Uint256) (*block.Block, error) {
-	panic("TODO")
+	if b, ok := chain.blocks[hash]; ok {
+		return b, nil
+	}
+	return nil, storage.ErrKeyNotFound
 }
 func (chain testChain) GetContractState(hash util.Uint160) *state.Contract {
 	panic("TODO")
@@ -117,6 +124,9 @@ func (chain testChain) GetTestVM() *vm.VM {
 func (chain testChain) GetStorageItems(hash util.Uint160) (map[string]*state.StorageItem, error) {
 	panic("TODO")
 }
+func (chain testChain) GetStateView() (blockchainer.StateView, error) {
+	panic("TODO")
+}
 func (chain testChain) CurrentHeaderHash() util.Uint256 {
 	return util.Uint256{}
 }
@@ -138,6 +148,13 @@ func (chain testChain) GetUnspentCoinState(util.Uint256) *state.UnspentCoin {
 }
 
 func (chain testChain) GetMemPool() *mempool.Pool {
+	if chain.pool == nil {
+		panic("TODO")
+	}
+	return chain.pool
+}
+
+func (chain testChain) GetStore() storage.Store {
 	panic("TODO")
 }
 
@@ -182,6 +199,7 @@ type localPeer struct {
 	netaddr        net.TCPAddr
 	server         *Server
 	version        *payload.Version
+	authKey        *keys.PublicKey
 	lastBlockIndex uint32
 	handshaked     bool
 	t              *testing.T
@@ -236,6 +254,12 @@ func (p *localPeer) EnqueueHPPacket(m []byte) error {
 func (p *localPeer) Version() *payload.Version {
 	return p.version
 }
+func (p *localPeer) AuthKey() *keys.PublicKey {
+	return p.authKey
+}
+func (p *localPeer) Stats() PeerStats {
+	return PeerStats{}
+}
 func (p *localPeer) LastBlockIndex() uint32 {
 	return p.lastBlockIndex
 }
@@ -283,6 +307,8 @@ func newTestServer(t *testing.T) *Server {
 		unregister:   make(chan peerDrop),
 		peers:        make(map[Peer]bool),
 		log:          zaptest.NewLogger(t),
+
+		compactBlocks: make(map[util.Uint256]*pendingCompactBlock),
 	}
 
 }
@@ -15,6 +15,8 @@ const (
 	// The minimum size of a valid message.
 	minMessageSize = 24
 	cmdSize        = 12
+	// headerSize is the size of magic, command and length fields.
+	headerSize = 4 + cmdSize + 4
 )
 
 // Message is the complete message send between nodes.
@@ -40,12 +42,15 @@ type CommandType string
 const (
 	CMDAddr        CommandType = "addr"
 	CMDBlock       CommandType = "block"
+	CMDBlockTxn    CommandType = "blocktxn"
+	CMDCmpctBlock  CommandType = "cmpctblock"
 	CMDConsensus   CommandType = "consensus"
 	CMDFilterAdd   CommandType = "filteradd"
 	CMDFilterClear CommandType = "filterclear"
 	CMDFilterLoad  CommandType = "filterload"
 	CMDGetAddr     CommandType = "getaddr"
 	CMDGetBlocks   CommandType = "getblocks"
+	CMDGetBlockTxn CommandType = "getblocktxn"
 	CMDGetData     CommandType = "getdata"
 	CMDGetHeaders  CommandType = "getheaders"
 	CMDHeaders     CommandType = "headers"
@@ -86,12 +91,30 @@ func NewMessage(magic config.NetMode, cmd CommandType, p payload.Payload) *Messa
 
 // CommandType converts the 12 byte command slice to a CommandType.
 func (m *Message) CommandType() CommandType {
-	cmd := cmdByteArrayToString(m.Command)
+	return commandType(m.Command)
+}
+
+// packetCommandType returns command type of the serialized message.
+func packetCommandType(pkt []byte) CommandType {
+	var cmd [cmdSize]byte
+	if len(pkt) >= headerSize {
+		copy(cmd[:], pkt[4:4+cmdSize])
+	}
+	return commandType(cmd)
+}
+
+// commandType converts the 12 byte command slice to a CommandType.
+func commandType(command [cmdSize]byte) CommandType {
+	cmd := cmdByteArrayToString(command)
 	switch cmd {
 	case "addr":
 		return CMDAddr
 	case "block":
 		return CMDBlock
+	case "blocktxn":
+		return CMDBlockTxn
+	case "cmpctblock":
+		return CMDCmpctBlock
 	case "consensus":
 		return CMDConsensus
 	case "filteradd":
@@ -104,6 +127,8 @@ func (m *Message) CommandType() CommandType {
 		return CMDGetAddr
 	case "getblocks":
 		return CMDGetBlocks
+	case "getblocktxn":
+		return CMDGetBlockTxn
 	case "getdata":
 		return CMDGetData
 	case "getheaders":
@@ -164,6 +189,12 @@ func (m *Message) decodePayload(br *io.BinReader) error {
 		p = &payload.AddressList{}
 	case CMDBlock:
 		p = &block.Block{}
+	case CMDCmpctBlock:
+		p = &payload.CompactBlock{}
+	case CMDGetBlockTxn:
+		p = &payload.GetBlockTxn{}
+	case CMDBlockTxn:
+		p = &payload.BlockTxn{}
 	case CMDConsensus:
 		p = &consensus.Payload{}
 	case CMDGetBlocks:
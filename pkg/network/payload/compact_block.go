@@ -0,0 +1,236 @@
+package payload
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+const (
+	// MaxCompactBlockTransactions is the maximum number of transactions in
+	// a compact block.
+	MaxCompactBlockTransactions = 0xffff
+	// shortIDSize is the size of transaction short ID in bytes.
+	shortIDSize = 6
+)
+
+// CompactBlock is a block announcement where transactions are replaced by
+// their short IDs (except for prefilled ones), so that the receiver can
+// reconstruct the block from its memory pool.
+type CompactBlock struct {
+	block.Base
+	// Nonce is a salt used for short IDs calculation.
+	Nonce uint64
+	// ShortIDs are short IDs of all transactions that are not prefilled in
+	// the order of their appearance in the block.
+	ShortIDs []uint64
+	// Prefilled are the transactions sent in full, sorted by index.
+	Prefilled []PrefilledTX
+}
+
+// PrefilledTX is a transaction sent in full along with the compact block.
+type PrefilledTX struct {
+	// Index is the index of the transaction in the block.
+	Index uint16
+	Tx    *transaction.Transaction
+}
+
+// GetBlockTxn payload is used to request transactions missing from the
+// compact block.
+type GetBlockTxn struct {
+	BlockHash util.Uint256
+	// Indexes are the indexes of missing transactions in the block.
+	Indexes []uint16
+}
+
+// BlockTxn payload contains transactions requested with GetBlockTxn.
+type BlockTxn struct {
+	BlockHash    util.Uint256
+	Transactions []*transaction.Transaction
+}
+
+// NewCompactBlock creates a compact block from the given block using the
+// nonce specified. Miner transactions are always prefilled as they can't be
+// in the receiver's memory pool.
+func NewCompactBlock(b *block.Block, nonce uint64) *CompactBlock {
+	c := &CompactBlock{
+		Base:  b.Base,
+		Nonce: nonce,
+	}
+	for i, tx := range b.Transactions {
+		if tx.Type == transaction.MinerType {
+			c.Prefilled = append(c.Prefilled, PrefilledTX{Index: uint16(i), Tx: tx})
+		} else {
+			c.ShortIDs = append(c.ShortIDs, c.ShortID(tx.Hash()))
+		}
+	}
+	return c
+}
+
+// ShortID returns the short ID of the transaction with the given hash.
+func (c *CompactBlock) ShortID(h util.Uint256) uint64 {
+	var buf [util.Uint256Size*2 + 8]byte
+	bh := c.Hash()
+	copy(buf[:], bh[:])
+	binary.LittleEndian.PutUint64(buf[util.Uint256Size:], c.Nonce)
+	copy(buf[util.Uint256Size+8:], h[:])
+	sum := sha256.Sum256(buf[:])
+	var id [8]byte
+	copy(id[:], sum[:shortIDSize])
+	return binary.LittleEndian.Uint64(id[:])
+}
+
+// TxCount returns the number of transactions in the block.
+func (c *CompactBlock) TxCount() int {
+	return len(c.ShortIDs) + len(c.Prefilled)
+}
+
+// Restore reconstructs the list of block transactions using prefilled
+// transactions and the candidates given (usually, memory pool contents).
+// Transactions that can't be found (or that have ambiguous short IDs) are
+// nil in the result and their indexes are returned as a second value.
+func (c *CompactBlock) Restore(candidates []*transaction.Transaction) ([]*transaction.Transaction, []uint16) {
+	txs := make([]*transaction.Transaction, c.TxCount())
+	for _, p := range c.Prefilled {
+		txs[p.Index] = p.Tx
+	}
+
+	// Short ID to index in the block, -1 marks ambiguous IDs.
+	slots := make(map[uint64]int, len(c.ShortIDs))
+	var j int
+	for i := range txs {
+		if txs[i] != nil {
+			continue
+		}
+		id := c.ShortIDs[j]
+		if _, ok := slots[id]; ok {
+			slots[id] = -1
+		} else {
+			slots[id] = i
+		}
+		j++
+	}
+	collisions := make(map[int]bool)
+	for _, tx := range candidates {
+		i, ok := slots[c.ShortID(tx.Hash())]
+		if !ok || i < 0 {
+			continue
+		}
+		if txs[i] != nil {
+			collisions[i] = true
+			continue
+		}
+		txs[i] = tx
+	}
+
+	var missing []uint16
+	for i := range txs {
+		if collisions[i] {
+			txs[i] = nil
+		}
+		if txs[i] == nil {
+			missing = append(missing, uint16(i))
+		}
+	}
+	return txs, missing
+}
+
+// DecodeBinary implements Serializable interface.
+func (c *CompactBlock) DecodeBinary(br *io.BinReader) {
+	c.Base.DecodeBinary(br)
+	c.Nonce = br.ReadU64LE()
+
+	n := br.ReadVarUint()
+	if n > MaxCompactBlockTransactions {
+		br.Err = errors.New("too many short IDs")
+		return
+	}
+	c.ShortIDs = make([]uint64, n)
+	for i := range c.ShortIDs {
+		var id [8]byte
+		br.ReadBytes(id[:shortIDSize])
+		c.ShortIDs[i] = binary.LittleEndian.Uint64(id[:])
+	}
+
+	br.ReadArray(&c.Prefilled, MaxCompactBlockTransactions)
+	if br.Err != nil {
+		return
+	}
+	if c.TxCount() > MaxCompactBlockTransactions {
+		br.Err = errors.New("too many transactions")
+		return
+	}
+	for i, p := range c.Prefilled {
+		if int(p.Index) >= c.TxCount() || (i > 0 && p.Index <= c.Prefilled[i-1].Index) {
+			br.Err = errors.New("invalid prefilled transaction index")
+			return
+		}
+	}
+}
+
+// EncodeBinary implements Serializable interface.
+func (c *CompactBlock) EncodeBinary(bw *io.BinWriter) {
+	c.Base.EncodeBinary(bw)
+	bw.WriteU64LE(c.Nonce)
+
+	bw.WriteVarUint(uint64(len(c.ShortIDs)))
+	for _, id := range c.ShortIDs {
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], id)
+		bw.WriteBytes(b[:shortIDSize])
+	}
+	bw.WriteArray(c.Prefilled)
+}
+
+// DecodeBinary implements Serializable interface.
+func (p *PrefilledTX) DecodeBinary(br *io.BinReader) {
+	p.Index = br.ReadU16LE()
+	p.Tx = new(transaction.Transaction)
+	p.Tx.DecodeBinary(br)
+}
+
+// EncodeBinary implements Serializable interface.
+func (p *PrefilledTX) EncodeBinary(bw *io.BinWriter) {
+	bw.WriteU16LE(p.Index)
+	p.Tx.EncodeBinary(bw)
+}
+
+// DecodeBinary implements Serializable interface.
+func (p *GetBlockTxn) DecodeBinary(br *io.BinReader) {
+	p.BlockHash.DecodeBinary(br)
+	n := br.ReadVarUint()
+	if n > MaxCompactBlockTransactions {
+		br.Err = errors.New("too many indexes")
+		return
+	}
+	p.Indexes = make([]uint16, n)
+	for i := range p.Indexes {
+		p.Indexes[i] = br.ReadU16LE()
+	}
+}
+
+// EncodeBinary implements Serializable interface.
+func (p *GetBlockTxn) EncodeBinary(bw *io.BinWriter) {
+	p.BlockHash.EncodeBinary(bw)
+	bw.WriteVarUint(uint64(len(p.Indexes)))
+	for _, i := range p.Indexes {
+		bw.WriteU16LE(i)
+	}
+}
+
+// DecodeBinary implements Serializable interface.
+func (p *BlockTxn) DecodeBinary(br *io.BinReader) {
+	p.BlockHash.DecodeBinary(br)
+	br.ReadArray(&p.Transactions, MaxCompactBlockTransactions)
+}
+
+// EncodeBinary implements Serializable interface.
+func (p *BlockTxn) EncodeBinary(bw *io.BinWriter) {
+	p.BlockHash.EncodeBinary(bw)
+	bw.WriteArray(p.Transactions)
+}
@@ -0,0 +1,93 @@
+package payload
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/internal/testserdes"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCompactBlock(t *testing.T, n int) (*block.Block, *CompactBlock) {
+	b := &block.Block{
+		Base: block.Base{
+			PrevHash:  hash.Sha256([]byte("a")),
+			Timestamp: 100500,
+			Index:     1,
+			Script: transaction.Witness{
+				InvocationScript:   []byte{0x0},
+				VerificationScript: []byte{0x1},
+			},
+		},
+		Transactions: []*transaction.Transaction{transaction.NewMinerTXWithNonce(42)},
+	}
+	for i := 0; i < n; i++ {
+		b.Transactions = append(b.Transactions, transaction.NewInvocationTX([]byte{byte(i)}, 0))
+	}
+	require.NoError(t, b.RebuildMerkleRoot())
+	for _, tx := range b.Transactions {
+		tx.Hash()
+	}
+	b.Hash()
+	return b, NewCompactBlock(b, 123)
+}
+
+func TestCompactBlockEncodeDecode(t *testing.T) {
+	_, c := newTestCompactBlock(t, 3)
+	require.Equal(t, 3, len(c.ShortIDs))
+	require.Equal(t, 1, len(c.Prefilled))
+	require.Equal(t, 4, c.TxCount())
+	testserdes.EncodeDecodeBinary(t, c, new(CompactBlock))
+
+	t.Run("bad prefilled index", func(t *testing.T) {
+		bad := *c
+		bad.Prefilled = []PrefilledTX{{Index: 4, Tx: c.Prefilled[0].Tx}}
+		data, err := testserdes.EncodeBinary(&bad)
+		require.NoError(t, err)
+		require.Error(t, testserdes.DecodeBinary(data, new(CompactBlock)))
+	})
+}
+
+func TestCompactBlockRestore(t *testing.T) {
+	b, c := newTestCompactBlock(t, 4)
+
+	t.Run("full", func(t *testing.T) {
+		txs, missing := c.Restore(b.Transactions[1:])
+		require.Equal(t, 0, len(missing))
+		require.Equal(t, b.Transactions, txs)
+	})
+	t.Run("missing", func(t *testing.T) {
+		pool := []*transaction.Transaction{b.Transactions[1], b.Transactions[3], transaction.NewInvocationTX([]byte{0xff}, 0)}
+		txs, missing := c.Restore(pool)
+		require.Equal(t, []uint16{2, 4}, missing)
+		require.Equal(t, b.Transactions[0], txs[0])
+		require.Equal(t, b.Transactions[1], txs[1])
+		require.Equal(t, b.Transactions[3], txs[3])
+	})
+	t.Run("collision", func(t *testing.T) {
+		cc := *c
+		cc.ShortIDs = append([]uint64{}, c.ShortIDs...)
+		cc.ShortIDs[1] = cc.ShortIDs[0]
+		_, missing := cc.Restore(b.Transactions[1:])
+		require.Equal(t, []uint16{1, 2}, missing)
+	})
+}
+
+func TestBlockTxnEncodeDecode(t *testing.T) {
+	b, _ := newTestCompactBlock(t, 2)
+	testserdes.EncodeDecodeBinary(t, &GetBlockTxn{
+		BlockHash: b.Hash(),
+		Indexes:   []uint16{1, 2},
+	}, new(GetBlockTxn))
+	testserdes.EncodeDecodeBinary(t, &BlockTxn{
+		BlockHash:    b.Hash(),
+		Transactions: b.Transactions[1:],
+	}, new(BlockTxn))
+	testserdes.EncodeDecodeBinary(t, &BlockTxn{
+		BlockHash:    util.Uint256{1, 2, 3},
+		Transactions: []*transaction.Transaction{},
+	}, new(BlockTxn))
+}
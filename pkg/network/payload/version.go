@@ -17,13 +17,15 @@ const (
 	// PrunedNode        uint64 = 3 // Not implemented
 	// LightNode         uint64 = 4 // Not implemented
 
+	// CompactBlocksService is set by nodes that can receive compact blocks.
+	CompactBlocksService uint64 = 1 << 4
 )
 
 // Version payload.
 type Version struct {
 	// currently the version of the protocol is 0
 	Version uint32
-	// currently 1
+	// offered services bit mask
 	Services uint64
 	// timestamp
 	Timestamp uint32
@@ -43,7 +45,7 @@ type Version struct {
 func NewVersion(id uint32, p uint16, ua string, h uint32, r bool) *Version {
 	return &Version{
 		Version:     0,
-		Services:    nodePeerService,
+		Services:    nodePeerService | CompactBlocksService,
 		Timestamp:   uint32(time.Now().UTC().Unix()),
 		Port:        p,
 		Nonce:       id,
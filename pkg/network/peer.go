@@ -2,10 +2,22 @@ package network
 
 import (
 	"net"
+	"time"
 
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 )
 
+// PeerStats contains peer connection statistics.
+type PeerStats struct {
+	// ConnectedAt is the time the connection was established at.
+	ConnectedAt time.Time
+	// BytesReceived is the total size of messages received from the peer.
+	BytesReceived uint64
+	// BytesSent is the total size of messages sent to the peer.
+	BytesSent uint64
+}
+
 // Peer represents a network node neo-go is connected to.
 type Peer interface {
 	// RemoteAddr returns the remote address that we're connected to now.
@@ -23,34 +35,37 @@ type Peer interface {
 	// EnqueuePacket if there is no error in serializing it.
 	EnqueueMessage(*Message) error
 
-	// EnqueuePacket is a blocking packet enqueuer, it doesn't return until
-	// it puts given packet into the queue. It accepts a slice of bytes that
-	// can be shared with other queues (so that message marshalling can be
-	// done once for all peers). Does nothing is the peer is not yet
-	// completed handshaking.
+	// EnqueuePacket is a non-blocking packet enqueuer intended to be used
+	// for broadcasts. It accepts a slice of bytes that can be shared with
+	// other queues (so that message marshalling can be done once for all
+	// peers). Packets are sent in the order of their priority classes
+	// (consensus, blocks, transactions, addresses) and low-priority ones
+	// can be dropped if the peer is too slow to receive them. Does nothing
+	// is the peer is not yet completed handshaking.
 	EnqueuePacket([]byte) error
 
 	// EnqueueP2PMessage is a temporary wrapper that sends a message via
 	// EnqueueP2PPacket if there is no error in serializing it.
 	EnqueueP2PMessage(*Message) error
 
-	// EnqueueP2PPacket is a blocking packet enqueuer, it doesn't return until
-	// it puts given packet into the queue. It accepts a slice of bytes that
-	// can be shared with other queues (so that message marshalling can be
-	// done once for all peers). Does nothing is the peer is not yet
-	// completed handshaking. This queue is intended to be used for unicast
-	// peer to peer communication that is more important than broadcasts
-	// (handled by EnqueuePacket), but less important than high-priority
-	// messages (handled by EnqueueHPPacket).
+	// EnqueueP2PPacket is the same as EnqueuePacket, but it's intended to
+	// be used for unicast peer to peer communication, so it blocks until
+	// there is enough space in the peer's send queue instead of dropping
+	// packets.
 	EnqueueP2PPacket([]byte) error
 
-	// EnqueueHPPacket is a blocking high priority packet enqueuer, it
-	// doesn't return until it puts given packet into the high-priority
-	// queue.
+	// EnqueueHPPacket is a non-blocking high priority packet enqueuer,
+	// packets enqueued with it are sent before any other ones and are
+	// never dropped.
 	EnqueueHPPacket([]byte) error
 	Version() *payload.Version
+	// AuthKey returns the node key the peer has authenticated with during
+	// secure session handshake, it's nil if there was no such handshake.
+	AuthKey() *keys.PublicKey
 	LastBlockIndex() uint32
 	Handshaked() bool
+	// Stats returns connection statistics.
+	Stats() PeerStats
 
 	// SendPing enqueues a ping message to be sent to the peer and does
 	// appropriate protocol handling like timeouts and outstanding pings
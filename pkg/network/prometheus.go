@@ -1,6 +1,8 @@
 package network
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -38,6 +40,106 @@ var (
 			Namespace: "neogo",
 		},
 	)
+
+	messagesReceived = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of P2P messages received",
+			Name:      "p2p_messages_received_total",
+			Namespace: "neogo",
+		},
+		[]string{"command"},
+	)
+
+	bytesReceived = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of P2P bytes received",
+			Name:      "p2p_bytes_received_total",
+			Namespace: "neogo",
+		},
+		[]string{"command"},
+	)
+
+	messagesSent = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of P2P messages sent",
+			Name:      "p2p_messages_sent_total",
+			Namespace: "neogo",
+		},
+		[]string{"command"},
+	)
+
+	bytesSent = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of P2P bytes sent",
+			Name:      "p2p_bytes_sent_total",
+			Namespace: "neogo",
+		},
+		[]string{"command"},
+	)
+
+	messageErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of P2P message handling errors",
+			Name:      "p2p_message_errors_total",
+			Namespace: "neogo",
+		},
+		[]string{"command"},
+	)
+
+	messagesDropped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of outgoing P2P messages dropped because of peer send queue overflow",
+			Name:      "p2p_messages_dropped_total",
+			Namespace: "neogo",
+		},
+		[]string{"command"},
+	)
+
+	peerMessagesReceived = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of P2P messages received from peer",
+			Name:      "p2p_peer_messages_received_total",
+			Namespace: "neogo",
+		},
+		[]string{"peer"},
+	)
+
+	peerBytesReceived = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of P2P bytes received from peer",
+			Name:      "p2p_peer_bytes_received_total",
+			Namespace: "neogo",
+		},
+		[]string{"peer"},
+	)
+
+	peerMessagesSent = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of P2P messages sent to peer",
+			Name:      "p2p_peer_messages_sent_total",
+			Namespace: "neogo",
+		},
+		[]string{"peer"},
+	)
+
+	peerBytesSent = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of P2P bytes sent to peer",
+			Name:      "p2p_peer_bytes_sent_total",
+			Namespace: "neogo",
+		},
+		[]string{"peer"},
+	)
+
+	peerLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Help:      "Peer ping/pong round trip time",
+			Name:      "p2p_peer_latency_seconds",
+			Namespace: "neogo",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+		},
+		[]string{"peer"},
+	)
 )
 
 func init() {
@@ -46,6 +148,17 @@ func init() {
 		servAndNodeVersion,
 		poolCount,
 		blockQueueLength,
+		messagesReceived,
+		bytesReceived,
+		messagesSent,
+		bytesSent,
+		messageErrors,
+		messagesDropped,
+		peerMessagesReceived,
+		peerBytesReceived,
+		peerMessagesSent,
+		peerBytesSent,
+		peerLatency,
 	)
 }
 
@@ -64,3 +177,38 @@ func setServerAndNodeVersions(nodeVer string, serverID string) {
 	servAndNodeVersion.WithLabelValues("Node version: ", nodeVer).Add(0)
 	servAndNodeVersion.WithLabelValues("Server id: ", serverID).Add(0)
 }
+
+func updateMessageReceivedMetrics(peer string, cmd CommandType, size int) {
+	messagesReceived.WithLabelValues(string(cmd)).Inc()
+	bytesReceived.WithLabelValues(string(cmd)).Add(float64(size))
+	peerMessagesReceived.WithLabelValues(peer).Inc()
+	peerBytesReceived.WithLabelValues(peer).Add(float64(size))
+}
+
+func updateMessageSentMetrics(peer string, cmd CommandType, size int) {
+	messagesSent.WithLabelValues(string(cmd)).Inc()
+	bytesSent.WithLabelValues(string(cmd)).Add(float64(size))
+	peerMessagesSent.WithLabelValues(peer).Inc()
+	peerBytesSent.WithLabelValues(peer).Add(float64(size))
+}
+
+func updateMessageErrorsMetric(cmd CommandType) {
+	messageErrors.WithLabelValues(string(cmd)).Inc()
+}
+
+func updateMessagesDroppedMetric(cmd CommandType) {
+	messagesDropped.WithLabelValues(string(cmd)).Inc()
+}
+
+func updatePeerLatencyMetric(peer string, latency time.Duration) {
+	peerLatency.WithLabelValues(peer).Observe(latency.Seconds())
+}
+
+// removePeerMetrics drops per-peer metrics of disconnected peer.
+func removePeerMetrics(peer string) {
+	peerMessagesReceived.DeleteLabelValues(peer)
+	peerBytesReceived.DeleteLabelValues(peer)
+	peerMessagesSent.DeleteLabelValues(peer)
+	peerBytesSent.DeleteLabelValues(peer)
+	peerLatency.DeleteLabelValues(peer)
+}
@@ -0,0 +1,28 @@
+package secure
+
+type (
+	// Config is a P2P session security configuration.
+	Config struct {
+		// Enabled turns on secure session handshake for all P2P
+		// connections, nodes that don't support it can't connect then.
+		Enabled bool `yaml:"Enabled"`
+		// KeyFile is a path to the file containing node key in WIF
+		// format. If it's not specified, a random key is generated on
+		// every start.
+		KeyFile string `yaml:"KeyFile"`
+		// TrustedValidators is a list of validator nodes pinned by their
+		// node keys.
+		TrustedValidators []TrustedPeer `yaml:"TrustedValidators"`
+		// ConsensusFromTrustedOnly limits consensus messages exchange to
+		// peers authenticated with one of TrustedValidators keys.
+		ConsensusFromTrustedOnly bool `yaml:"ConsensusFromTrustedOnly"`
+	}
+
+	// TrustedPeer is a node pinned by its public key. Outgoing connections
+	// to Address are only established if the remote side authenticates
+	// with PublicKey.
+	TrustedPeer struct {
+		Address   string `yaml:"Address"`
+		PublicKey string `yaml:"PublicKey"`
+	}
+)
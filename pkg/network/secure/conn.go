@@ -0,0 +1,282 @@
+/*
+Package secure implements encrypted and authenticated P2P sessions.
+
+Session is established with a three-message handshake performed right after
+the connection is opened (before any P2P messages are exchanged):
+
+	initiator -> responder: e_i
+	responder -> initiator: e_r, s_r, sig_r
+	initiator -> responder: s_i, sig_i
+
+where e_* are ephemeral secp256r1 public keys, s_* are node (static) public
+keys and sig_* are signatures made with node keys over the handshake
+transcript (that includes network magic and both ephemeral keys) and the
+role of the signer. Session keys are derived from the ECDH shared secret of
+the ephemeral keys, the traffic is then encrypted with AES-256-GCM in
+length-prefixed records.
+*/
+package secure
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+)
+
+const (
+	// handshakeTimeout is the maximum duration of the handshake.
+	handshakeTimeout = 10 * time.Second
+	// maxRecordSize is the maximum size of plaintext in a single record.
+	maxRecordSize = 64 * 1024
+
+	pubKeySize    = 33
+	signatureSize = 64
+
+	roleInitiator byte = 1
+	roleResponder byte = 2
+)
+
+// protoLabel is a domain separation label used in handshake transcript.
+var protoLabel = []byte("NEO-GO P2P SESSION v1")
+
+// ErrAuthFailed is returned when the remote side fails to prove its node key
+// ownership.
+var ErrAuthFailed = errors.New("peer authentication failed")
+
+// Conn is an encrypted and authenticated connection, it implements net.Conn
+// interface.
+type Conn struct {
+	net.Conn
+
+	remoteKey *keys.PublicKey
+
+	recvAEAD  cipher.AEAD
+	recvNonce uint64
+	readBuf   []byte
+
+	writeLock sync.Mutex
+	sendAEAD  cipher.AEAD
+	sendNonce uint64
+}
+
+// Handshake performs secure session handshake over the given connection
+// using the node key specified and returns a new secured connection. The
+// initiator is the side that has opened the connection. Network magic is
+// bound to the session, so nodes from different networks can't establish
+// it.
+func Handshake(conn net.Conn, key *keys.PrivateKey, magic uint32, initiator bool) (*Conn, error) {
+	if err := conn.SetDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		return nil, err
+	}
+
+	eph, err := keys.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	ourEph := eph.PublicKey().Bytes()
+
+	var (
+		theirEph   []byte
+		transcript []byte
+		remote     *keys.PublicKey
+	)
+	if initiator {
+		if _, err = conn.Write(ourEph); err != nil {
+			return nil, err
+		}
+		buf := make([]byte, pubKeySize+pubKeySize+signatureSize)
+		if _, err = io.ReadFull(conn, buf); err != nil {
+			return nil, err
+		}
+		theirEph = buf[:pubKeySize]
+		transcript = getTranscript(magic, ourEph, theirEph)
+		remote, err = verifyAuth(buf[pubKeySize:], transcript, roleResponder)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = conn.Write(makeAuth(key, transcript, roleInitiator)); err != nil {
+			return nil, err
+		}
+	} else {
+		theirEph = make([]byte, pubKeySize)
+		if _, err = io.ReadFull(conn, theirEph); err != nil {
+			return nil, err
+		}
+		transcript = getTranscript(magic, theirEph, ourEph)
+		msg := append(ourEph, makeAuth(key, transcript, roleResponder)...)
+		if _, err = conn.Write(msg); err != nil {
+			return nil, err
+		}
+		buf := make([]byte, pubKeySize+signatureSize)
+		if _, err = io.ReadFull(conn, buf); err != nil {
+			return nil, err
+		}
+		remote, err = verifyAuth(buf, transcript, roleInitiator)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	theirEphKey, err := decodeKey(theirEph)
+	if err != nil {
+		return nil, err
+	}
+	sx, _ := elliptic.P256().ScalarMult(theirEphKey.X, theirEphKey.Y, eph.Bytes())
+	shared := make([]byte, 32)
+	sxBytes := sx.Bytes()
+	copy(shared[32-len(sxBytes):], sxBytes)
+
+	i2r, err := newAEAD(shared, transcript, roleInitiator)
+	if err != nil {
+		return nil, err
+	}
+	r2i, err := newAEAD(shared, transcript, roleResponder)
+	if err != nil {
+		return nil, err
+	}
+	if err = conn.SetDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+
+	c := &Conn{
+		Conn:      conn,
+		remoteKey: remote,
+	}
+	if initiator {
+		c.sendAEAD, c.recvAEAD = i2r, r2i
+	} else {
+		c.sendAEAD, c.recvAEAD = r2i, i2r
+	}
+	return c, nil
+}
+
+// getTranscript returns handshake transcript hash.
+func getTranscript(magic uint32, initEph, respEph []byte) []byte {
+	h := sha256.New()
+	h.Write(protoLabel)
+	var m [4]byte
+	binary.LittleEndian.PutUint32(m[:], magic)
+	h.Write(m[:])
+	h.Write(initEph)
+	h.Write(respEph)
+	return h.Sum(nil)
+}
+
+// makeAuth returns node public key followed by transcript signature for the
+// given role.
+func makeAuth(key *keys.PrivateKey, transcript []byte, role byte) []byte {
+	sig := key.Sign(append(transcript, role))
+	return append(key.PublicKey().Bytes(), sig...)
+}
+
+// verifyAuth checks the signature of the remote side made in the given role
+// and returns its public key.
+func verifyAuth(auth []byte, transcript []byte, role byte) (*keys.PublicKey, error) {
+	pub, err := decodeKey(auth[:pubKeySize])
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(append(transcript, role))
+	if !pub.Verify(auth[pubKeySize:], h[:]) {
+		return nil, ErrAuthFailed
+	}
+	return pub, nil
+}
+
+func decodeKey(b []byte) (*keys.PublicKey, error) {
+	pub, err := keys.NewPublicKeyFromBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	if pub.IsInfinity() {
+		return nil, errors.New("invalid public key")
+	}
+	return pub, nil
+}
+
+// newAEAD derives a key for the given traffic direction and creates AEAD
+// cipher for it.
+func newAEAD(shared []byte, transcript []byte, role byte) (cipher.AEAD, error) {
+	h := sha256.New()
+	h.Write(shared)
+	h.Write(transcript)
+	h.Write([]byte{role})
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// RemoteKey returns the node key remote side has authenticated with.
+func (c *Conn) RemoteKey() *keys.PublicKey {
+	return c.remoteKey
+}
+
+// Read implements net.Conn interface.
+func (c *Conn) Read(b []byte) (int, error) {
+	if len(c.readBuf) == 0 {
+		var hdr [4]byte
+		if _, err := io.ReadFull(c.Conn, hdr[:]); err != nil {
+			return 0, err
+		}
+		size := binary.LittleEndian.Uint32(hdr[:])
+		if size > uint32(maxRecordSize+c.recvAEAD.Overhead()) {
+			return 0, fmt.Errorf("record is too big: %d", size)
+		}
+		ct := make([]byte, size)
+		if _, err := io.ReadFull(c.Conn, ct); err != nil {
+			return 0, err
+		}
+		pt, err := c.recvAEAD.Open(ct[:0], nonce(c.recvNonce, c.recvAEAD), ct, nil)
+		if err != nil {
+			return 0, err
+		}
+		c.recvNonce++
+		c.readBuf = pt
+	}
+	n := copy(b, c.readBuf)
+	c.readBuf = c.readBuf[n:]
+	return n, nil
+}
+
+// Write implements net.Conn interface. It's safe for concurrent use.
+func (c *Conn) Write(b []byte) (int, error) {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	var written int
+	for len(b) > 0 {
+		chunk := b
+		if len(chunk) > maxRecordSize {
+			chunk = chunk[:maxRecordSize]
+		}
+		rec := make([]byte, 4, 4+len(chunk)+c.sendAEAD.Overhead())
+		rec = c.sendAEAD.Seal(rec, nonce(c.sendNonce, c.sendAEAD), chunk, nil)
+		binary.LittleEndian.PutUint32(rec[:4], uint32(len(rec)-4))
+		if _, err := c.Conn.Write(rec); err != nil {
+			return written, err
+		}
+		c.sendNonce++
+		written += len(chunk)
+		b = b[len(chunk):]
+	}
+	return written, nil
+}
+
+// nonce makes AEAD nonce from the record counter.
+func nonce(n uint64, aead cipher.AEAD) []byte {
+	b := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(b[len(b)-8:], n)
+	return b
+}
@@ -0,0 +1,72 @@
+package secure
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/stretchr/testify/require"
+)
+
+type handshakeResult struct {
+	conn *Conn
+	err  error
+}
+
+func newPair(t *testing.T, magicI, magicR uint32) (*keys.PrivateKey, *keys.PrivateKey, handshakeResult, handshakeResult) {
+	keyI, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	keyR, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	connI, connR := net.Pipe()
+	ch := make(chan handshakeResult)
+	go func() {
+		c, err := Handshake(connR, keyR, magicR, false)
+		if err != nil {
+			connR.Close()
+		}
+		ch <- handshakeResult{c, err}
+	}()
+	c, err := Handshake(connI, keyI, magicI, true)
+	if err != nil {
+		connI.Close()
+	}
+	resI := handshakeResult{c, err}
+	return keyI, keyR, resI, <-ch
+}
+
+func TestHandshake(t *testing.T) {
+	keyI, keyR, resI, resR := newPair(t, 42, 42)
+	require.NoError(t, resI.err)
+	require.NoError(t, resR.err)
+	defer resI.conn.Close()
+
+	require.True(t, resI.conn.RemoteKey().Equal(keyR.PublicKey()))
+	require.True(t, resR.conn.RemoteKey().Equal(keyI.PublicKey()))
+
+	small := []byte{1, 2, 3}
+	big := bytes.Repeat([]byte{0xAB, 0xCD}, maxRecordSize)
+	go func() {
+		_, _ = resI.conn.Write(small)
+		_, _ = resI.conn.Write(big)
+	}()
+	buf := make([]byte, len(small)+len(big))
+	_, err := io.ReadFull(resR.conn, buf)
+	require.NoError(t, err)
+	require.Equal(t, append(small, big...), buf)
+
+	go func() { _, _ = resR.conn.Write(small) }()
+	buf = make([]byte, len(small))
+	_, err = io.ReadFull(resI.conn, buf)
+	require.NoError(t, err)
+	require.Equal(t, small, buf)
+}
+
+func TestHandshakeWrongMagic(t *testing.T) {
+	_, _, resI, resR := newPair(t, 42, 43)
+	require.Equal(t, ErrAuthFailed, resI.err)
+	require.Error(t, resR.err)
+}
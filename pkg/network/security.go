@@ -0,0 +1,85 @@
+package network
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/network/secure"
+	"go.uber.org/zap"
+)
+
+// initSecurity loads node key and trusted validators list if secure P2P
+// sessions are enabled.
+func (s *Server) initSecurity() error {
+	cfg := s.Security
+	if !cfg.Enabled {
+		if cfg.ConsensusFromTrustedOnly {
+			return fmt.Errorf("consensus can't be limited to trusted validators with secure sessions disabled")
+		}
+		return nil
+	}
+	if cfg.KeyFile != "" {
+		data, err := ioutil.ReadFile(cfg.KeyFile)
+		if err != nil {
+			return fmt.Errorf("can't read node key: %v", err)
+		}
+		s.nodeKey, err = keys.NewPrivateKeyFromWIF(strings.TrimSpace(string(data)))
+		if err != nil {
+			return fmt.Errorf("bad node key: %v", err)
+		}
+	} else {
+		var err error
+		s.nodeKey, err = keys.NewPrivateKey()
+		if err != nil {
+			return err
+		}
+	}
+	s.log.Info("secure P2P sessions enabled",
+		zap.String("nodeKey", fmt.Sprintf("%x", s.nodeKey.PublicKey().Bytes())))
+
+	s.pinnedKeys = make(map[string]*keys.PublicKey, len(cfg.TrustedValidators))
+	for _, v := range cfg.TrustedValidators {
+		pub, err := keys.NewPublicKeyFromString(v.PublicKey)
+		if err != nil {
+			return fmt.Errorf("bad trusted validator key %s: %v", v.PublicKey, err)
+		}
+		if v.Address != "" {
+			s.pinnedKeys[v.Address] = pub
+		}
+		s.trustedKeys = append(s.trustedKeys, pub)
+	}
+	return nil
+}
+
+// secureConn performs secure session handshake over the given connection if
+// secure sessions are enabled, otherwise the connection is returned as is.
+// dialAddr is the address of outgoing connection and it's empty for incoming
+// ones. The connection is closed if handshake fails.
+func (s *Server) secureConn(conn net.Conn, dialAddr string) (net.Conn, error) {
+	if s.nodeKey == nil {
+		return conn, nil
+	}
+	sc, err := secure.Handshake(conn, s.nodeKey, uint32(s.Net), dialAddr != "")
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if pinned, ok := s.pinnedKeys[dialAddr]; ok && !pinned.Equal(sc.RemoteKey()) {
+		conn.Close()
+		return nil, fmt.Errorf("%s authenticated with unexpected key %x", dialAddr, sc.RemoteKey().Bytes())
+	}
+	return sc, nil
+}
+
+// isConsensusPeer checks whether consensus messages can be exchanged with
+// the given peer.
+func (s *Server) isConsensusPeer(p Peer) bool {
+	if !s.Security.ConsensusFromTrustedOnly {
+		return true
+	}
+	key := p.AuthKey()
+	return key != nil && s.trustedKeys.Contains(key)
+}
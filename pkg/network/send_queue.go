@@ -0,0 +1,222 @@
+package network
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+)
+
+// msgPriority is a priority class of outgoing message, lower values are more
+// important.
+type msgPriority int
+
+// Priority classes of outgoing messages.
+const (
+	prioConsensus msgPriority = iota
+	prioBlocks
+	prioTX
+	prioAddr
+
+	numPriorities = int(prioAddr) + 1
+	// minDroppablePriority is the most important class that can be dropped
+	// under pressure.
+	minDroppablePriority = prioTX
+)
+
+const (
+	// maxSendQueueSize is the size of queued messages after which
+	// low-priority messages start to be dropped.
+	maxSendQueueSize = 4 * 1024 * 1024
+	// maxSendQueueHardSize is the size of queued messages that can't be
+	// exceeded by non-blocking enqueueing.
+	maxSendQueueHardSize = 4 * maxSendQueueSize
+	// slowPeerTimeout is the maximum time the next message to be sent can
+	// wait in the overflown queue, the peer is considered to be unable to
+	// keep up after that.
+	slowPeerTimeout = 30 * time.Second
+)
+
+var errSlowPeer = errors.New("peer can't keep up with outgoing messages")
+
+// sendQueue is a per-peer queue of outgoing messages. Messages are sent in
+// priority order, when the queue size exceeds maxSendQueueSize the oldest
+// messages of droppable classes are removed to free space for new ones.
+type sendQueue struct {
+	lock   sync.Mutex
+	queues [numPriorities][]queuedMsg
+	size   int
+
+	// notify is signalled when new messages are added.
+	notify chan struct{}
+	// freed is closed (and replaced) when some space is freed and there
+	// are enqueuers waiting for it.
+	freed   chan struct{}
+	waiters int
+}
+
+// queuedMsg is a serialized message along with the time it was enqueued at.
+type queuedMsg struct {
+	pkt []byte
+	at  time.Time
+}
+
+func newSendQueue() *sendQueue {
+	return &sendQueue{
+		notify: make(chan struct{}, 1),
+		freed:  make(chan struct{}),
+	}
+}
+
+// packetPriority returns priority class of the given serialized message.
+func packetPriority(pkt []byte) msgPriority {
+	switch packetCommandType(pkt) {
+	case CMDConsensus:
+		return prioConsensus
+	case CMDTX, CMDMempool:
+		return prioTX
+	case CMDAddr, CMDGetAddr:
+		return prioAddr
+	case CMDInv, CMDGetData:
+		if len(pkt) > headerSize {
+			switch payload.InventoryType(pkt[headerSize]) {
+			case payload.ConsensusType:
+				return prioConsensus
+			case payload.TXType:
+				return prioTX
+			}
+		}
+	}
+	return prioBlocks
+}
+
+// fits checks whether the message of the given size can be added without
+// exceeding the limit. Empty queue always accepts a message.
+func (q *sendQueue) fits(size int, limit int) bool {
+	return q.size == 0 || q.size+size <= limit
+}
+
+// push adds the message to the queue. If the queue is full, the oldest
+// messages of droppable classes not more important than prio are dropped
+// first. If that's not enough, push blocks until there is enough space when
+// wait is true, otherwise the message itself is dropped if it's droppable or
+// added over the soft limit if it's not. errSlowPeer is returned if the queue
+// is full and the next message to be sent has been waiting for more than
+// slowPeerTimeout or if the hard limit is reached.
+func (q *sendQueue) push(pkt []byte, prio msgPriority, wait bool, done <-chan struct{}) error {
+	q.lock.Lock()
+	for !q.fits(len(pkt), maxSendQueueSize) {
+		if q.dropLowPriority(prio, len(pkt)) {
+			break
+		}
+		left := slowPeerTimeout - q.headAge()
+		if left <= 0 {
+			q.lock.Unlock()
+			return errSlowPeer
+		}
+		if !wait {
+			if prio >= minDroppablePriority {
+				q.lock.Unlock()
+				updateMessagesDroppedMetric(packetCommandType(pkt))
+				return nil
+			}
+			if !q.fits(len(pkt), maxSendQueueHardSize) {
+				q.lock.Unlock()
+				return errSlowPeer
+			}
+			break
+		}
+		freed := q.freed
+		q.waiters++
+		q.lock.Unlock()
+		t := time.NewTimer(left)
+		select {
+		case <-freed:
+		case <-t.C:
+		case <-done:
+			t.Stop()
+			q.lock.Lock()
+			q.waiters--
+			q.lock.Unlock()
+			return errGone
+		}
+		t.Stop()
+		q.lock.Lock()
+		q.waiters--
+	}
+	q.queues[prio] = append(q.queues[prio], queuedMsg{pkt: pkt, at: time.Now()})
+	q.size += len(pkt)
+	q.lock.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// dropLowPriority drops the oldest messages of droppable classes starting
+// from the least important one (but not more important than prio) until
+// there is enough space for the message of the given size. It returns true
+// if the message fits after that. It must be called with the lock held.
+func (q *sendQueue) dropLowPriority(prio msgPriority, size int) bool {
+	for c := msgPriority(numPriorities - 1); c >= prio && c >= minDroppablePriority; c-- {
+		for len(q.queues[c]) != 0 {
+			if q.fits(size, maxSendQueueSize) {
+				return true
+			}
+			old := q.shift(c)
+			updateMessagesDroppedMetric(packetCommandType(old))
+		}
+	}
+	return q.fits(size, maxSendQueueSize)
+}
+
+// headAge returns the time the next message to be sent has been waiting for.
+// It must be called with the lock held.
+func (q *sendQueue) headAge() time.Duration {
+	for c := range q.queues {
+		if len(q.queues[c]) != 0 {
+			return time.Since(q.queues[c][0].at)
+		}
+	}
+	return 0
+}
+
+// shift removes the oldest message of the given class from the queue and
+// returns it. It must be called with the lock held.
+func (q *sendQueue) shift(c msgPriority) []byte {
+	pkt := q.queues[c][0].pkt
+	q.queues[c][0] = queuedMsg{}
+	q.queues[c] = q.queues[c][1:]
+	q.size -= len(pkt)
+	return pkt
+}
+
+// pop returns the most important message blocking until there is one or
+// done is closed (nil is returned then).
+func (q *sendQueue) pop(done <-chan struct{}) []byte {
+	for {
+		q.lock.Lock()
+		for c := range q.queues {
+			if len(q.queues[c]) == 0 {
+				continue
+			}
+			pkt := q.shift(msgPriority(c))
+			if q.waiters != 0 {
+				close(q.freed)
+				q.freed = make(chan struct{})
+			}
+			q.lock.Unlock()
+			return pkt
+		}
+		q.lock.Unlock()
+
+		select {
+		case <-q.notify:
+		case <-done:
+			return nil
+		}
+	}
+}
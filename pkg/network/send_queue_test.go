@@ -0,0 +1,130 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPacket(t *testing.T, cmd CommandType, size int) []byte {
+	pkt := make([]byte, headerSize+size)
+	copy(pkt[4:], cmd)
+	require.Equal(t, cmd, packetCommandType(pkt))
+	return pkt
+}
+
+func TestPacketPriority(t *testing.T) {
+	inv := func(typ payload.InventoryType) []byte {
+		pkt := newTestPacket(t, CMDInv, 1)
+		pkt[headerSize] = byte(typ)
+		return pkt
+	}
+	require.Equal(t, prioConsensus, packetPriority(newTestPacket(t, CMDConsensus, 0)))
+	require.Equal(t, prioConsensus, packetPriority(inv(payload.ConsensusType)))
+	require.Equal(t, prioBlocks, packetPriority(newTestPacket(t, CMDBlock, 0)))
+	require.Equal(t, prioBlocks, packetPriority(newTestPacket(t, CMDPing, 0)))
+	require.Equal(t, prioBlocks, packetPriority(inv(payload.BlockType)))
+	require.Equal(t, prioTX, packetPriority(newTestPacket(t, CMDTX, 0)))
+	require.Equal(t, prioTX, packetPriority(inv(payload.TXType)))
+	require.Equal(t, prioAddr, packetPriority(newTestPacket(t, CMDAddr, 0)))
+}
+
+func TestSendQueueOrder(t *testing.T) {
+	q := newSendQueue()
+	done := make(chan struct{})
+	addr := newTestPacket(t, CMDAddr, 1)
+	tx := newTestPacket(t, CMDTX, 1)
+	block := newTestPacket(t, CMDBlock, 1)
+	cons := newTestPacket(t, CMDConsensus, 1)
+	for _, pkt := range [][]byte{addr, tx, block, cons} {
+		require.NoError(t, q.push(pkt, packetPriority(pkt), false, done))
+	}
+	require.Equal(t, cons, q.pop(done))
+	require.Equal(t, block, q.pop(done))
+	require.Equal(t, tx, q.pop(done))
+	require.Equal(t, addr, q.pop(done))
+
+	close(done)
+	require.Nil(t, q.pop(done))
+}
+
+func TestSendQueueOverflow(t *testing.T) {
+	const size = maxSendQueueSize/4 - headerSize
+	q := newSendQueue()
+	done := make(chan struct{})
+
+	addr := newTestPacket(t, CMDAddr, size)
+	txs := make([][]byte, 3)
+	for i := range txs {
+		txs[i] = newTestPacket(t, CMDTX, size)
+		txs[i][headerSize] = byte(i)
+	}
+	require.NoError(t, q.push(addr, prioAddr, false, done))
+	for _, tx := range txs {
+		require.NoError(t, q.push(tx, prioTX, false, done))
+	}
+
+	t.Run("drop oldest", func(t *testing.T) {
+		// Addresses are dropped first, then the oldest transaction.
+		block := newTestPacket(t, CMDBlock, size)
+		require.NoError(t, q.push(block, prioBlocks, false, done))
+		require.NoError(t, q.push(block, prioBlocks, false, done))
+		require.Equal(t, 0, len(q.queues[prioAddr]))
+		require.Equal(t, 2, len(q.queues[prioTX]))
+		require.Equal(t, txs[1], q.queues[prioTX][0].pkt)
+	})
+	t.Run("drop new", func(t *testing.T) {
+		// Less important messages can't push out more important ones.
+		require.NoError(t, q.push(addr, prioAddr, false, done))
+		require.Equal(t, 0, len(q.queues[prioAddr]))
+	})
+	t.Run("over soft limit", func(t *testing.T) {
+		block := newTestPacket(t, CMDBlock, size)
+		require.NoError(t, q.push(block, prioBlocks, false, done))
+		require.NoError(t, q.push(block, prioBlocks, false, done))
+		require.Equal(t, 0, len(q.queues[prioTX]))
+		require.NoError(t, q.push(block, prioBlocks, false, done))
+		require.True(t, q.size > maxSendQueueSize)
+	})
+	t.Run("wait", func(t *testing.T) {
+		block := newTestPacket(t, CMDBlock, size)
+		errCh := make(chan error)
+		go func() { errCh <- q.push(block, prioBlocks, true, done) }()
+		select {
+		case <-errCh:
+			t.Fatal("push should block")
+		case <-time.After(50 * time.Millisecond):
+		}
+		queueSize := func() int {
+			q.lock.Lock()
+			defer q.lock.Unlock()
+			return q.size
+		}
+		for queueSize() > maxSendQueueSize-len(block) {
+			require.NotNil(t, q.pop(done))
+		}
+		select {
+		case err := <-errCh:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("push should be unblocked")
+		}
+	})
+	t.Run("slow peer", func(t *testing.T) {
+		for q.size <= maxSendQueueSize {
+			require.NoError(t, q.push(newTestPacket(t, CMDBlock, size), prioBlocks, false, done))
+		}
+		q.queues[prioBlocks][0].at = time.Now().Add(-slowPeerTimeout)
+		require.Equal(t, errSlowPeer, q.push(addr, prioAddr, false, done))
+		require.Equal(t, errSlowPeer, q.push(addr, prioBlocks, true, done))
+	})
+	t.Run("hard limit", func(t *testing.T) {
+		q := newSendQueue()
+		block := newTestPacket(t, CMDBlock, maxSendQueueHardSize/2-headerSize)
+		require.NoError(t, q.push(block, prioBlocks, false, done))
+		require.NoError(t, q.push(block, prioBlocks, false, done))
+		require.Equal(t, errSlowPeer, q.push(block, prioBlocks, false, done))
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/blockchainer"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"go.uber.org/atomic"
@@ -69,6 +70,20 @@ type (
 
 		consensusStarted *atomic.Bool
 
+		// cbLock protects compactBlocks.
+		cbLock sync.Mutex
+		// compactBlocks are compact blocks waiting for missing
+		// transactions.
+		compactBlocks map[util.Uint256]*pendingCompactBlock
+
+		// nodeKey is used to authenticate secure P2P sessions, it's nil
+		// when they're disabled.
+		nodeKey *keys.PrivateKey
+		// pinnedKeys maps trusted validator addresses to their node keys.
+		pinnedKeys map[string]*keys.PublicKey
+		// trustedKeys are node keys of trusted validators.
+		trustedKeys keys.PublicKeys
+
 		log *zap.Logger
 	}
 
@@ -101,6 +116,7 @@ func NewServer(config ServerConfig, chain blockchainer.Blockchainer, log *zap.Lo
 		consensusStarted: atomic.NewBool(false),
 		log:              log,
 		transactions:     make(chan *transaction.Transaction, 64),
+		compactBlocks:    make(map[util.Uint256]*pendingCompactBlock),
 	}
 	s.bQueue = newBlockQueue(maxBlockBatch, chain, log, func(b *block.Block) {
 		if s.consensusStarted.Load() {
@@ -148,7 +164,19 @@ func NewServer(config ServerConfig, chain blockchainer.Blockchainer, log *zap.Lo
 		s.AttemptConnPeers = defaultAttemptConnPeers
 	}
 
-	s.transport = NewTCPTransport(s, fmt.Sprintf("%s:%d", config.Address, config.Port), s.log)
+	if err := s.initSecurity(); err != nil {
+		return nil, err
+	}
+
+	bindAddr := fmt.Sprintf("%s:%d", config.Address, config.Port)
+	switch s.Transport {
+	case "", "tcp":
+		s.transport = NewTCPTransport(s, bindAddr, s.log)
+	case "ws":
+		s.transport = NewWSTransport(s, bindAddr, s.log)
+	default:
+		return nil, fmt.Errorf("unknown transport %q", s.Transport)
+	}
 	s.discovery = NewDefaultDiscovery(
 		s.DialTimeout,
 		s.transport,
@@ -194,6 +222,7 @@ func (s *Server) Shutdown() {
 		p.Disconnect(errServerShutdown)
 	}
 	s.bQueue.discard()
+	s.consensus.Shutdown()
 	close(s.quit)
 }
 
@@ -221,6 +250,25 @@ func (s *Server) ConnectedPeers() []string {
 	return peers
 }
 
+// ConsensusState returns the current state of consensus process, it's nil
+// if consensus is not running.
+func (s *Server) ConsensusState() *consensus.State {
+	return s.consensus.GetState()
+}
+
+// startPeer performs secure session handshake for the given connection (if
+// enabled) and starts handling it as a new peer. dialAddr is the address of
+// outgoing connection and it's empty for incoming ones.
+func (s *Server) startPeer(conn net.Conn, dialAddr string) error {
+	conn, err := s.secureConn(conn, dialAddr)
+	if err != nil {
+		return err
+	}
+	p := NewTCPPeer(conn, s)
+	go p.handleConn()
+	return nil
+}
+
 // run is a goroutine that starts another goroutine to manage protocol specifics
 // while itself dealing with peers management (handling connects/disconnects).
 func (s *Server) run() {
@@ -465,6 +513,9 @@ func (s *Server) handlePong(p Peer, pong *payload.Ping) error {
 
 // handleInvCmd processes the received inventory.
 func (s *Server) handleInvCmd(p Peer, inv *payload.Inventory) error {
+	if inv.Type == payload.ConsensusType && !s.isConsensusPeer(p) {
+		return nil
+	}
 	reqHashes := make([]util.Uint256, 0)
 	var typExists = map[payload.InventoryType]func(util.Uint256) bool{
 		payload.TXType:    s.chain.HasTransaction,
@@ -512,6 +563,9 @@ func (s *Server) handleGetDataCmd(p Peer, inv *payload.Inventory) error {
 				msg = s.MkMsg(CMDBlock, b)
 			}
 		case payload.ConsensusType:
+			if !s.isConsensusPeer(p) {
+				return nil
+			}
 			if cp := s.consensus.GetPayload(hash); cp != nil {
 				msg = s.MkMsg(CMDConsensus, cp)
 			}
@@ -593,9 +647,13 @@ func (s *Server) handleGetHeadersCmd(p Peer, gh *payload.GetBlocks) error {
 	return p.EnqueueP2PMessage(msg)
 }
 
-// handleConsensusCmd processes received consensus payload.
+// handleConsensusCmd processes received consensus payload. Payloads from
+// peers that are not allowed to take part in consensus are ignored.
 // It never returns an error.
-func (s *Server) handleConsensusCmd(cp *consensus.Payload) error {
+func (s *Server) handleConsensusCmd(p Peer, cp *consensus.Payload) error {
+	if !s.isConsensusPeer(p) {
+		return nil
+	}
 	s.consensus.OnPayload(cp)
 	return nil
 }
@@ -628,10 +686,15 @@ func (s *Server) handleGetAddrCmd(p Peer) error {
 	}
 	alist := payload.NewAddressList(len(addrs))
 	ts := time.Now()
-	for i, addr := range addrs {
+	alist.Addrs = alist.Addrs[:0]
+	for _, addr := range addrs {
+		// WebSocket addresses can't be represented in addr payload.
+		if isWSAddr(addr) {
+			continue
+		}
 		// we know it's a good address, so it can't fail
 		netaddr, _ := net.ResolveTCPAddr("tcp", addr)
-		alist.Addrs[i] = payload.NewAddressAndTime(netaddr, ts)
+		alist.Addrs = append(alist.Addrs, payload.NewAddressAndTime(netaddr, ts))
 	}
 	return p.EnqueueP2PMessage(s.MkMsg(CMDAddr, alist))
 }
@@ -710,9 +773,18 @@ func (s *Server) handleMessage(peer Peer, msg *Message) error {
 		case CMDBlock:
 			block := msg.Payload.(*block.Block)
 			return s.handleBlockCmd(peer, block)
+		case CMDCmpctBlock:
+			cb := msg.Payload.(*payload.CompactBlock)
+			return s.handleCompactBlockCmd(peer, cb)
+		case CMDGetBlockTxn:
+			req := msg.Payload.(*payload.GetBlockTxn)
+			return s.handleGetBlockTxnCmd(peer, req)
+		case CMDBlockTxn:
+			bt := msg.Payload.(*payload.BlockTxn)
+			return s.handleBlockTxnCmd(peer, bt)
 		case CMDConsensus:
 			cp := msg.Payload.(*consensus.Payload)
-			return s.handleConsensusCmd(cp)
+			return s.handleConsensusCmd(peer, cp)
 		case CMDTX:
 			tx := msg.Payload.(*transaction.Transaction)
 			return s.handleTxCmd(tx)
@@ -749,7 +821,7 @@ func (s *Server) handleNewPayload(p *consensus.Payload) {
 	msg := s.MkMsg(CMDInv, payload.NewInventory(payload.ConsensusType, []util.Uint256{p.Hash()}))
 	// It's high priority because it directly affects consensus process,
 	// even though it's just an inv.
-	s.broadcastHPMessage(msg)
+	s.iteratePeersWithSendMsg(msg, Peer.EnqueueHPPacket, s.isConsensusPeer)
 }
 
 func (s *Server) requestTx(hashes ...util.Uint256) {
@@ -792,12 +864,20 @@ func (s *Server) broadcastHPMessage(msg *Message) {
 }
 
 // relayBlock tells all the other connected nodes about the given block.
+// Peers supporting compact blocks receive it in compact form, others get
+// inventory announcement.
 func (s *Server) relayBlock(b *block.Block) {
-	msg := s.MkMsg(CMDInv, payload.NewInventory(payload.BlockType, []util.Uint256{b.Hash()}))
 	// Filter out nodes that are more current (avoid spamming the network
 	// during initial sync).
-	s.iteratePeersWithSendMsg(msg, Peer.EnqueuePacket, func(p Peer) bool {
+	behind := func(p Peer) bool {
 		return p.Handshaked() && p.LastBlockIndex() < b.Index
+	}
+	msg := s.MkMsg(CMDInv, payload.NewInventory(payload.BlockType, []util.Uint256{b.Hash()}))
+	s.iteratePeersWithSendMsg(msg, Peer.EnqueuePacket, func(p Peer) bool {
+		return behind(p) && !supportsCompactBlocks(p)
+	})
+	s.iteratePeersWithSendMsg(s.newCompactBlockMsg(b), Peer.EnqueuePacket, func(p Peer) bool {
+		return behind(p) && supportsCompactBlocks(p)
 	})
 }
 
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/network/secure"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"go.uber.org/zap/zapcore"
 )
@@ -44,8 +45,13 @@ type (
 		Relay bool
 
 		// Seeds are a list of initial nodes used to establish connectivity.
+		// Addresses with ws:// prefix are connected to via WebSocket.
 		Seeds []string
 
+		// Transport is the name of the transport used to accept incoming
+		// connections, either "tcp" (default) or "ws".
+		Transport string
+
 		// Maximum duration a single dial may take.
 		DialTimeout time.Duration
 
@@ -61,6 +67,9 @@ type (
 		// Level of the internal logger.
 		LogLevel zapcore.Level
 
+		// Security is a secure P2P sessions configuration.
+		Security secure.Config
+
 		// Wallet is a wallet configuration.
 		Wallet *wallet.Config
 
@@ -76,7 +85,7 @@ func NewServerConfig(cfg config.Config) ServerConfig {
 	protoConfig := cfg.ProtocolConfiguration
 
 	var wc *wallet.Config
-	if appConfig.UnlockWallet.Path != "" {
+	if appConfig.UnlockWallet.Path != "" || appConfig.UnlockWallet.RemoteSigner != "" {
 		wc = &appConfig.UnlockWallet
 	}
 
@@ -87,6 +96,7 @@ func NewServerConfig(cfg config.Config) ServerConfig {
 		Net:               protoConfig.Magic,
 		Relay:             appConfig.Relay,
 		Seeds:             protoConfig.SeedList,
+		Transport:         appConfig.Transport,
 		DialTimeout:       appConfig.DialTimeout * time.Second,
 		ProtoTickInterval: appConfig.ProtoTickInterval * time.Second,
 		PingInterval:      appConfig.PingInterval * time.Second,
@@ -94,6 +104,7 @@ func NewServerConfig(cfg config.Config) ServerConfig {
 		MaxPeers:          appConfig.MaxPeers,
 		AttemptConnPeers:  appConfig.AttemptConnPeers,
 		MinPeers:          appConfig.MinPeers,
+		Security:          appConfig.P2PSecurity,
 		Wallet:            wc,
 		TimePerBlock:      time.Duration(protoConfig.SecondsPerBlock) * time.Second,
 	}
@@ -4,7 +4,9 @@ import (
 	"net"
 	"testing"
 
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -23,7 +25,7 @@ func TestSendVersion(t *testing.T) {
 		version := msg.Payload.(*payload.Version)
 		assert.NotZero(t, version.Nonce)
 		assert.Equal(t, uint16(3000), version.Port)
-		assert.Equal(t, uint64(1), version.Services)
+		assert.Equal(t, uint64(1)|payload.CompactBlocksService, version.Services)
 		assert.Equal(t, uint32(0), version.Version)
 		assert.Equal(t, []byte("/test/"), version.UserAgent)
 		assert.Equal(t, uint32(0), version.StartHeight)
@@ -105,3 +107,32 @@ func TestRequestHeaders(t *testing.T) {
 	}
 	s.requestHeaders(p)
 }
+
+func TestConsensusFromTrustedOnly(t *testing.T) {
+	var (
+		s = newTestServer(t)
+		p = newLocalPeer(t, s)
+	)
+	trusted, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	s.Security.ConsensusFromTrustedOnly = true
+	s.trustedKeys = keys.PublicKeys{trusted.PublicKey()}
+
+	require.False(t, s.isConsensusPeer(p))
+
+	// Consensus inventory from untrusted peer is ignored.
+	p.messageHandler = func(t *testing.T, msg *Message) {
+		t.Fatalf("unexpected message: %s", msg.CommandType())
+	}
+	inv := payload.NewInventory(payload.ConsensusType, []util.Uint256{{1}})
+	require.NoError(t, s.handleInvCmd(p, inv))
+	require.NoError(t, s.handleConsensusCmd(p, nil))
+
+	other, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	p.authKey = other.PublicKey()
+	require.False(t, s.isConsensusPeer(p))
+
+	p.authKey = trusted.PublicKey()
+	require.True(t, s.isConsensusPeer(p))
+}
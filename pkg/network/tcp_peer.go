@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/network/secure"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 )
 
@@ -20,10 +24,6 @@ const (
 	versionReceived
 	verAckSent
 	verAckReceived
-
-	requestQueueSize   = 32
-	p2pMsgQueueSize    = 16
-	hpRequestQueueSize = 4
 )
 
 var (
@@ -38,6 +38,9 @@ var (
 type TCPPeer struct {
 	// underlying TCP connection.
 	conn net.Conn
+	// addr is a string representation of the remote address used for
+	// metrics.
+	addr string
 	// The server this peer belongs to.
 	server *Server
 	// The version of the peer.
@@ -49,79 +52,91 @@ type TCPPeer struct {
 	finale    sync.Once
 	handShake handShakeStage
 
-	done     chan struct{}
-	sendQ    chan []byte
-	p2pSendQ chan []byte
-	hpSendQ  chan []byte
+	done  chan struct{}
+	sendQ *sendQueue
 
 	wg sync.WaitGroup
 
 	// number of sent pings.
 	pingSent  int
 	pingTimer *time.Timer
+	// time of the first outstanding ping.
+	pingSentAt time.Time
+
+	connectedAt   time.Time
+	bytesReceived *atomic.Uint64
+	bytesSent     *atomic.Uint64
 }
 
 // NewTCPPeer returns a TCPPeer structure based on the given connection.
 func NewTCPPeer(conn net.Conn, s *Server) *TCPPeer {
 	return &TCPPeer{
-		conn:     conn,
-		server:   s,
-		done:     make(chan struct{}),
-		sendQ:    make(chan []byte, requestQueueSize),
-		p2pSendQ: make(chan []byte, p2pMsgQueueSize),
-		hpSendQ:  make(chan []byte, hpRequestQueueSize),
+		conn:          conn,
+		addr:          conn.RemoteAddr().String(),
+		server:        s,
+		done:          make(chan struct{}),
+		sendQ:         newSendQueue(),
+		connectedAt:   time.Now(),
+		bytesReceived: atomic.NewUint64(0),
+		bytesSent:     atomic.NewUint64(0),
 	}
 }
 
-// putPacketIntoQueue puts given message into the given queue if the peer has
-// done handshaking.
-func (p *TCPPeer) putPacketIntoQueue(queue chan<- []byte, msg []byte) error {
+// putPacketIntoQueue puts given message into the send queue with the given
+// priority if the peer has done handshaking. If wait is true it blocks until
+// there is enough space in the queue. The peer is disconnected if it can't
+// keep up with outgoing messages.
+func (p *TCPPeer) putPacketIntoQueue(prio msgPriority, wait bool, msg []byte) error {
 	if !p.Handshaked() {
 		return errStateMismatch
 	}
 	select {
-	case queue <- msg:
 	case <-p.done:
 		return errGone
+	default:
 	}
-	return nil
+	err := p.sendQ.push(msg, prio, wait, p.done)
+	if err == errSlowPeer {
+		// This can be called from the server's main loop, so disconnection
+		// can't be done synchronously.
+		go p.Disconnect(err)
+	}
+	return err
 }
 
 // EnqueuePacket implements the Peer interface.
 func (p *TCPPeer) EnqueuePacket(msg []byte) error {
-	return p.putPacketIntoQueue(p.sendQ, msg)
+	return p.putPacketIntoQueue(packetPriority(msg), false, msg)
 }
 
-// putMessageIntoQueue serializes given Message and puts it into given queue if
-// the peer has done handshaking.
-func (p *TCPPeer) putMsgIntoQueue(queue chan<- []byte, msg *Message) error {
+// EnqueueMessage is a temporary wrapper that sends a message via
+// EnqueuePacket if there is no error in serializing it.
+func (p *TCPPeer) EnqueueMessage(msg *Message) error {
 	b, err := msg.Bytes()
 	if err != nil {
 		return err
 	}
-	return p.putPacketIntoQueue(queue, b)
-}
-
-// EnqueueMessage is a temporary wrapper that sends a message via
-// EnqueuePacket if there is no error in serializing it.
-func (p *TCPPeer) EnqueueMessage(msg *Message) error {
-	return p.putMsgIntoQueue(p.sendQ, msg)
+	return p.EnqueuePacket(b)
 }
 
 // EnqueueP2PPacket implements the Peer interface.
 func (p *TCPPeer) EnqueueP2PPacket(msg []byte) error {
-	return p.putPacketIntoQueue(p.p2pSendQ, msg)
+	return p.putPacketIntoQueue(packetPriority(msg), true, msg)
 }
 
 // EnqueueP2PMessage implements the Peer interface.
 func (p *TCPPeer) EnqueueP2PMessage(msg *Message) error {
-	return p.putMsgIntoQueue(p.p2pSendQ, msg)
+	b, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+	return p.EnqueueP2PPacket(b)
 }
 
 // EnqueueHPPacket implements the Peer interface. It the peer is not yet
 // handshaked it's a noop.
 func (p *TCPPeer) EnqueueHPPacket(msg []byte) error {
-	return p.putPacketIntoQueue(p.hpSendQ, msg)
+	return p.putPacketIntoQueue(prioConsensus, false, msg)
 }
 
 func (p *TCPPeer) writeMsg(msg *Message) error {
@@ -130,9 +145,19 @@ func (p *TCPPeer) writeMsg(msg *Message) error {
 		return err
 	}
 
-	_, err = p.conn.Write(b)
+	return p.write(b)
+}
 
-	return err
+// write writes given packet to the connection and updates traffic
+// statistics.
+func (p *TCPPeer) write(pkt []byte) error {
+	_, err := p.conn.Write(pkt)
+	if err != nil {
+		return err
+	}
+	p.bytesSent.Add(uint64(len(pkt)))
+	updateMessageSentMetrics(p.addr, packetCommandType(pkt), len(pkt))
+	return nil
 }
 
 // handleConn handles the read side of the connection, it should be started as
@@ -157,7 +182,11 @@ func (p *TCPPeer) handleConn() {
 			} else if err != nil {
 				break
 			}
+			size := headerSize + int(msg.Length)
+			p.bytesReceived.Add(uint64(size))
+			updateMessageReceivedMetrics(p.addr, msg.CommandType(), size)
 			if err = p.server.handleMessage(p, msg); err != nil {
+				updateMessageErrorsMetric(msg.CommandType())
 				if p.Handshaked() {
 					err = fmt.Errorf("handling %s message: %v", msg.CommandType(), err)
 				}
@@ -169,53 +198,19 @@ func (p *TCPPeer) handleConn() {
 }
 
 // handleQueues is a goroutine that is started automatically to handle
-// send queues.
+// send queue.
 func (p *TCPPeer) handleQueues() {
 	var err error
-	// p2psend queue shares its time with send queue in around
-	// ((p2pSkipDivisor - 1) * 2 + 1)/1 ratio, ratio because the third
-	// select can still choose p2psend over send.
-	var p2pSkipCounter uint32
-	const p2pSkipDivisor = 4
 
 	for {
-		var msg []byte
-
-		// This one is to give priority to the hp queue
-		select {
-		case <-p.done:
-			return
-		case msg = <-p.hpSendQ:
-		default:
-		}
-
-		// Skip this select every p2pSkipDivisor iteration.
-		if msg == nil && p2pSkipCounter%p2pSkipDivisor != 0 {
-			// Then look at the p2p queue.
-			select {
-			case <-p.done:
-				return
-			case msg = <-p.hpSendQ:
-			case msg = <-p.p2pSendQ:
-			default:
-			}
-		}
-		// If there is no message in HP or P2P queues, block until one
-		// appears in any of the queues.
+		msg := p.sendQ.pop(p.done)
 		if msg == nil {
-			select {
-			case <-p.done:
-				return
-			case msg = <-p.hpSendQ:
-			case msg = <-p.p2pSendQ:
-			case msg = <-p.sendQ:
-			}
+			return
 		}
-		_, err = p.conn.Write(msg)
+		err = p.write(msg)
 		if err != nil {
 			break
 		}
-		p2pSkipCounter++
 	}
 	p.Disconnect(err)
 }
@@ -345,13 +340,20 @@ func (p *TCPPeer) RemoteAddr() net.Addr {
 func (p *TCPPeer) PeerAddr() net.Addr {
 	remote := p.conn.RemoteAddr()
 	// The network can be non-tcp in unit tests.
-	if p.version == nil || remote.Network() != "tcp" {
+	if p.version == nil || (remote.Network() != "tcp" && remote.Network() != "ws") {
 		return p.RemoteAddr()
 	}
-	host, _, err := net.SplitHostPort(remote.String())
+	host, _, err := net.SplitHostPort(strings.TrimPrefix(remote.String(), wsScheme))
 	if err != nil {
 		return p.RemoteAddr()
 	}
+	if remote.Network() == "ws" {
+		addr, err := newWSAddr(host, p.version.Port)
+		if err != nil {
+			return p.RemoteAddr()
+		}
+		return addr
+	}
 	addrString := net.JoinHostPort(host, strconv.Itoa(int(p.version.Port)))
 	tcpAddr, err := net.ResolveTCPAddr("tcp", addrString)
 	if err != nil {
@@ -365,6 +367,7 @@ func (p *TCPPeer) Disconnect(err error) {
 	p.finale.Do(func() {
 		close(p.done)
 		p.conn.Close()
+		removePeerMetrics(p.addr)
 		p.server.unregister <- peerDrop{p, err}
 	})
 }
@@ -374,6 +377,23 @@ func (p *TCPPeer) Version() *payload.Version {
 	return p.version
 }
 
+// AuthKey implements the Peer interface.
+func (p *TCPPeer) AuthKey() *keys.PublicKey {
+	if sc, ok := p.conn.(*secure.Conn); ok {
+		return sc.RemoteKey()
+	}
+	return nil
+}
+
+// Stats implements the Peer interface.
+func (p *TCPPeer) Stats() PeerStats {
+	return PeerStats{
+		ConnectedAt:   p.connectedAt,
+		BytesReceived: p.bytesReceived.Load(),
+		BytesSent:     p.bytesSent.Load(),
+	}
+}
+
 // LastBlockIndex returns last block index.
 func (p *TCPPeer) LastBlockIndex() uint32 {
 	p.lock.RLock()
@@ -390,6 +410,7 @@ func (p *TCPPeer) SendPing(msg *Message) error {
 	p.lock.Lock()
 	p.pingSent++
 	if p.pingTimer == nil {
+		p.pingSentAt = time.Now()
 		p.pingTimer = time.AfterFunc(p.server.PingTimeout, func() {
 			p.Disconnect(errPingPong)
 		})
@@ -411,6 +432,7 @@ func (p *TCPPeer) HandlePong(pong *payload.Ping) error {
 	if p.pingSent < 0 {
 		return errUnexpectedPong
 	}
+	updatePeerLatencyMetric(p.addr, time.Since(p.pingSentAt))
 	p.lastBlockIndex = pong.LastBlockIndex
 	return nil
 }
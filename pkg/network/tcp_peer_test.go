@@ -3,6 +3,7 @@ package network
 import (
 	"net"
 	"testing"
+	"time"
 
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/stretchr/testify/require"
@@ -83,3 +84,51 @@ func TestPeerHandshake(t *testing.T) {
 	require.NoError(t, tcpS.EnqueueMessage(&Message{}))
 	require.NoError(t, tcpC.EnqueueMessage(&Message{}))
 }
+
+func TestPeerFullSendQueue(t *testing.T) {
+	server, _ := net.Pipe()
+	p := NewTCPPeer(server, newTestServer(t))
+	p.handShake = verAckReceived | verAckSent | versionReceived | versionSent
+	defer close(p.done)
+
+	// Queue is not drained, so fill it up with blocks.
+	const size = maxSendQueueSize/4 - headerSize
+	for p.sendQ.size+size+headerSize <= maxSendQueueSize {
+		require.NoError(t, p.EnqueuePacket(newTestPacket(t, CMDBlock, size)))
+	}
+
+	// Transactions requested by consensus are not dropped.
+	getdata := newTestPacket(t, CMDGetData, 1)
+	getdata[headerSize] = byte(payload.TXType)
+	require.NoError(t, p.EnqueueHPPacket(getdata))
+	require.Equal(t, [][]byte{getdata}, queuedPackets(p.sendQ, prioConsensus))
+
+	// Transaction reply waits for free space.
+	tx := newTestPacket(t, CMDTX, size)
+	errCh := make(chan error)
+	go func() { errCh <- p.EnqueueP2PPacket(tx) }()
+	select {
+	case <-errCh:
+		t.Fatal("enqueue should block")
+	case <-time.After(50 * time.Millisecond):
+	}
+	require.Equal(t, getdata, p.sendQ.pop(p.done))
+	require.NotNil(t, p.sendQ.pop(p.done))
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("enqueue should be unblocked")
+	}
+	require.Equal(t, [][]byte{tx}, queuedPackets(p.sendQ, prioTX))
+}
+
+func queuedPackets(q *sendQueue, prio msgPriority) [][]byte {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	var res [][]byte
+	for _, m := range q.queues[prio] {
+		res = append(res, m.pkt)
+	}
+	return res
+}
@@ -28,15 +28,23 @@ func NewTCPTransport(s *Server, bindAddr string, log *zap.Logger) *TCPTransport
 	}
 }
 
-// Dial implements the Transporter interface.
+// Dial implements the Transporter interface. Addresses with ws:// prefix
+// are dialed via WebSocket.
 func (t *TCPTransport) Dial(addr string, timeout time.Duration) error {
+	if isWSAddr(addr) {
+		return dialWS(t.server, addr, timeout)
+	}
+	return dialTCP(t.server, addr, timeout)
+}
+
+// dialTCP establishes a TCP connection to the given address and starts a new
+// peer for it.
+func dialTCP(s *Server, addr string, timeout time.Duration) error {
 	conn, err := net.DialTimeout("tcp", addr, timeout)
 	if err != nil {
 		return err
 	}
-	p := NewTCPPeer(conn, t.server)
-	go p.handleConn()
-	return nil
+	return s.startPeer(conn, addr)
 }
 
 // Accept implements the Transporter interface.
@@ -58,8 +66,11 @@ func (t *TCPTransport) Accept() {
 			}
 			continue
 		}
-		p := NewTCPPeer(conn, t.server)
-		go p.handleConn()
+		go func(conn net.Conn) {
+			if err := t.server.startPeer(conn, ""); err != nil {
+				t.log.Warn("failed to start peer", zap.Stringer("addr", conn.RemoteAddr()), zap.Error(err))
+			}
+		}(conn)
 	}
 }
 
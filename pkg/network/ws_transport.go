@@ -0,0 +1,231 @@
+package network
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// wsScheme is an address prefix used to mark peers reachable via WebSocket
+// transport.
+const wsScheme = "ws://"
+
+// WSTransport allows network communication over WebSocket connections. It
+// uses the same message framing as TCPTransport, every write is sent as a
+// separate binary WebSocket message and reads treat incoming messages as a
+// continuous stream.
+type WSTransport struct {
+	log      *zap.Logger
+	server   *Server
+	bindAddr string
+
+	lock    sync.Mutex
+	httpSrv *http.Server
+
+	upgrader websocket.Upgrader
+}
+
+// wsConn is a net.Conn adapter for websocket.Conn.
+type wsConn struct {
+	conn *websocket.Conn
+
+	// reader is a reader of the current incoming message.
+	reader  io.Reader
+	writeMu sync.Mutex
+}
+
+// wsAddr is a net.Addr of a WebSocket peer, it's printed with ws:// scheme
+// so that discovery dials it via the appropriate transport.
+type wsAddr struct {
+	*net.TCPAddr
+}
+
+// NewWSTransport returns a new WSTransport that will listen for
+// new incoming peer connections.
+func NewWSTransport(s *Server, bindAddr string, log *zap.Logger) *WSTransport {
+	return &WSTransport{
+		log:      log,
+		server:   s,
+		bindAddr: bindAddr,
+		upgrader: websocket.Upgrader{
+			// Nodes are not browsers, there is no origin to check.
+			CheckOrigin: func(*http.Request) bool { return true },
+		},
+	}
+}
+
+// Dial implements the Transporter interface. Only addresses with ws:// prefix
+// are dialed via WebSocket, others are plain TCP ones.
+func (t *WSTransport) Dial(addr string, timeout time.Duration) error {
+	if !isWSAddr(addr) {
+		return dialTCP(t.server, addr, timeout)
+	}
+	return dialWS(t.server, addr, timeout)
+}
+
+// dialWS establishes a WebSocket connection to the given address (with
+// ws:// prefix) and starts a new peer for it.
+func dialWS(s *Server, addr string, timeout time.Duration) error {
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: timeout,
+	}
+	conn, _, err := dialer.Dial(addr, nil)
+	if err != nil {
+		return err
+	}
+	return s.startPeer(newWSConn(conn), addr)
+}
+
+// isWSAddr checks whether the given address has ws:// prefix.
+func isWSAddr(addr string) bool {
+	return strings.HasPrefix(addr, wsScheme)
+}
+
+// Accept implements the Transporter interface.
+func (t *WSTransport) Accept() {
+	l, err := net.Listen("tcp", t.bindAddr)
+	if err != nil {
+		t.log.Panic("WS listen error", zap.Error(err))
+		return
+	}
+
+	t.lock.Lock()
+	t.httpSrv = &http.Server{Handler: t}
+	srv := t.httpSrv
+	t.lock.Unlock()
+
+	err = srv.Serve(l)
+	if err != http.ErrServerClosed {
+		t.log.Warn("WS serve error", zap.Error(err))
+	}
+}
+
+// ServeHTTP implements http.Handler interface, it upgrades incoming HTTP
+// connections to WebSocket and starts a new peer for each of them.
+func (t *WSTransport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := t.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		t.log.Warn("WS upgrade error", zap.Error(err))
+		return
+	}
+	wsc := newWSConn(conn)
+	if err := t.server.startPeer(wsc, ""); err != nil {
+		t.log.Warn("failed to start peer", zap.Stringer("addr", wsc.RemoteAddr()), zap.Error(err))
+	}
+}
+
+// Close implements the Transporter interface.
+func (t *WSTransport) Close() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.httpSrv != nil {
+		t.httpSrv.Close()
+	}
+}
+
+// Proto implements the Transporter interface.
+func (t *WSTransport) Proto() string {
+	return "ws"
+}
+
+func newWSConn(conn *websocket.Conn) *wsConn {
+	return &wsConn{conn: conn}
+}
+
+// Read implements net.Conn interface.
+func (c *wsConn) Read(b []byte) (int, error) {
+	for {
+		if c.reader == nil {
+			typ, r, err := c.conn.NextReader()
+			if err != nil {
+				return 0, err
+			}
+			if typ != websocket.BinaryMessage {
+				continue
+			}
+			c.reader = r
+		}
+		n, err := c.reader.Read(b)
+		if err == io.EOF {
+			c.reader = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+// Write implements net.Conn interface. It's safe for concurrent use.
+func (c *wsConn) Write(b []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if err := c.conn.WriteMessage(websocket.BinaryMessage, b); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Close implements net.Conn interface.
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
+
+// LocalAddr implements net.Conn interface.
+func (c *wsConn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+// RemoteAddr implements net.Conn interface.
+func (c *wsConn) RemoteAddr() net.Addr {
+	if tcpAddr, ok := c.conn.RemoteAddr().(*net.TCPAddr); ok {
+		return wsAddr{tcpAddr}
+	}
+	return c.conn.RemoteAddr()
+}
+
+// SetDeadline implements net.Conn interface.
+func (c *wsConn) SetDeadline(t time.Time) error {
+	if err := c.conn.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.conn.SetWriteDeadline(t)
+}
+
+// SetReadDeadline implements net.Conn interface.
+func (c *wsConn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline implements net.Conn interface.
+func (c *wsConn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
+
+// Network implements net.Addr interface.
+func (a wsAddr) Network() string {
+	return "ws"
+}
+
+// String implements net.Addr interface.
+func (a wsAddr) String() string {
+	return wsScheme + a.TCPAddr.String()
+}
+
+// newWSAddr creates a wsAddr from the host and port given.
+func newWSAddr(host string, port uint16) (net.Addr, error) {
+	tcpAddr, err := net.ResolveTCPAddr("tcp", net.JoinHostPort(host, strconv.Itoa(int(port))))
+	if err != nil {
+		return nil, err
+	}
+	return wsAddr{tcpAddr}, nil
+}
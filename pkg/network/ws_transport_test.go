@@ -0,0 +1,86 @@
+package network
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsWSAddr(t *testing.T) {
+	require.True(t, isWSAddr("ws://127.0.0.1:20333"))
+	require.False(t, isWSAddr("127.0.0.1:20333"))
+}
+
+func TestWSConn(t *testing.T) {
+	connCh := make(chan *websocket.Conn, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		require.NoError(t, err)
+		connCh <- conn
+	}))
+	defer srv.Close()
+
+	url := wsScheme + strings.TrimPrefix(srv.URL, "http://")
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	c := newWSConn(client)
+	s := newWSConn(<-connCh)
+	defer c.Close()
+	defer s.Close()
+
+	require.True(t, strings.HasPrefix(c.RemoteAddr().String(), wsScheme))
+	require.Equal(t, "ws", c.RemoteAddr().Network())
+
+	// Data written in several messages is read as a stream.
+	_, err = c.Write([]byte{1, 2, 3})
+	require.NoError(t, err)
+	_, err = c.Write([]byte{4, 5})
+	require.NoError(t, err)
+
+	buf := make([]byte, 5)
+	n, err := s.Read(buf[:2])
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	for n < len(buf) {
+		m, err := s.Read(buf[n:])
+		require.NoError(t, err)
+		n += m
+	}
+	require.Equal(t, []byte{1, 2, 3, 4, 5}, buf)
+}
+
+func TestWSTransportDialTCP(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	s := newTestServer(t)
+	go func() {
+		for range s.register {
+		}
+	}()
+	defer close(s.register)
+	tr := NewWSTransport(s, "", s.log)
+	go func() { _ = tr.Dial(l.Addr().String(), time.Second) }()
+
+	conn, err := l.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Plain P2P peer starts with a version message, WebSocket dialer would
+	// send HTTP upgrade request instead.
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	msg := &Message{}
+	require.NoError(t, msg.Decode(io.NewBinReaderFromIO(conn)))
+	require.Equal(t, CMDVersion, msg.CommandType())
+	require.IsType(t, (*payload.Version)(nil), msg.Payload)
+	require.Equal(t, s.id, msg.Payload.(*payload.Version).Nonce)
+}
@@ -42,6 +42,7 @@ type Client struct {
 	wif        *keys.WIF
 	balancerMu *sync.Mutex
 	balancer   request.BalanceGetter
+	selector   CoinSelector
 	cache      cache
 }
 
@@ -58,6 +59,9 @@ type Options struct {
 	// along with the request body. If no version is specified
 	// the default version (currently 2.0) will be used.
 	Version string
+	// CoinSelector is a strategy used to choose UTXOs in CalculateInputs,
+	// SmallestFirst is used if it's not specified.
+	CoinSelector CoinSelector
 }
 
 // cache stores cache values for the RPC client methods
@@ -109,6 +113,7 @@ func New(ctx context.Context, endpoint string, opts Options) (*Client, error) {
 		wifMu:      new(sync.Mutex),
 		endpoint:   url,
 		version:    opts.Version,
+		selector:   opts.CoinSelector,
 	}, nil
 }
 
@@ -173,7 +178,8 @@ func (c *Client) SetClient(cli *http.Client) {
 
 // CalculateInputs creates input transactions for the specified amount of given
 // asset belonging to specified address. This implementation uses GetUnspents
-// JSON-RPC call internally, so make sure your RPC server supports that.
+// JSON-RPC call internally, so make sure your RPC server supports that. UTXOs
+// are chosen using the CoinSelector specified in client Options.
 func (c *Client) CalculateInputs(address string, asset util.Uint256, cost util.Fixed8) ([]transaction.Input, util.Fixed8, error) {
 	var utxos state.UnspentBalances
 
@@ -187,7 +193,7 @@ func (c *Client) CalculateInputs(address string, asset util.Uint256, cost util.F
 			break
 		}
 	}
-	return unspentsToInputs(utxos, cost)
+	return unspentsToInputs(utxos, cost, c.selector)
 
 }
 
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// CoinSelector chooses unspent outputs to spend from the given set to get at
+// least the required amount. It returns selected outputs and their total
+// value, the set passed must not be modified.
+type CoinSelector func(utxos state.UnspentBalances, required util.Fixed8) (state.UnspentBalances, util.Fixed8, error)
+
+// maxBranchAndBoundTries limits the number of search steps BranchAndBound
+// makes before falling back to LargestFirst.
+const maxBranchAndBoundTries = 100000
+
+var errInsufficientFunds = errors.New("cannot compose inputs for transaction; check sender balance")
+
+// SmallestFirst selects outputs starting from the smallest ones, it's the
+// default strategy which reduces the number of small outputs (but leads to
+// bigger transactions).
+func SmallestFirst(utxos state.UnspentBalances, required util.Fixed8) (state.UnspentBalances, util.Fixed8, error) {
+	sorted := sortedUnspents(utxos, false)
+	return selectInOrder(sorted, required)
+}
+
+// LargestFirst selects outputs starting from the biggest ones, it produces
+// transactions with the minimum number of inputs.
+func LargestFirst(utxos state.UnspentBalances, required util.Fixed8) (state.UnspentBalances, util.Fixed8, error) {
+	sorted := sortedUnspents(utxos, true)
+	return selectInOrder(sorted, required)
+}
+
+// BranchAndBound searches for a set of outputs with the total value exactly
+// matching the required amount (so that there is no change output) and falls
+// back to LargestFirst if there is no such set (or it's not found in a
+// reasonable number of steps).
+func BranchAndBound(utxos state.UnspentBalances, required util.Fixed8) (state.UnspentBalances, util.Fixed8, error) {
+	var (
+		sorted = sortedUnspents(utxos, true)
+		// rest[i] is the sum of all values starting from i.
+		rest     = make([]util.Fixed8, len(sorted)+1)
+		selected = make([]bool, len(sorted))
+		tries    int
+		search   func(i int, sum util.Fixed8) bool
+	)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		rest[i] = rest[i+1] + sorted[i].Value
+	}
+	search = func(i int, sum util.Fixed8) bool {
+		tries++
+		switch {
+		case sum == required:
+			return true
+		case i == len(sorted) || sum > required || sum+rest[i] < required || tries > maxBranchAndBoundTries:
+			return false
+		}
+		selected[i] = true
+		if search(i+1, sum+sorted[i].Value) {
+			return true
+		}
+		selected[i] = false
+		return search(i+1, sum)
+	}
+	if required <= 0 || !search(0, 0) {
+		return LargestFirst(utxos, required)
+	}
+	var res state.UnspentBalances
+	for i := range sorted {
+		if selected[i] {
+			res = append(res, sorted[i])
+		}
+	}
+	return res, required, nil
+}
+
+// GetCoinSelector returns the coin selection strategy by its name:
+// "smallest-first", "largest-first" or "exact" (BranchAndBound). Empty name
+// means the default one.
+func GetCoinSelector(name string) (CoinSelector, error) {
+	switch name {
+	case "", "smallest-first":
+		return SmallestFirst, nil
+	case "largest-first":
+		return LargestFirst, nil
+	case "exact":
+		return BranchAndBound, nil
+	default:
+		return nil, fmt.Errorf("unknown coin selection strategy: %s", name)
+	}
+}
+
+func sortedUnspents(utxos state.UnspentBalances, desc bool) state.UnspentBalances {
+	sorted := make(state.UnspentBalances, len(utxos))
+	copy(sorted, utxos)
+	if desc {
+		sort.Stable(sort.Reverse(sorted))
+	} else {
+		sort.Stable(sorted)
+	}
+	return sorted
+}
+
+func selectInOrder(utxos state.UnspentBalances, required util.Fixed8) (state.UnspentBalances, util.Fixed8, error) {
+	var (
+		num      int
+		selected util.Fixed8
+	)
+	for _, us := range utxos {
+		if selected >= required {
+			break
+		}
+		selected += us.Value
+		num++
+	}
+	if selected < required {
+		return nil, 0, errInsufficientFunds
+	}
+	return utxos[:num], selected, nil
+}
+
+// CreateConsolidationTx creates an unsigned contract transaction merging
+// outputs of the given asset belonging to the address into a single output
+// to the same address. Only outputs with value less than threshold are used
+// (all of them if it's 0), starting from the smallest ones and up to maxInputs
+// (no limit if it's 0).
+func (c *Client) CreateConsolidationTx(addr string, asset util.Uint256, threshold util.Fixed8, maxInputs int) (*transaction.Transaction, error) {
+	scriptHash, err := address.StringToUint160(addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "bad address %s", addr)
+	}
+	resp, err := c.GetUnspents(addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get balance for address %v", addr)
+	}
+	var utxos state.UnspentBalances
+	for _, ubi := range resp.Balance {
+		if !asset.Equals(ubi.AssetHash) {
+			continue
+		}
+		for _, us := range ubi.Unspents {
+			if threshold == 0 || us.Value < threshold {
+				utxos = append(utxos, us)
+			}
+		}
+		break
+	}
+	utxos = sortedUnspents(utxos, false)
+	if maxInputs > 0 && len(utxos) > maxInputs {
+		utxos = utxos[:maxInputs]
+	}
+	if len(utxos) < 2 {
+		return nil, errors.New("nothing to consolidate")
+	}
+
+	tx := transaction.NewContractTX()
+	var sum util.Fixed8
+	for _, us := range utxos {
+		tx.AddInput(&transaction.Input{
+			PrevHash:  us.Tx,
+			PrevIndex: us.Index,
+		})
+		sum += us.Value
+	}
+	tx.AddOutput(transaction.NewOutput(asset, sum, scriptHash))
+	tx.Sender = scriptHash
+	tx.ValidUntilBlock, err = c.CalculateValidUntilBlock()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to add validUntilBlock to transaction")
+	}
+	return tx, nil
+}
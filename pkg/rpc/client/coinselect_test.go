@@ -0,0 +1,79 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func testUnspents(values ...int64) state.UnspentBalances {
+	res := make(state.UnspentBalances, len(values))
+	for i, v := range values {
+		res[i] = state.UnspentBalance{
+			Tx:    util.Uint256{byte(i)},
+			Index: uint16(i),
+			Value: util.Fixed8FromInt64(v),
+		}
+	}
+	return res
+}
+
+func sumUnspents(us state.UnspentBalances) util.Fixed8 {
+	var sum util.Fixed8
+	for i := range us {
+		sum += us[i].Value
+	}
+	return sum
+}
+
+func TestCoinSelectors(t *testing.T) {
+	utxos := testUnspents(5, 1, 10, 3, 7)
+	orig := append(state.UnspentBalances{}, utxos...)
+
+	t.Run("SmallestFirst", func(t *testing.T) {
+		res, sum, err := SmallestFirst(utxos, util.Fixed8FromInt64(6))
+		require.NoError(t, err)
+		require.Equal(t, 3, len(res))
+		require.Equal(t, util.Fixed8FromInt64(1), res[0].Value)
+		require.Equal(t, util.Fixed8FromInt64(9), sum)
+		require.Equal(t, sum, sumUnspents(res))
+	})
+	t.Run("LargestFirst", func(t *testing.T) {
+		res, sum, err := LargestFirst(utxos, util.Fixed8FromInt64(12))
+		require.NoError(t, err)
+		require.Equal(t, 2, len(res))
+		require.Equal(t, util.Fixed8FromInt64(17), sum)
+		require.Equal(t, sum, sumUnspents(res))
+	})
+	t.Run("BranchAndBound", func(t *testing.T) {
+		res, sum, err := BranchAndBound(utxos, util.Fixed8FromInt64(9))
+		require.NoError(t, err)
+		require.Equal(t, util.Fixed8FromInt64(9), sum)
+		require.Equal(t, sum, sumUnspents(res))
+
+		// No exact match possible, falls back to LargestFirst.
+		res, sum, err = BranchAndBound(utxos, util.Fixed8FromInt64(25)+1)
+		require.NoError(t, err)
+		require.Equal(t, 5, len(res))
+		require.Equal(t, util.Fixed8FromInt64(26), sum)
+	})
+	t.Run("insufficient funds", func(t *testing.T) {
+		for _, s := range []CoinSelector{SmallestFirst, LargestFirst, BranchAndBound} {
+			_, _, err := s(utxos, util.Fixed8FromInt64(27))
+			require.Error(t, err)
+		}
+	})
+	require.Equal(t, orig, utxos)
+}
+
+func TestGetCoinSelector(t *testing.T) {
+	for _, name := range []string{"", "smallest-first", "largest-first", "exact"} {
+		s, err := GetCoinSelector(name)
+		require.NoError(t, err)
+		require.NotNil(t, s)
+	}
+	_, err := GetCoinSelector("random")
+	require.Error(t, err)
+}
@@ -29,6 +29,7 @@ Supported methods
 	getblocksysfee
 	getclaimable
 	getconnectioncount
+	getconsensusstate
 	getcontractstate
 	getnep5balances
 	getnep5transfers
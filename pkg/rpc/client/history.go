@@ -0,0 +1,248 @@
+package client
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/pkg/errors"
+)
+
+// historyTxInfo is the transaction data needed for history entries.
+type historyTxInfo struct {
+	height    uint32
+	timestamp uint32
+	claim     bool
+}
+
+// outputKey identifies transaction output.
+type outputKey struct {
+	tx    util.Uint256
+	index uint16
+}
+
+// spentOutput is an account output found to be spent.
+type spentOutput struct {
+	out   wallet.HistoryOutput
+	block uint32
+	exact bool
+}
+
+// UpdateHistory updates the account history with NEP5 transfers (from
+// getnep5transfers), unspent outputs (from getunspents) and spent NEO outputs
+// (from getclaimable). Outputs that were unspent at the previous update and
+// are missing now are recorded as spent. For NEO outputs that are not claimed
+// yet getclaimable provides the exact spending height, so the spending
+// transaction is found in that block. For other outputs the spending height
+// and transaction are not known, they're recorded as approximate entries at
+// the current height, so the more often the history is updated the more
+// precise it is. Outputs (other than unclaimed NEO ones) spent before the
+// first update are not known at all. Transactions are only requested for new
+// outputs, only NEP5 transfers newer than the ones already known are
+// requested and getclaimable is only used for the first update and when
+// some NEO output is spent, so repeated updates are cheap.
+func (c *Client) UpdateHistory(ah *wallet.AccountHistory, addr string) error {
+	count, err := c.GetBlockCount()
+	if err != nil {
+		return errors.Wrap(err, "can't get block count")
+	}
+	height := count - 1
+	first := ah.Height == 0
+
+	transfers, err := c.GetNEP5TransfersFrom(addr, lastNEP5Timestamp(ah))
+	if err != nil {
+		return errors.Wrap(err, "can't get NEP5 transfers")
+	}
+	for _, tr := range transfers.Received {
+		ah.AddEntry(nep5HistoryEntry(wallet.HistoryNEP5Received, &tr))
+	}
+	for _, tr := range transfers.Sent {
+		ah.AddEntry(nep5HistoryEntry(wallet.HistoryNEP5Sent, &tr))
+	}
+
+	unspents, err := c.GetUnspents(addr)
+	if err != nil {
+		return errors.Wrap(err, "can't get unspent outputs")
+	}
+	known := make(map[outputKey]*wallet.HistoryOutput, len(ah.Unspents))
+	for i := range ah.Unspents {
+		known[outputKey{ah.Unspents[i].Tx, ah.Unspents[i].Index}] = &ah.Unspents[i]
+	}
+	var (
+		outs    []wallet.HistoryOutput
+		current = make(map[outputKey]bool)
+		txs     = make(map[util.Uint256]*historyTxInfo)
+	)
+	for _, ubi := range unspents.Balance {
+		for _, us := range ubi.Unspents {
+			k := outputKey{us.Tx, us.Index}
+			current[k] = true
+			if out, ok := known[k]; ok {
+				outs = append(outs, *out)
+				continue
+			}
+			info, ok := txs[us.Tx]
+			if !ok {
+				info, err = c.getHistoryTxInfo(us.Tx)
+				if err != nil {
+					return err
+				}
+				txs[us.Tx] = info
+			}
+			typ := wallet.HistoryReceived
+			if info.claim {
+				typ = wallet.HistoryClaim
+			}
+			ah.AddEntry(wallet.HistoryEntry{
+				Type:      typ,
+				Block:     info.height,
+				Timestamp: info.timestamp,
+				Tx:        us.Tx,
+				Index:     uint32(us.Index),
+				Asset:     ubi.AssetHash.StringLE(),
+				Amount:    us.Value.String(),
+			})
+			outs = append(outs, wallet.HistoryOutput{
+				Asset: ubi.AssetHash,
+				Tx:    us.Tx,
+				Index: us.Index,
+				Value: us.Value,
+				Block: info.height,
+			})
+		}
+	}
+
+	neo := core.GoverningTokenID()
+	needClaimable := first
+	var spent []spentOutput
+	for _, out := range ah.Unspents {
+		if !current[outputKey{out.Tx, out.Index}] {
+			spent = append(spent, spentOutput{out: out, block: height})
+			needClaimable = needClaimable || out.Asset.Equals(neo)
+		}
+	}
+	if needClaimable {
+		claimable, err := c.GetClaimable(addr)
+		if err != nil {
+			return errors.Wrap(err, "can't get claimable outputs")
+		}
+		spentHeights := make(map[outputKey]uint32, len(claimable.Spents))
+		for _, cl := range claimable.Spents {
+			spentHeights[outputKey{cl.Tx, uint16(cl.N)}] = cl.EndHeight
+			if !first {
+				continue
+			}
+			// Outputs spent before the first update.
+			out := wallet.HistoryOutput{
+				Asset: neo,
+				Tx:    cl.Tx,
+				Index: uint16(cl.N),
+				Value: cl.Value,
+				Block: cl.StartHeight,
+			}
+			ah.AddEntry(wallet.HistoryEntry{
+				Type:   wallet.HistoryReceived,
+				Block:  out.Block,
+				Tx:     out.Tx,
+				Index:  uint32(out.Index),
+				Asset:  neo.StringLE(),
+				Amount: out.Value.String(),
+			})
+			spent = append(spent, spentOutput{out: out})
+		}
+		for i := range spent {
+			h, ok := spentHeights[outputKey{spent[i].out.Tx, spent[i].out.Index}]
+			if ok {
+				spent[i].block = h
+				spent[i].exact = true
+			}
+		}
+	}
+
+	blocks := make(map[uint32]*result.Block)
+	for _, sp := range spent {
+		e := wallet.HistoryEntry{
+			Type:        wallet.HistorySpent,
+			Block:       sp.block,
+			Tx:          sp.out.Tx,
+			Index:       uint32(sp.out.Index),
+			Asset:       sp.out.Asset.StringLE(),
+			Amount:      sp.out.Value.String(),
+			Approximate: !sp.exact,
+		}
+		if sp.exact {
+			b, ok := blocks[sp.block]
+			if !ok {
+				b, err = c.GetBlockByIndexVerbose(sp.block)
+				if err != nil {
+					return errors.Wrapf(err, "can't get block %d", sp.block)
+				}
+				blocks[sp.block] = b
+			}
+			e.Timestamp = b.Time
+			e.SpentBy = findSpendingTx(b, sp.out.Tx, sp.out.Index)
+		}
+		ah.AddEntry(e)
+	}
+	ah.Unspents = outs
+	ah.Height = height
+	ah.Sort()
+	return nil
+}
+
+// lastNEP5Timestamp returns the timestamp of the latest NEP5 transfer known
+// to the history, transfers made since then are to be requested.
+func lastNEP5Timestamp(ah *wallet.AccountHistory) uint32 {
+	var ts uint32
+	for i := range ah.Entries {
+		e := &ah.Entries[i]
+		if (e.Type == wallet.HistoryNEP5Received || e.Type == wallet.HistoryNEP5Sent) && e.Timestamp > ts {
+			ts = e.Timestamp
+		}
+	}
+	return ts
+}
+
+// findSpendingTx returns the hash of the block transaction spending the given
+// output or nil if there is no such transaction.
+func findSpendingTx(b *result.Block, h util.Uint256, index uint16) *util.Uint256 {
+	for _, tx := range b.Tx {
+		for _, in := range tx.Inputs {
+			if in.PrevHash.Equals(h) && in.PrevIndex == index {
+				txHash := tx.Hash()
+				return &txHash
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Client) getHistoryTxInfo(h util.Uint256) (*historyTxInfo, error) {
+	tx, err := c.GetRawTransactionVerbose(h)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't get transaction %s", h.StringLE())
+	}
+	height, err := c.GetTransactionHeight(h)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't get transaction %s height", h.StringLE())
+	}
+	return &historyTxInfo{
+		height:    height,
+		timestamp: tx.Timestamp,
+		claim:     tx.Type == transaction.ClaimType,
+	}, nil
+}
+
+func nep5HistoryEntry(typ wallet.HistoryEntryType, tr *result.NEP5Transfer) wallet.HistoryEntry {
+	return wallet.HistoryEntry{
+		Type:      typ,
+		Block:     tr.Index,
+		Timestamp: tr.Timestamp,
+		Tx:        tr.TxHash,
+		Index:     tr.NotifyIndex,
+		Asset:     tr.Asset.StringLE(),
+		Amount:    tr.Amount,
+		Address:   tr.Address,
+	}
+}
@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/request"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateHistory(t *testing.T) {
+	var (
+		round      int
+		txCalls    int
+		claimCalls int
+		nep5Start  []interface{}
+		neo        = core.GoverningTokenID().StringLE()
+		gas        = core.UtilityTokenID().StringLE()
+		txs        = make([]*transaction.Transaction, 4)
+		heights    = []uint32{50, 60, 106, 70}
+	)
+	for i := range txs {
+		if i == 1 {
+			txs[i] = transaction.NewClaimTX(&transaction.ClaimTX{})
+		} else {
+			txs[i] = transaction.NewContractTX()
+		}
+		txs[i].Nonce = uint32(i)
+	}
+	spender := transaction.NewContractTX()
+	spender.Inputs = []transaction.Input{{PrevHash: txs[0].Hash(), PrevIndex: 0}}
+	unspent := func(asset string, amount string, outs ...int) string {
+		var us []string
+		for _, i := range outs {
+			us = append(us, fmt.Sprintf(`{"txid":"0x%s","n":0,"value":"%s"}`, txs[i].Hash().StringLE(), amount))
+		}
+		return fmt.Sprintf(`{"unspent":[%s],"asset_hash":"0x%s","asset":"","asset_symbol":"","amount":"0"}`, strings.Join(us, ","), asset)
+	}
+	txIndex := func(params json.RawMessage) int {
+		var ps []interface{}
+		require.NoError(t, json.Unmarshal(params, &ps))
+		h, err := util.Uint256DecodeStringLE(ps[0].(string))
+		require.NoError(t, err)
+		for i := range txs {
+			if txs[i].Hash().Equals(h) {
+				return i
+			}
+		}
+		t.Fatalf("unknown transaction %s", h.StringLE())
+		return 0
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r := request.NewIn()
+		require.NoError(t, r.DecodeData(req.Body))
+		var res string
+		switch r.Method {
+		case "getblockcount":
+			res = fmt.Sprint(100 + round*10 + 1)
+		case "getnep5transfers":
+			var ps []interface{}
+			require.NoError(t, json.Unmarshal(r.RawParams, &ps))
+			if len(ps) > 1 {
+				nep5Start = append(nep5Start, ps[1])
+			}
+			res = `{"sent":[],"received":[{"timestamp":1555651816,"asset_hash":"600c4f5200db36177e3e8a09e9f18e2fc7d12a0f","transfer_address":"AYwgBNMepiv5ocGcyNT4mA8zPLTQ8pDBis","amount":"1000000","block_index":80,"transfer_notify_index":0,"tx_hash":"df7683ece554ecfb85cf41492c5f143215dd43ef9ec61181a28f922da06aba58"}],"address":"AbHgdBaWEnHkCiLtDZXjhvhaAK2cwFh5pF"}`
+		case "getclaimable":
+			claimCalls++
+			res = `{"claimable":[],"address":"AbHgdBaWEnHkCiLtDZXjhvhaAK2cwFh5pF","unclaimed":"0"}`
+			if round == 1 {
+				res = fmt.Sprintf(`{"claimable":[{"txid":"0x%s","n":0,"value":"10","start_height":50,"end_height":105,"generated":"1","sys_fee":"0","unclaimed":"1"}],"address":"AbHgdBaWEnHkCiLtDZXjhvhaAK2cwFh5pF","unclaimed":"1"}`, txs[0].Hash().StringLE())
+			}
+		case "getunspents":
+			if round == 0 {
+				res = fmt.Sprintf(`{"balance":[%s,%s],"address":"AbHgdBaWEnHkCiLtDZXjhvhaAK2cwFh5pF"}`, unspent(neo, "10", 0), unspent(gas, "1", 1, 3))
+			} else {
+				res = fmt.Sprintf(`{"balance":[%s,%s],"address":"AbHgdBaWEnHkCiLtDZXjhvhaAK2cwFh5pF"}`, unspent(neo, "10", 2), unspent(gas, "1", 1))
+			}
+		case "getrawtransaction":
+			txCalls++
+			i := txIndex(r.RawParams)
+			b, err := json.Marshal(result.TransactionOutputRaw{
+				Transaction:         txs[i],
+				TransactionMetadata: result.TransactionMetadata{Timestamp: 1000 + heights[i]},
+			})
+			require.NoError(t, err)
+			res = string(b)
+		case "gettransactionheight":
+			res = fmt.Sprint(heights[txIndex(r.RawParams)])
+		case "getblock":
+			b, err := json.Marshal(result.Block{
+				Index: 105,
+				Time:  1105,
+				Tx:    []result.Tx{{Transaction: transaction.NewMinerTX()}, {Transaction: spender}},
+			})
+			require.NoError(t, err)
+			res = string(b)
+		default:
+			t.Fatalf("Bad request method: %s", r.Method)
+		}
+		requestHandler(t, w, `{"jsonrpc":"2.0","id":1,"result":`+res+`}`)
+	}))
+	defer srv.Close()
+
+	c, err := New(context.TODO(), srv.URL, Options{})
+	require.NoError(t, err)
+
+	ah := new(wallet.AccountHistory)
+	require.NoError(t, c.UpdateHistory(ah, "AbHgdBaWEnHkCiLtDZXjhvhaAK2cwFh5pF"))
+	require.Equal(t, 3, txCalls)
+	require.Equal(t, 1, claimCalls)
+	require.Equal(t, uint32(100), ah.Height)
+	require.Equal(t, 3, len(ah.Unspents))
+
+	var types []wallet.HistoryEntryType
+	for _, e := range ah.Entries {
+		types = append(types, e.Type)
+	}
+	require.Equal(t, []wallet.HistoryEntryType{wallet.HistoryReceived, wallet.HistoryClaim,
+		wallet.HistoryReceived, wallet.HistoryNEP5Received}, types)
+	require.Equal(t, uint32(1050), ah.Entries[0].Timestamp)
+
+	round++
+	require.NoError(t, c.UpdateHistory(ah, "AbHgdBaWEnHkCiLtDZXjhvhaAK2cwFh5pF"))
+	require.Equal(t, 4, txCalls)
+	require.Equal(t, 2, claimCalls) // NEO output was spent
+	require.Equal(t, uint32(110), ah.Height)
+	require.Equal(t, 2, len(ah.Unspents))
+
+	type entry struct {
+		typ    wallet.HistoryEntryType
+		block  uint32
+		approx bool
+	}
+	var entries []entry
+	for _, e := range ah.Entries {
+		entries = append(entries, entry{e.Type, e.Block, e.Approximate})
+	}
+	require.Equal(t, []entry{
+		{wallet.HistoryReceived, 50, false},
+		{wallet.HistoryClaim, 60, false},
+		{wallet.HistoryReceived, 70, false},
+		{wallet.HistoryNEP5Received, 80, false},
+		{wallet.HistorySpent, 105, false}, // from getclaimable
+		{wallet.HistoryReceived, 106, false},
+		{wallet.HistorySpent, 110, true}, // disappeared from getunspents
+	}, entries)
+	neoSpent := ah.Entries[4]
+	require.Equal(t, txs[0].Hash(), neoSpent.Tx)
+	require.NotNil(t, neoSpent.SpentBy)
+	require.Equal(t, spender.Hash(), *neoSpent.SpentBy)
+	require.Equal(t, uint32(1105), neoSpent.Timestamp)
+	gasSpent := ah.Entries[6]
+	require.Equal(t, txs[3].Hash(), gasSpent.Tx)
+	require.Nil(t, gasSpent.SpentBy)
+
+	// Nothing has changed, so getclaimable is not needed and only new NEP5
+	// transfers are requested.
+	round++
+	require.NoError(t, c.UpdateHistory(ah, "AbHgdBaWEnHkCiLtDZXjhvhaAK2cwFh5pF"))
+	require.Equal(t, 4, txCalls)
+	require.Equal(t, 2, claimCalls)
+	require.Equal(t, 7, len(ah.Entries))
+	require.Equal(t, []interface{}{float64(0), float64(1555651816), float64(1555651816)}, nep5Start)
+}
@@ -2,9 +2,7 @@ package client
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
-	"sort"
 
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
@@ -24,6 +22,8 @@ type (
 	NeoScanServer struct {
 		URL  string // "protocol://host:port/"
 		Path string // path to API endpoint without wallet address
+		// Selector is a coin selection strategy (SmallestFirst if nil).
+		Selector CoinSelector
 	}
 
 	// Unspent stores Unspents per asset
@@ -90,36 +90,28 @@ func (s NeoScanServer) CalculateInputs(address string, assetIDUint util.Uint256,
 		return nil, util.Fixed8(0), errs.Wrapf(err, "Cannot get balance for address %v", address)
 	}
 	filterSpecificAsset(assetID, us, &assetUnspent)
-	return unspentsToInputs(assetUnspent.Unspent, cost)
+	return unspentsToInputs(assetUnspent.Unspent, cost, s.Selector)
 }
 
 // unspentsToInputs uses UnspentBalances to create a slice of inputs for a new
-// transcation containing the required amount of asset.
-func unspentsToInputs(utxos state.UnspentBalances, required util.Fixed8) ([]transaction.Input, util.Fixed8, error) {
-	var (
-		num, i   uint16
-		selected = util.Fixed8(0)
-	)
-	sort.Sort(utxos)
-
-	for _, us := range utxos {
-		if selected >= required {
-			break
-		}
-		selected += us.Value
-		num++
+// transcation containing the required amount of asset. Outputs are chosen by
+// the given CoinSelector (SmallestFirst if nil).
+func unspentsToInputs(utxos state.UnspentBalances, required util.Fixed8, selector CoinSelector) ([]transaction.Input, util.Fixed8, error) {
+	if selector == nil {
+		selector = SmallestFirst
 	}
-	if selected < required {
-		return nil, util.Fixed8(0), errors.New("cannot compose inputs for transaction; check sender balance")
+	selected, total, err := selector(utxos, required)
+	if err != nil {
+		return nil, util.Fixed8(0), err
 	}
 
-	inputs := make([]transaction.Input, 0, num)
-	for i = 0; i < num; i++ {
+	inputs := make([]transaction.Input, 0, len(selected))
+	for _, us := range selected {
 		inputs = append(inputs, transaction.Input{
-			PrevHash:  utxos[i].Tx,
-			PrevIndex: utxos[i].Index,
+			PrevHash:  us.Tx,
+			PrevIndex: us.Index,
 		})
 	}
 
-	return inputs, selected, nil
+	return inputs, total, nil
 }
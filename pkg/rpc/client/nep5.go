@@ -93,13 +93,14 @@ func (c *Client) NEP5TokenInfo(tokenHash util.Uint160) (*wallet.Token, error) {
 	return wallet.NewToken(tokenHash, name, symbol, decimals), nil
 }
 
-// TransferNEP5 creates an invocation transaction that invokes 'transfer' method
-// on a given token to move specified amount of NEP5 assets (in FixedN format
-// using contract's number of decimals) to given account.
-func (c *Client) TransferNEP5(acc *wallet.Account, to util.Uint160, token *wallet.Token, amount int64, gas util.Fixed8) (util.Uint256, error) {
+// CreateNEP5TransferTx creates an invocation transaction that invokes
+// 'transfer' method on a given token to move specified amount of NEP5 assets
+// (in FixedN format using contract's number of decimals) from the given
+// account to another one. The transaction is not signed.
+func (c *Client) CreateNEP5TransferTx(acc *wallet.Account, to util.Uint160, token *wallet.Token, amount int64, gas util.Fixed8) (*transaction.Transaction, error) {
 	from, err := address.StringToUint160(acc.Address)
 	if err != nil {
-		return util.Uint256{}, fmt.Errorf("bad account address: %v", err)
+		return nil, fmt.Errorf("bad account address: %v", err)
 	}
 	// Note: we don't use invoke function here because it requires
 	// 2 round trips instead of one.
@@ -115,17 +116,24 @@ func (c *Client) TransferNEP5(acc *wallet.Account, to util.Uint160, token *walle
 
 	tx.ValidUntilBlock, err = c.CalculateValidUntilBlock()
 	if err != nil {
-		return util.Uint256{}, fmt.Errorf("can't calculate validUntilBlock: %v", err)
+		return nil, fmt.Errorf("can't calculate validUntilBlock: %v", err)
 	}
+	tx.Sender = from
 
-	addr, err := address.StringToUint160(acc.Address)
-	if err != nil {
-		return util.Uint256{}, fmt.Errorf("failed to get address: %v", err)
+	if err := request.AddInputsAndUnspentsToTx(tx, acc.Address, core.UtilityTokenID(), gas, c); err != nil {
+		return nil, fmt.Errorf("can't add GAS to transaction: %v", err)
 	}
-	tx.Sender = addr
+	return tx, nil
+}
 
-	if err := request.AddInputsAndUnspentsToTx(tx, acc.Address, core.UtilityTokenID(), gas, c); err != nil {
-		return util.Uint256{}, fmt.Errorf("can't add GAS to transaction: %v", err)
+// TransferNEP5 creates an invocation transaction that invokes 'transfer' method
+// on a given token to move specified amount of NEP5 assets (in FixedN format
+// using contract's number of decimals) to given account, signs it with the
+// account key and sends it.
+func (c *Client) TransferNEP5(acc *wallet.Account, to util.Uint160, token *wallet.Token, amount int64, gas util.Fixed8) (util.Uint256, error) {
+	tx, err := c.CreateNEP5TransferTx(acc, to, token, amount, gas)
+	if err != nil {
+		return util.Uint256{}, err
 	}
 
 	if err := acc.SignTx(tx); err != nil {
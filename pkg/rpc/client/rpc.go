@@ -209,6 +209,18 @@ func (c *Client) GetConnectionCount() (int, error) {
 	return resp, nil
 }
 
+// GetConsensusState returns the current state of node's consensus process.
+func (c *Client) GetConsensusState() (*result.ConsensusState, error) {
+	var (
+		params = request.NewRawParams()
+		resp   = &result.ConsensusState{}
+	)
+	if err := c.performRequest("getconsensusstate", params, resp); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
 // GetContractState queries contract information, according to the contract script hash.
 func (c *Client) GetContractState(hash util.Uint160) (*result.ContractState, error) {
 	var (
@@ -233,7 +245,16 @@ func (c *Client) GetNEP5Balances(address util.Uint160) (*result.NEP5Balances, er
 
 // GetNEP5Transfers is a wrapper for getnep5transfers RPC.
 func (c *Client) GetNEP5Transfers(address string) (*result.NEP5Transfers, error) {
-	params := request.NewRawParams(address)
+	return c.getNEP5Transfers(request.NewRawParams(address))
+}
+
+// GetNEP5TransfersFrom is a wrapper for getnep5transfers RPC returning only
+// transfers made in blocks with timestamp not less than the given one.
+func (c *Client) GetNEP5TransfersFrom(address string, start uint32) (*result.NEP5Transfers, error) {
+	return c.getNEP5Transfers(request.NewRawParams(address, start))
+}
+
+func (c *Client) getNEP5Transfers(params request.RawParams) (*result.NEP5Transfers, error) {
 	resp := new(result.NEP5Transfers)
 	if err := c.performRequest("getnep5transfers", params, resp); err != nil {
 		return nil, err
@@ -253,6 +274,19 @@ func (c *Client) GetPeers() (*result.GetPeers, error) {
 	return resp, nil
 }
 
+// GetPeersVerbose returns the list of nodes that the node is currently
+// connected/disconnected from with detailed information about connected ones.
+func (c *Client) GetPeersVerbose() (*result.GetPeersVerbose, error) {
+	var (
+		params = request.NewRawParams(1)
+		resp   = &result.GetPeersVerbose{}
+	)
+	if err := c.performRequest("getpeers", params, resp); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
 // GetRawMemPool returns the list of unconfirmed transactions in memory.
 func (c *Client) GetRawMemPool() ([]util.Uint256, error) {
 	var (
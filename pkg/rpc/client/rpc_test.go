@@ -496,6 +496,28 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"getconsensusstate": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (interface{}, error) {
+				return c.GetConsensusState()
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":{"height":12,"view":1,"primary":2,"validators":[{"publickey":"02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2","preparation":true,"commit":false,"changeview":false}],"events":[{"time":1588000000000,"type":"view_changed","view":1,"validator":-1,"details":"from view 0, reason: timeout"}]}}`,
+			result:         func(c *Client) interface{} { return &result.ConsensusState{} },
+			check: func(t *testing.T, c *Client, uns interface{}) {
+				res, ok := uns.(*result.ConsensusState)
+				require.True(t, ok)
+				assert.Equal(t, uint32(12), res.Height)
+				assert.Equal(t, byte(1), res.View)
+				assert.Equal(t, uint(2), res.Primary)
+				require.Equal(t, 1, len(res.Validators))
+				assert.True(t, res.Validators[0].Preparation)
+				require.Equal(t, 1, len(res.Events))
+				assert.Equal(t, "view_changed", res.Events[0].Type)
+				assert.Equal(t, -1, res.Events[0].Validator)
+			},
+		},
+	},
 	"getcontractstate": {
 		{
 			name: "positive",
@@ -627,6 +649,33 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 				}
 			},
 		},
+		{
+			name: "positive, verbose",
+			invoke: func(c *Client) (interface{}, error) {
+				return c.GetPeersVerbose()
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":{"unconnected":[],"connected":[{"address":"127.0.0.1","port":"20335","useragent":"/NEO-GO:0.74.0/","startheight":10,"lastblockindex":12,"bytesreceived":1024,"bytessent":2048,"connectionage":60}],"bad":[]}}`,
+			result: func(c *Client) interface{} {
+				return &result.GetPeersVerbose{
+					Unconnected: result.Peers{},
+					Connected: []result.PeerInfo{
+						{
+							Peer: result.Peer{
+								Address: "127.0.0.1",
+								Port:    "20335",
+							},
+							UserAgent:      "/NEO-GO:0.74.0/",
+							StartHeight:    10,
+							LastBlockIndex: 12,
+							BytesReceived:  1024,
+							BytesSent:      2048,
+							ConnectionAge:  60,
+						},
+					},
+					Bad: result.Peers{},
+				}
+			},
+		},
 	},
 	"getrawmempool": {
 		{
@@ -61,6 +61,15 @@ func AddInputsAndUnspentsToTx(tx *transaction.Transaction, addr string, assetID
 	if err != nil {
 		return errs.Wrapf(err, "failed to take script hash from address: %v", addr)
 	}
+	return AddInputsAndChangeToTx(tx, addr, assetID, amount, balancer, scriptHash)
+}
+
+// AddInputsAndChangeToTx adds inputs from the given address needed to
+// transaction and one output with change sent to the change script hash.
+func AddInputsAndChangeToTx(tx *transaction.Transaction, addr string, assetID util.Uint256, amount util.Fixed8, balancer BalanceGetter, change util.Uint160) error {
+	if change.Equals(util.Uint160{}) {
+		return errs.New("change script hash is not set")
+	}
 	inputs, spent, err := balancer.CalculateInputs(addr, assetID, amount)
 	if err != nil {
 		return errs.Wrap(err, "failed to get inputs")
@@ -70,7 +79,7 @@ func AddInputsAndUnspentsToTx(tx *transaction.Transaction, addr string, assetID
 	}
 
 	if senderUnspent := spent - amount; senderUnspent > 0 {
-		senderOutput := transaction.NewOutput(assetID, senderUnspent, scriptHash)
+		senderOutput := transaction.NewOutput(assetID, senderUnspent, change)
 		tx.AddOutput(senderOutput)
 	}
 	return nil
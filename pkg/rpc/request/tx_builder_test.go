@@ -4,6 +4,8 @@ import (
 	"encoding/hex"
 	"testing"
 
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/stretchr/testify/assert"
@@ -88,3 +90,43 @@ func TestInvocationScriptCreationBad(t *testing.T) {
 		assert.NotNil(t, err)
 	}
 }
+
+type testBalancer struct {
+	inputs []transaction.Input
+	spent  util.Fixed8
+}
+
+func (b testBalancer) CalculateInputs(string, util.Uint256, util.Fixed8) ([]transaction.Input, util.Fixed8, error) {
+	return b.inputs, b.spent, nil
+}
+
+func TestAddInputsAndChangeToTx(t *testing.T) {
+	var (
+		asset  = util.Uint256{1, 2, 3}
+		change = util.Uint160{4, 5, 6}
+		from   = util.Uint160{7, 8, 9}
+		b      = testBalancer{
+			inputs: []transaction.Input{{PrevHash: util.Uint256{1}}, {PrevHash: util.Uint256{2}}},
+			spent:  util.Fixed8FromInt64(10),
+		}
+	)
+
+	tx := transaction.NewContractTX()
+	require.NoError(t, AddInputsAndChangeToTx(tx, address.Uint160ToString(from), asset, util.Fixed8FromInt64(7), b, change))
+	require.Equal(t, b.inputs, tx.Inputs)
+	require.Equal(t, 1, len(tx.Outputs))
+	require.Equal(t, change, tx.Outputs[0].ScriptHash)
+	require.Equal(t, util.Fixed8FromInt64(3), tx.Outputs[0].Amount)
+
+	tx = transaction.NewContractTX()
+	require.NoError(t, AddInputsAndUnspentsToTx(tx, address.Uint160ToString(from), asset, util.Fixed8FromInt64(7), b))
+	require.Equal(t, 1, len(tx.Outputs))
+	require.Equal(t, from, tx.Outputs[0].ScriptHash)
+
+	tx = transaction.NewContractTX()
+	require.NoError(t, AddInputsAndChangeToTx(tx, address.Uint160ToString(from), asset, util.Fixed8FromInt64(10), b, change))
+	require.Equal(t, 0, len(tx.Outputs))
+
+	tx = transaction.NewContractTX()
+	require.Error(t, AddInputsAndChangeToTx(tx, address.Uint160ToString(from), asset, util.Fixed8FromInt64(7), b, util.Uint160{}))
+}
@@ -30,6 +30,9 @@ var (
 	ErrValidationFailed = NewSubmitError(-504, "Block or transaction validation failed.")
 	// ErrPolicyFail represents SubmitError with code -505
 	ErrPolicyFail = NewSubmitError(-505, "One of the Policy filters failed.")
+	// ErrPruned is returned when requested application log was removed by
+	// pruning.
+	ErrPruned = NewRPCError("Requested data is pruned", "", nil)
 	// ErrUnknown represents SubmitError with code -500
 	ErrUnknown = NewSubmitError(-500, "Unknown error.")
 )
@@ -0,0 +1,38 @@
+package result
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+)
+
+type (
+	// ConsensusState payload for outputting consensus process state in
+	// `getconsensusstate` RPC call.
+	ConsensusState struct {
+		Height     uint32               `json:"height"`
+		View       byte                 `json:"view"`
+		Primary    uint                 `json:"primary"`
+		Validators []ConsensusValidator `json:"validators"`
+		Events     []ConsensusEvent     `json:"events"`
+	}
+
+	// ConsensusValidator describes payloads received from the validator in
+	// the current consensus round.
+	ConsensusValidator struct {
+		PublicKey   keys.PublicKey `json:"publickey"`
+		Preparation bool           `json:"preparation"`
+		Commit      bool           `json:"commit"`
+		ChangeView  bool           `json:"changeview"`
+	}
+
+	// ConsensusEvent is a consensus round event.
+	ConsensusEvent struct {
+		// Time is the event time in milliseconds since the Unix epoch.
+		Time int64  `json:"time"`
+		Type string `json:"type"`
+		View byte   `json:"view"`
+		// Validator is the index of the validator that has sent the
+		// message, it's -1 for local events.
+		Validator int    `json:"validator"`
+		Details   string `json:"details,omitempty"`
+	}
+)
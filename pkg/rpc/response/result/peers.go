@@ -1,6 +1,7 @@
 package result
 
 import (
+	"net"
 	"strings"
 )
 
@@ -20,6 +21,28 @@ type (
 		Address string `json:"address"`
 		Port    string `json:"port"`
 	}
+
+	// GetPeersVerbose payload for outputting peers in verbose `getpeers`
+	// RPC call.
+	GetPeersVerbose struct {
+		Unconnected Peers      `json:"unconnected"`
+		Connected   []PeerInfo `json:"connected"`
+		Bad         Peers      `json:"bad"`
+	}
+
+	// PeerInfo represents the connected peer with its state and connection
+	// statistics.
+	PeerInfo struct {
+		Peer
+		UserAgent      string `json:"useragent"`
+		StartHeight    uint32 `json:"startheight"`
+		LastBlockIndex uint32 `json:"lastblockindex"`
+		BytesReceived  uint64 `json:"bytesreceived"`
+		BytesSent      uint64 `json:"bytessent"`
+		// ConnectionAge is the time since connection establishment in
+		// seconds.
+		ConnectionAge int64 `json:"connectionage"`
+	}
 )
 
 // NewGetPeers creates a new GetPeers structure.
@@ -31,6 +54,20 @@ func NewGetPeers() GetPeers {
 	}
 }
 
+// NewGetPeersVerbose creates a new GetPeersVerbose structure.
+func NewGetPeersVerbose() GetPeersVerbose {
+	return GetPeersVerbose{
+		Unconnected: []Peer{},
+		Connected:   []PeerInfo{},
+		Bad:         []Peer{},
+	}
+}
+
+// NewPeerInfo creates a new PeerInfo structure for the given address.
+func NewPeerInfo(addr string) PeerInfo {
+	return PeerInfo{Peer: newPeer(addr)}
+}
+
 // AddUnconnected adds a set of peers to the unconnected peers slice.
 func (g *GetPeers) AddUnconnected(addrs []string) {
 	g.Unconnected.addPeers(addrs)
@@ -46,15 +83,36 @@ func (g *GetPeers) AddBad(addrs []string) {
 	g.Bad.addPeers(addrs)
 }
 
+// AddUnconnected adds a set of peers to the unconnected peers slice.
+func (g *GetPeersVerbose) AddUnconnected(addrs []string) {
+	g.Unconnected.addPeers(addrs)
+}
+
+// AddBad adds a set of peers to the bad peers slice.
+func (g *GetPeersVerbose) AddBad(addrs []string) {
+	g.Bad.addPeers(addrs)
+}
+
 // addPeers adds a set of peers to the given peer slice.
 func (p *Peers) addPeers(addrs []string) {
 	for i := range addrs {
-		addressParts := strings.Split(addrs[i], ":")
-		peer := Peer{
-			Address: addressParts[0],
-			Port:    addressParts[1],
-		}
+		*p = append(*p, newPeer(addrs[i]))
+	}
+}
 
-		*p = append(*p, peer)
+// newPeer creates a Peer from the given address. Address scheme (like ws://)
+// if any is kept in the Address field.
+func newPeer(addr string) Peer {
+	var scheme string
+	if i := strings.Index(addr, "://"); i >= 0 {
+		scheme, addr = addr[:i+3], addr[i+3:]
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	return Peer{
+		Address: scheme + host,
+		Port:    port,
 	}
 }
@@ -24,3 +24,18 @@ func TestGetPeers(t *testing.T) {
 	require.Equal(t, "127.0.0.1", gp.Bad[0].Address)
 	require.Equal(t, "20333", gp.Bad[0].Port)
 }
+
+func TestGetPeersVerbose(t *testing.T) {
+	gp := NewGetPeersVerbose()
+	require.Equal(t, 0, len(gp.Connected))
+
+	gp.AddUnconnected([]string{"ws://1.1.1.1:53", "[::1]:20333"})
+	require.Equal(t, "ws://1.1.1.1", gp.Unconnected[0].Address)
+	require.Equal(t, "53", gp.Unconnected[0].Port)
+	require.Equal(t, "::1", gp.Unconnected[1].Address)
+	require.Equal(t, "20333", gp.Unconnected[1].Port)
+
+	info := NewPeerInfo("192.168.0.1:10333")
+	require.Equal(t, "192.168.0.1", info.Address)
+	require.Equal(t, "10333", info.Port)
+}
@@ -8,7 +8,9 @@ import (
 	"math"
 	"net"
 	"net/http"
+	"sort"
 	"strconv"
+	"time"
 
 	"github.com/nspcc-dev/neo-go/pkg/core/blockchainer"
 	"github.com/nspcc-dev/neo-go/pkg/rpc"
@@ -56,6 +58,7 @@ var rpcHandlers = map[string]func(*Server, request.Params) (interface{}, error){
 	"getblocksysfee":       (*Server).getBlockSysFee,
 	"getclaimable":         (*Server).getClaimable,
 	"getconnectioncount":   (*Server).getConnectionCount,
+	"getconsensusstate":    (*Server).getConsensusState,
 	"getcontractstate":     (*Server).getContractState,
 	"getnep5balances":      (*Server).getNEP5Balances,
 	"getnep5transfers":     (*Server).getNEP5Transfers,
@@ -276,7 +279,22 @@ func (s *Server) getVersion(_ request.Params) (interface{}, error) {
 	}, nil
 }
 
-func (s *Server) getPeers(_ request.Params) (interface{}, error) {
+func (s *Server) getPeers(reqParams request.Params) (interface{}, error) {
+	var verbose bool
+
+	param, ok := reqParams.ValueWithType(0, request.NumberT)
+	if ok {
+		v, err := param.GetInt()
+		if err != nil {
+			return nil, response.ErrInvalidParams
+		}
+		verbose = v != 0
+	}
+
+	if verbose {
+		return s.getPeersVerbose(), nil
+	}
+
 	peers := result.NewGetPeers()
 	peers.AddUnconnected(s.coreServer.UnconnectedPeers())
 	peers.AddConnected(s.coreServer.ConnectedPeers())
@@ -284,6 +302,66 @@ func (s *Server) getPeers(_ request.Params) (interface{}, error) {
 	return peers, nil
 }
 
+// getPeersVerbose returns peers list with detailed information about
+// connected peers.
+func (s *Server) getPeersVerbose() result.GetPeersVerbose {
+	peers := result.NewGetPeersVerbose()
+	peers.AddUnconnected(s.coreServer.UnconnectedPeers())
+	peers.AddBad(s.coreServer.BadPeers())
+	now := time.Now()
+	for p := range s.coreServer.Peers() {
+		info := result.NewPeerInfo(p.PeerAddr().String())
+		if v := p.Version(); v != nil {
+			info.UserAgent = string(v.UserAgent)
+			info.StartHeight = v.StartHeight
+		}
+		stats := p.Stats()
+		info.LastBlockIndex = p.LastBlockIndex()
+		info.BytesReceived = stats.BytesReceived
+		info.BytesSent = stats.BytesSent
+		info.ConnectionAge = int64(now.Sub(stats.ConnectedAt) / time.Second)
+		peers.Connected = append(peers.Connected, info)
+	}
+	sort.Slice(peers.Connected, func(i, j int) bool {
+		return peers.Connected[i].Address < peers.Connected[j].Address ||
+			peers.Connected[i].Address == peers.Connected[j].Address &&
+				peers.Connected[i].Port < peers.Connected[j].Port
+	})
+	return peers
+}
+
+func (s *Server) getConsensusState(_ request.Params) (interface{}, error) {
+	st := s.coreServer.ConsensusState()
+	if st == nil {
+		return nil, response.NewRPCError("Consensus is not running", "", nil)
+	}
+	res := result.ConsensusState{
+		Height:     st.Height,
+		View:       st.View,
+		Primary:    st.Primary,
+		Validators: make([]result.ConsensusValidator, len(st.Validators)),
+		Events:     make([]result.ConsensusEvent, len(st.Events)),
+	}
+	for i, v := range st.Validators {
+		res.Validators[i] = result.ConsensusValidator{
+			PublicKey:   *v.PublicKey,
+			Preparation: v.Preparation,
+			Commit:      v.Commit,
+			ChangeView:  v.ChangeView,
+		}
+	}
+	for i, ev := range st.Events {
+		res.Events[i] = result.ConsensusEvent{
+			Time:      ev.Time.UnixNano() / int64(time.Millisecond),
+			Type:      string(ev.Type),
+			View:      ev.View,
+			Validator: ev.Validator,
+			Details:   ev.Details,
+		}
+	}
+	return res, nil
+}
+
 func (s *Server) getRawMempool(_ request.Params) (interface{}, error) {
 	mp := s.chain.GetMemPool()
 	hashList := make([]util.Uint256, 0)
@@ -332,6 +410,9 @@ func (s *Server) getApplicationLog(reqParams request.Params) (interface{}, error
 	}
 
 	appExecResult, err := s.chain.GetAppExecResult(txHash)
+	if err == core.ErrPruned {
+		return nil, response.ErrPruned
+	}
 	if err != nil {
 		return nil, response.NewRPCError("Unknown transaction", "", nil)
 	}
@@ -414,7 +495,13 @@ func (s *Server) getNEP5Balances(ps request.Params) (interface{}, error) {
 		return nil, response.ErrInvalidParams
 	}
 
-	as := s.chain.GetNEP5Balances(u)
+	view, err := s.chain.GetStateView()
+	if err != nil {
+		return nil, response.NewInternalServerError("can't get chain state", err)
+	}
+	defer view.Release()
+
+	as := view.GetNEP5Balances(u)
 	bs := &result.NEP5Balances{
 		Address:  address.Uint160ToString(u),
 		Balances: []result.NEP5Balance{},
@@ -422,7 +509,7 @@ func (s *Server) getNEP5Balances(ps request.Params) (interface{}, error) {
 	if as != nil {
 		cache := make(map[util.Uint160]int64)
 		for h, bal := range as.Trackers {
-			dec, err := s.getDecimals(h, cache)
+			dec, err := s.getDecimals(view, h, cache)
 			if err != nil {
 				continue
 			}
@@ -446,22 +533,41 @@ func (s *Server) getNEP5Transfers(ps request.Params) (interface{}, error) {
 	if err != nil {
 		return nil, response.ErrInvalidParams
 	}
+	// Optional start timestamp, older transfers are not returned.
+	var start int
+	if p, ok := ps.ValueWithType(1, request.NumberT); ok {
+		start, err = p.GetInt()
+		if err != nil || start < 0 {
+			return nil, response.ErrInvalidParams
+		}
+	} else if len(ps) > 1 {
+		return nil, response.ErrInvalidParams
+	}
+
+	view, err := s.chain.GetStateView()
+	if err != nil {
+		return nil, response.NewInternalServerError("can't get chain state", err)
+	}
+	defer view.Release()
 
 	bs := &result.NEP5Transfers{
 		Address:  address.Uint160ToString(u),
 		Received: []result.NEP5Transfer{},
 		Sent:     []result.NEP5Transfer{},
 	}
-	lg := s.chain.GetNEP5TransferLog(u)
+	lg := view.GetNEP5TransferLog(u)
 	cache := make(map[util.Uint160]int64)
 	err = lg.ForEach(func(tr *state.NEP5Transfer) error {
+		if int64(tr.Timestamp) < int64(start) {
+			return nil
+		}
 		transfer := result.NEP5Transfer{
 			Timestamp: tr.Timestamp,
 			Asset:     tr.Asset,
 			Index:     tr.Block,
 			TxHash:    tr.Tx,
 		}
-		d, err := s.getDecimals(tr.Asset, cache)
+		d, err := s.getDecimals(view, tr.Asset, cache)
 		if err != nil {
 			return nil
 		}
@@ -501,7 +607,7 @@ func amountToString(amount int64, decimals int64) string {
 	return fmt.Sprintf(fs, q, r)
 }
 
-func (s *Server) getDecimals(h util.Uint160, cache map[util.Uint160]int64) (int64, error) {
+func (s *Server) getDecimals(view blockchainer.StateView, h util.Uint160, cache map[util.Uint160]int64) (int64, error) {
 	if d, ok := cache[h]; ok {
 		return d, nil
 	}
@@ -518,7 +624,7 @@ func (s *Server) getDecimals(h util.Uint160, cache map[util.Uint160]int64) (int6
 	if err != nil {
 		return 0, err
 	}
-	res := s.runScriptInVM(script)
+	res := s.runScriptInVM(view, script)
 	if res == nil || res.State != "HALT" || len(res.Stack) == 0 {
 		return 0, errors.New("execution error")
 	}
@@ -699,7 +805,13 @@ func (s *Server) getAccountStateAux(reqParams request.Params, unspents bool) (in
 	} else if scriptHash, err := param.GetUint160FromAddress(); err != nil {
 		return nil, response.ErrInvalidParams
 	} else {
-		as := s.chain.GetAccountState(scriptHash)
+		view, err := s.chain.GetStateView()
+		if err != nil {
+			return nil, response.NewInternalServerError("can't get chain state", err)
+		}
+		defer view.Release()
+
+		as := view.GetAccountState(scriptHash)
 		if as == nil {
 			as = state.NewAccount(scriptHash)
 		}
@@ -845,7 +957,7 @@ func (s *Server) invoke(reqParams request.Params) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	return s.runScriptInVM(script), nil
+	return s.invokeScript(script)
 }
 
 // invokescript implements the `invokescript` RPC call.
@@ -862,7 +974,7 @@ func (s *Server) invokeFunction(reqParams request.Params) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	return s.runScriptInVM(script), nil
+	return s.invokeScript(script)
 }
 
 // invokescript implements the `invokescript` RPC call.
@@ -876,13 +988,24 @@ func (s *Server) invokescript(reqParams request.Params) (interface{}, error) {
 		return nil, response.ErrInvalidParams
 	}
 
-	return s.runScriptInVM(script), nil
+	return s.invokeScript(script)
+}
+
+// invokeScript runs given script in a new test VM over the current chain state
+// and returns the invocation result.
+func (s *Server) invokeScript(script []byte) (interface{}, error) {
+	view, err := s.chain.GetStateView()
+	if err != nil {
+		return nil, response.NewInternalServerError("can't get chain state", err)
+	}
+	defer view.Release()
+	return s.runScriptInVM(view, script), nil
 }
 
-// runScriptInVM runs given script in a new test VM and returns the invocation
-// result.
-func (s *Server) runScriptInVM(script []byte) *result.Invoke {
-	vm := s.chain.GetTestVM()
+// runScriptInVM runs given script in a new test VM over the given chain state
+// view and returns the invocation result.
+func (s *Server) runScriptInVM(view blockchainer.StateView, script []byte) *result.Invoke {
+	vm := view.GetTestVM()
 	vm.SetGasLimit(s.config.MaxGasInvoke)
 	vm.LoadScript(script)
 	_ = vm.Run()
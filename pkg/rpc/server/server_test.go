@@ -189,6 +189,22 @@ var rpcTestCases = map[string][]rpcTestCase{
 			params: `["notahex"]`,
 			fail:   true,
 		},
+		{
+			name:   "invalid start",
+			params: `["` + testchain.PrivateKeyByID(0).Address() + `", "notanumber"]`,
+			fail:   true,
+		},
+		{
+			name:   "start in the future",
+			params: `["` + testchain.PrivateKeyByID(0).Address() + `", 4294967295]`,
+			result: func(e *executor) interface{} { return &result.NEP5Transfers{} },
+			check: func(t *testing.T, e *executor, acc interface{}) {
+				res, ok := acc.(*result.NEP5Transfers)
+				require.True(t, ok)
+				require.Equal(t, 0, len(res.Received))
+				require.Equal(t, 0, len(res.Sent))
+			},
+		},
 		{
 			name:   "positive",
 			params: `["` + testchain.PrivateKeyByID(0).Address() + `"]`,
@@ -500,6 +516,13 @@ var rpcTestCases = map[string][]rpcTestCase{
 			},
 		},
 	},
+	"getconsensusstate": {
+		{
+			name:   "not running",
+			params: "[]",
+			fail:   true,
+		},
+	},
 	"getpeers": {
 		{
 			params: "[]",
@@ -511,6 +534,17 @@ var rpcTestCases = map[string][]rpcTestCase{
 				}
 			},
 		},
+		{
+			name:   "verbose",
+			params: "[1]",
+			result: func(*executor) interface{} {
+				return &result.GetPeersVerbose{
+					Unconnected: []result.Peer{},
+					Connected:   []result.PeerInfo{},
+					Bad:         []result.Peer{},
+				}
+			},
+		},
 	},
 	"getrawtransaction": {
 		{
@@ -35,6 +35,11 @@ type paramContext struct {
 	Items map[string]json.RawMessage `json:"items"`
 }
 
+// signable is an item which can be signed.
+type signable interface {
+	GetSignedPart() []byte
+}
+
 type sigWithIndex struct {
 	index int
 	sig   []byte
@@ -67,6 +72,43 @@ func (c *ParameterContext) GetWitness(ctr *wallet.Contract) (*transaction.Witnes
 	}, nil
 }
 
+// GetWitnesses returns witnesses for all context items. Items must have
+// VerificationScript set and all signatures present.
+func (c *ParameterContext) GetWitnesses() ([]transaction.Witness, error) {
+	hashes := make([]util.Uint160, 0, len(c.Items))
+	for h := range c.Items {
+		hashes = append(hashes, h)
+	}
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i].Less(hashes[j]) })
+	ws := make([]transaction.Witness, 0, len(hashes))
+	for _, h := range hashes {
+		item := c.Items[h]
+		if item.VerificationScript == nil {
+			return nil, fmt.Errorf("no verification script for %s", h.StringLE())
+		}
+		w, err := c.GetWitness(&wallet.Contract{Script: item.VerificationScript})
+		if err != nil {
+			return nil, fmt.Errorf("can't create witness for %s: %v", h.StringLE(), err)
+		}
+		ws = append(ws, *w)
+	}
+	return ws, nil
+}
+
+// Sign signs the verifiable item with the private key corresponding to pub
+// using the signer and adds the signature for the specified contract.
+func (c *ParameterContext) Sign(ctr *wallet.Contract, s wallet.Signer, pub *keys.PublicKey) error {
+	v, ok := c.Verifiable.(signable)
+	if !ok {
+		return errors.New("verifiable item can't be signed")
+	}
+	sig, err := s.Sign(pub, v.GetSignedPart())
+	if err != nil {
+		return fmt.Errorf("can't sign: %v", err)
+	}
+	return c.AddSignature(ctr, pub, sig)
+}
+
 // AddSignature adds a signature for the specified contract and public key.
 func (c *ParameterContext) AddSignature(ctr *wallet.Contract, pub *keys.PublicKey, sig []byte) error {
 	item := c.getItemForContract(ctr)
@@ -134,9 +176,10 @@ func (c *ParameterContext) getItemForContract(ctr *wallet.Contract) *Item {
 		params[i].Type = ctr.Parameters[i].Type
 	}
 	item := &Item{
-		Script:     h,
-		Parameters: params,
-		Signatures: make(map[string][]byte),
+		Script:             h,
+		Parameters:         params,
+		Signatures:         make(map[string][]byte),
+		VerificationScript: ctr.Script,
 	}
 	c.Items[h] = item
 	return item
@@ -178,8 +221,10 @@ func (c *ParameterContext) UnmarshalJSON(data []byte) error {
 
 	var verif io.Serializable
 	switch pc.Type {
-	case "Neo.Core.ContractTransaction":
+	case "Neo.Core.ContractTransaction", "Neo.Core.InvocationTransaction", "Neo.Core.ClaimTransaction":
 		verif = new(transaction.Transaction)
+	case wallet.MessageContextType:
+		verif = new(wallet.Message)
 	default:
 		return fmt.Errorf("unsupported type: %s", c.Type)
 	}
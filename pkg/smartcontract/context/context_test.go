@@ -2,12 +2,16 @@ package context
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"testing"
 
+	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/crypto"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/internal/testserdes"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -64,6 +68,29 @@ func TestParameterContext_AddSignatureSimpleContract(t *testing.T) {
 	})
 }
 
+func TestParameterContext_Sign(t *testing.T) {
+	tx := getContractTx()
+	priv, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+	ctr := &wallet.Contract{
+		Script:     pub.GetVerificationScript(),
+		Parameters: []wallet.ContractParam{newParam(smartcontract.SignatureType, "parameter0")},
+	}
+
+	other, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	c := NewParameterContext("Neo.Core.ContractTransaction", tx)
+	require.Error(t, c.Sign(ctr, wallet.NewLocalSigner(other), pub))
+
+	require.NoError(t, c.Sign(ctr, wallet.NewLocalSigner(priv), pub))
+	w, err := c.GetWitness(ctr)
+	require.NoError(t, err)
+	v := newTestVM(w, tx)
+	require.NoError(t, v.Run())
+	require.Equal(t, true, v.Estack().Pop().Value())
+}
+
 func TestParameterContext_AddSignatureMultisig(t *testing.T) {
 	tx := getContractTx()
 	c := NewParameterContext("Neo.Core.ContractTransaction", tx)
@@ -144,6 +171,77 @@ func TestParameterContext_MarshalJSON(t *testing.T) {
 	testserdes.MarshalUnmarshalJSON(t, expected, new(ParameterContext))
 }
 
+func TestParameterContext_GetWitnesses(t *testing.T) {
+	tx := getContractTx()
+	privs, pubs := getPrivateKeys(t, 3)
+	c := NewParameterContext("Neo.Core.ContractTransaction", tx)
+
+	simple := &wallet.Contract{
+		Script:     pubs[0].GetVerificationScript(),
+		Parameters: []wallet.ContractParam{newParam(smartcontract.SignatureType, "parameter0")},
+	}
+	// Keys are sorted by CreateMultiSigRedeemScript, so pubs can't be used
+	// along with privs after it.
+	multiScript, err := smartcontract.CreateMultiSigRedeemScript(2, pubs[1:])
+	require.NoError(t, err)
+	multi := &wallet.Contract{
+		Script: multiScript,
+		Parameters: []wallet.ContractParam{
+			newParam(smartcontract.SignatureType, "parameter0"),
+			newParam(smartcontract.SignatureType, "parameter1"),
+		},
+	}
+	require.NoError(t, c.AddSignature(simple, pubs[0], privs[0].Sign(tx.GetSignedPart())))
+	require.NoError(t, c.AddSignature(multi, privs[1].PublicKey(), privs[1].Sign(tx.GetSignedPart())))
+	_, err = c.GetWitnesses()
+	require.Error(t, err, "not enough signatures")
+	require.NoError(t, c.AddSignature(multi, privs[2].PublicKey(), privs[2].Sign(tx.GetSignedPart())))
+
+	// Contracts are not needed after the roundtrip.
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+	actual := new(ParameterContext)
+	require.NoError(t, json.Unmarshal(data, actual))
+	ws, err := actual.GetWitnesses()
+	require.NoError(t, err)
+	require.Equal(t, 2, len(ws))
+	for i := range ws {
+		require.True(t, i == 0 || ws[i-1].ScriptHash().Less(ws[i].ScriptHash()))
+		v := newTestVM(&ws[i], tx)
+		require.NoError(t, v.Run())
+		require.Equal(t, true, v.Estack().Pop().Value())
+	}
+
+	actual.Items[simple.ScriptHash()].VerificationScript = nil
+	_, err = actual.GetWitnesses()
+	require.Error(t, err)
+}
+
+func TestParameterContext_SignMessage(t *testing.T) {
+	privs, pubs := getPrivateKeys(t, 3)
+	script, err := smartcontract.CreateMultiSigRedeemScript(2, pubs)
+	require.NoError(t, err)
+	acc, err := wallet.NewWatchOnlyAccount(address.Uint160ToString(hash.Hash160(script)), script)
+	require.NoError(t, err)
+
+	m := wallet.NewMessage(uint32(config.ModeTestNet), []byte("I own this address"))
+	c := NewParameterContext(wallet.MessageContextType, m)
+	require.NoError(t, c.Sign(acc.Contract, wallet.NewLocalSigner(privs[2]), privs[2].PublicKey()))
+
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+	actual := new(ParameterContext)
+	require.NoError(t, json.Unmarshal(data, actual))
+	require.Equal(t, m, actual.Verifiable)
+	require.NoError(t, actual.Sign(acc.Contract, wallet.NewLocalSigner(privs[0]), privs[0].PublicKey()))
+
+	w, err := actual.GetWitness(acc.Contract)
+	require.NoError(t, err)
+	h, err := wallet.VerifyMessageWitness(m, w)
+	require.NoError(t, err)
+	require.Equal(t, acc.Contract.ScriptHash(), h)
+}
+
 func getPrivateKeys(t *testing.T, n int) ([]*keys.PrivateKey, []*keys.PublicKey) {
 	privs := make([]*keys.PrivateKey, n)
 	pubs := make([]*keys.PublicKey, n)
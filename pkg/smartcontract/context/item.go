@@ -14,12 +14,16 @@ type Item struct {
 	Script     util.Uint160
 	Parameters []smartcontract.Parameter
 	Signatures map[string][]byte
+	// VerificationScript is a contract script, it's needed to create
+	// a witness without the wallet holding the contract.
+	VerificationScript []byte
 }
 
 type itemAux struct {
-	Script     util.Uint160              `json:"script"`
-	Parameters []smartcontract.Parameter `json:"parameters"`
-	Signatures map[string]string         `json:"signatures"`
+	Script             util.Uint160              `json:"script"`
+	Parameters         []smartcontract.Parameter `json:"parameters"`
+	Signatures         map[string]string         `json:"signatures"`
+	VerificationScript string                    `json:"verification,omitempty"`
 }
 
 // GetSignature returns signature for pub if present.
@@ -36,9 +40,10 @@ func (it *Item) AddSignature(pub *keys.PublicKey, sig []byte) {
 // MarshalJSON implements json.Marshaler interface.
 func (it Item) MarshalJSON() ([]byte, error) {
 	ci := itemAux{
-		Script:     it.Script,
-		Parameters: it.Parameters,
-		Signatures: make(map[string]string, len(it.Signatures)),
+		Script:             it.Script,
+		Parameters:         it.Parameters,
+		Signatures:         make(map[string]string, len(it.Signatures)),
+		VerificationScript: hex.EncodeToString(it.VerificationScript),
 	}
 
 	for key, sig := range it.Signatures {
@@ -68,8 +73,18 @@ func (it *Item) UnmarshalJSON(data []byte) error {
 		sigs[keyHex] = sig
 	}
 
+	var script []byte
+	if ci.VerificationScript != "" {
+		var err error
+		script, err = hex.DecodeString(ci.VerificationScript)
+		if err != nil {
+			return err
+		}
+	}
+
 	it.Signatures = sigs
 	it.Script = ci.Script
 	it.Parameters = ci.Parameters
+	it.VerificationScript = script
 	return nil
 }
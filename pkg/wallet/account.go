@@ -13,9 +13,15 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 )
 
+// ErrWatchOnly is returned when trying to decrypt the key of watch-only
+// account.
+var ErrWatchOnly = errors.New("watch-only account has no key")
+
 // Account represents a NEO account. It holds the private and public key
 // along with some metadata.
 type Account struct {
@@ -28,6 +34,10 @@ type Account struct {
 	// Account import file.
 	wif string
 
+	// Scrypt parameters of the wallet the account belongs to, NEP-2 ones
+	// are used if not set.
+	scrypt keys.ScryptParams
+
 	// NEO public address.
 	Address string `json:"address"`
 
@@ -47,6 +57,16 @@ type Account struct {
 
 	// Indicates whether the account is the default change account.
 	Default bool `json:"isDefault"`
+
+	// Extra contains additional account data, it's only present for
+	// HD (hierarchical deterministic) accounts.
+	Extra *AccountExtra `json:"extra,omitempty"`
+}
+
+// AccountExtra is an additional account data.
+type AccountExtra struct {
+	// DerivationPath is a BIP-32 derivation path of an HD account key.
+	DerivationPath string `json:"derivationPath,omitempty"`
 }
 
 // Contract represents a subset of the smartcontract to embed in the
@@ -117,6 +137,40 @@ func (c *Contract) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// NewWatchOnlyAccount creates an Account without a key for the given address.
+// Verification script is optional, if it's given it must match the address,
+// it allows to create unsigned transactions for this account to be signed
+// elsewhere (like with a remote signer).
+func NewWatchOnlyAccount(addr string, script []byte) (*Account, error) {
+	h, err := address.StringToUint160(addr)
+	if err != nil {
+		return nil, err
+	}
+	a := &Account{Address: addr}
+	if script == nil {
+		return a, nil
+	}
+	if !hash.Hash160(script).Equals(h) {
+		return nil, errors.New("verification script doesn't match the address")
+	}
+	var params []ContractParam
+	if vm.IsSignatureContract(script) {
+		params = getContractParams(1)
+	} else if pubs, ok := vm.ParseMultiSigContract(script); ok {
+		params = getContractParams(getMultiSigM(script, len(pubs)))
+	}
+	a.Contract = &Contract{
+		Script:     script,
+		Parameters: params,
+	}
+	return a, nil
+}
+
+// IsWatchOnly returns true if the account has no key.
+func (a *Account) IsWatchOnly() bool {
+	return a.EncryptedWIF == "" && a.privateKey == nil
+}
+
 // NewAccount creates a new Account with a random generated PrivateKey.
 func NewAccount() (*Account, error) {
 	priv, err := keys.NewPrivateKey()
@@ -150,14 +204,21 @@ func (a *Account) getVerificationScript() []byte {
 }
 
 // Decrypt decrypts the EncryptedWIF with the given passphrase returning error
-// if anything goes wrong.
+// if anything goes wrong. NEP-2 scrypt parameters are used unless the account
+// belongs to a wallet specifying other ones.
 func (a *Account) Decrypt(passphrase string) error {
+	return a.DecryptWithParams(passphrase, a.scryptParams())
+}
+
+// DecryptWithParams is the same as Decrypt, but uses the given scrypt
+// parameters.
+func (a *Account) DecryptWithParams(passphrase string, scrypt keys.ScryptParams) error {
 	var err error
 
 	if a.EncryptedWIF == "" {
-		return errors.New("no encrypted wif in the account")
+		return ErrWatchOnly
 	}
-	a.privateKey, err = keys.NEP2Decrypt(a.EncryptedWIF, passphrase)
+	a.privateKey, err = keys.NEP2DecryptWithParams(a.EncryptedWIF, passphrase, scrypt)
 	if err != nil {
 		return err
 	}
@@ -169,9 +230,15 @@ func (a *Account) Decrypt(passphrase string) error {
 }
 
 // Encrypt encrypts the wallet's PrivateKey with the given passphrase
-// under the NEP-2 standard.
+// under the NEP-2 standard (scrypt parameters are the same as for Decrypt).
 func (a *Account) Encrypt(passphrase string) error {
-	wif, err := keys.NEP2Encrypt(a.privateKey, passphrase)
+	return a.EncryptWithParams(passphrase, a.scryptParams())
+}
+
+// EncryptWithParams is the same as Encrypt, but uses the given scrypt
+// parameters.
+func (a *Account) EncryptWithParams(passphrase string, scrypt keys.ScryptParams) error {
+	wif, err := keys.NEP2EncryptWithParams(a.privateKey, passphrase, scrypt)
 	if err != nil {
 		return err
 	}
@@ -179,6 +246,14 @@ func (a *Account) Encrypt(passphrase string) error {
 	return nil
 }
 
+// scryptParams returns scrypt parameters used for the account key encryption.
+func (a *Account) scryptParams() keys.ScryptParams {
+	if a.scrypt == (keys.ScryptParams{}) {
+		return keys.NEP2ScryptParams()
+	}
+	return a.scrypt
+}
+
 // PrivateKey returns private key corresponding to the account.
 func (a *Account) PrivateKey() *keys.PrivateKey {
 	return a.privateKey
@@ -254,6 +329,19 @@ func newAccountFromPrivateKey(p *keys.PrivateKey) *Account {
 	return a
 }
 
+// getMultiSigM returns the number of signatures required by the valid multisig
+// contract with n keys.
+func getMultiSigM(script []byte, n int) int {
+	instr, param, _ := vm.NewContext(script).Next()
+	if opcode.PUSH1 <= instr && instr <= opcode.PUSH16 {
+		return int(instr-opcode.PUSH1) + 1
+	}
+	if m := emit.BytesToInt(param); m.IsInt64() && m.Int64() <= int64(n) {
+		return int(m.Int64())
+	}
+	return n
+}
+
 func getContractParams(n int) []ContractParam {
 	params := make([]ContractParam, n)
 	for i := range params {
@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/internal/keytestcases"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -123,6 +126,56 @@ func TestAccount_ConvertMultisig(t *testing.T) {
 	})
 }
 
+func TestNewWatchOnlyAccount(t *testing.T) {
+	pubs := convertPubs(t, []string{
+		"02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2",
+		"02103a7f7dd016558597f7960d27c516a4394fd968b9e65155eb4b013e4040406e",
+		"02a7bc55fe8684e0119768d104ba30795bdcc86619e864add26156723ed185cd62",
+	})
+
+	t.Run("address only", func(t *testing.T) {
+		a, err := NewWatchOnlyAccount("ANg3mmstMr7qtY8TgdKM777WSLKCNFbawM", nil)
+		require.NoError(t, err)
+		require.True(t, a.IsWatchOnly())
+		require.Nil(t, a.Contract)
+		require.Equal(t, ErrWatchOnly, a.Decrypt("pass"))
+		require.Error(t, a.SignTx(transaction.NewContractTX()))
+	})
+
+	t.Run("signature contract", func(t *testing.T) {
+		script := pubs[0].GetVerificationScript()
+		a, err := NewWatchOnlyAccount(address.Uint160ToString(hash.Hash160(script)), script)
+		require.NoError(t, err)
+		require.True(t, a.IsWatchOnly())
+		require.Equal(t, script, a.Contract.Script)
+		require.Equal(t, 1, len(a.Contract.Parameters))
+	})
+
+	t.Run("multisig contract", func(t *testing.T) {
+		script, err := smartcontract.CreateMultiSigRedeemScript(2, pubs)
+		require.NoError(t, err)
+		a, err := NewWatchOnlyAccount(address.Uint160ToString(hash.Hash160(script)), script)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(a.Contract.Parameters))
+	})
+
+	t.Run("script mismatch", func(t *testing.T) {
+		_, err := NewWatchOnlyAccount("ANg3mmstMr7qtY8TgdKM777WSLKCNFbawM", pubs[1].GetVerificationScript())
+		require.Error(t, err)
+	})
+
+	t.Run("bad address", func(t *testing.T) {
+		_, err := NewWatchOnlyAccount("bad", nil)
+		require.Error(t, err)
+	})
+
+	t.Run("account with key", func(t *testing.T) {
+		a, err := NewAccount()
+		require.NoError(t, err)
+		require.False(t, a.IsWatchOnly())
+	})
+}
+
 func convertPubs(t *testing.T, hexKeys []string) []*keys.PublicKey {
 	pubs := make([]*keys.PublicKey, len(hexKeys))
 	for i := range pubs {
@@ -0,0 +1,22 @@
+package wallet
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// Contact is an address book entry.
+type Contact struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Note    string `json:"note,omitempty"`
+}
+
+// NewContact returns new address book entry for the given script hash.
+func NewContact(name string, h util.Uint160, note string) *Contact {
+	return &Contact{
+		Name:    name,
+		Address: address.Uint160ToString(h),
+		Note:    note,
+	}
+}
@@ -0,0 +1,125 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// GenerateAccounts creates n new accounts using the given number of
+// goroutines. If the prefix is not empty only accounts with addresses
+// starting with it are returned, so it can take a while (every additional
+// character makes it ~58 times longer), the search can be interrupted via ctx.
+// Keys of the accounts returned are not encrypted.
+func GenerateAccounts(ctx context.Context, n int, workers int, prefix string) ([]*Account, error) {
+	if err := checkAddressPrefix(prefix); err != nil {
+		return nil, err
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	var (
+		wg   sync.WaitGroup
+		accs = make(chan *Account)
+		errs = make(chan error, workers)
+	)
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				priv, err := keys.NewPrivateKey()
+				if err != nil {
+					errs <- err
+					return
+				}
+				// Address is checked before creating the account as
+				// it's the only thing needed for the search.
+				if !strings.HasPrefix(priv.Address(), prefix) {
+					continue
+				}
+				select {
+				case accs <- newAccountFromPrivateKey(priv):
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	res := make([]*Account, 0, n)
+	for len(res) < n {
+		select {
+		case acc := <-accs:
+			res = append(res, acc)
+		case err := <-errs:
+			return nil, err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return res, nil
+}
+
+// EncryptAccounts encrypts keys of all accounts with the passphrase using the
+// given number of goroutines.
+func EncryptAccounts(accs []*Account, passphrase string, scrypt keys.ScryptParams, workers int) error {
+	if workers < 1 {
+		workers = 1
+	}
+	var (
+		wg   sync.WaitGroup
+		once sync.Once
+		err  error
+		jobs = make(chan *Account)
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for acc := range jobs {
+				if e := acc.EncryptWithParams(passphrase, scrypt); e != nil {
+					once.Do(func() { err = fmt.Errorf("can't encrypt key of %s: %v", acc.Address, e) })
+				}
+			}
+		}()
+	}
+	for _, acc := range accs {
+		jobs <- acc
+	}
+	close(jobs)
+	wg.Wait()
+	return err
+}
+
+// checkAddressPrefix checks that there are addresses starting with the
+// prefix. Base58 alphabet is sorted and all standard addresses have the
+// same length, so it's enough to compare the prefix with the ones of the
+// smallest and the largest addresses.
+func checkAddressPrefix(prefix string) error {
+	for _, c := range prefix {
+		if !strings.ContainsRune(base58Alphabet, c) {
+			return fmt.Errorf("invalid prefix: '%c' is not a base58 character", c)
+		}
+	}
+	var max util.Uint160
+	for i := range max {
+		max[i] = 0xff
+	}
+	lo, hi := address.Uint160ToString(util.Uint160{}), address.Uint160ToString(max)
+	if len(prefix) > len(lo) || prefix < lo[:len(prefix)] || prefix > hi[:len(prefix)] {
+		return fmt.Errorf("invalid prefix: addresses are between %s and %s", lo, hi)
+	}
+	return nil
+}
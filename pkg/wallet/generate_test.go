@@ -0,0 +1,57 @@
+package wallet
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAccounts(t *testing.T) {
+	accs, err := GenerateAccounts(context.Background(), 5, 3, "")
+	require.NoError(t, err)
+	require.Equal(t, 5, len(accs))
+	seen := make(map[string]bool)
+	for _, acc := range accs {
+		require.False(t, seen[acc.Address])
+		seen[acc.Address] = true
+		require.Equal(t, acc.Address, acc.PrivateKey().Address())
+		require.Equal(t, "", acc.EncryptedWIF)
+	}
+
+	t.Run("prefix", func(t *testing.T) {
+		accs, err := GenerateAccounts(context.Background(), 2, 2, "AX")
+		require.NoError(t, err)
+		require.Equal(t, 2, len(accs))
+		for _, acc := range accs {
+			require.True(t, strings.HasPrefix(acc.Address, "AX"))
+		}
+	})
+	t.Run("invalid prefix", func(t *testing.T) {
+		for _, p := range []string{"A0", "AI", "B", "a", "Az"} {
+			_, err := GenerateAccounts(context.Background(), 1, 1, p)
+			require.Error(t, err, p)
+		}
+	})
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := GenerateAccounts(ctx, 1, 2, "AXXXXXXXXX")
+		require.Error(t, err)
+	})
+}
+
+func TestEncryptAccounts(t *testing.T) {
+	accs, err := GenerateAccounts(context.Background(), 3, 1, "")
+	require.NoError(t, err)
+	scrypt := keys.ScryptParams{N: 16, R: 1, P: 1}
+	require.NoError(t, EncryptAccounts(accs, "pass", scrypt, 2))
+	for _, acc := range accs {
+		wif := acc.PrivateKey().WIF()
+		require.NotEqual(t, "", acc.EncryptedWIF)
+		require.NoError(t, acc.DecryptWithParams("pass", scrypt))
+		require.Equal(t, wif, acc.PrivateKey().WIF())
+	}
+}
@@ -0,0 +1,85 @@
+package wallet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+)
+
+// NEOCoinType is the SLIP-44 coin type registered for NEO.
+const NEOCoinType = 888
+
+// neoDerivationPrefix is the BIP-44 derivation path prefix for the keys of
+// the first NEO account (in BIP-44 terms), external chain.
+var neoDerivationPrefix = fmt.Sprintf("m/44'/%d'/0'/0/", NEOCoinType)
+
+// NEODerivationPath returns the standard BIP-44 derivation path of the key
+// with the given index: m/44'/888'/0'/0/index.
+func NEODerivationPath(index uint32) string {
+	return fmt.Sprintf("%s%d", neoDerivationPrefix, index)
+}
+
+// NewHDAccount creates a new HD (hierarchical deterministic) Account with the
+// key derived from the given seed using the given derivation path.
+func NewHDAccount(seed []byte, path string) (*Account, error) {
+	master, err := keys.NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	p, err := keys.ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	k, err := master.Derive(p)
+	if err != nil {
+		return nil, err
+	}
+	a := newAccountFromPrivateKey(k.PrivateKey())
+	a.Extra = &AccountExtra{DerivationPath: keys.FormatDerivationPath(p)}
+	return a, nil
+}
+
+// IsHD returns true if the account key was derived from some seed.
+func (a *Account) IsHD() bool {
+	return a.Extra != nil && a.Extra.DerivationPath != ""
+}
+
+// CreateHDAccount derives a new account with the key using the standard NEO
+// derivation path with the given index from the seed, encrypts its private
+// key with the given passphrase, adds the account to the wallet and saves it.
+func (w *Wallet) CreateHDAccount(seed []byte, index uint32, name, passphrase string) (*Account, error) {
+	acc, err := NewHDAccount(seed, NEODerivationPath(index))
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range w.Accounts {
+		if a.Address == acc.Address {
+			return nil, fmt.Errorf("address '%s' is already in wallet", acc.Address)
+		}
+	}
+	acc.Label = name
+	if err := acc.EncryptWithParams(passphrase, w.Scrypt); err != nil {
+		return nil, err
+	}
+	w.AddAccount(acc)
+	return acc, w.Save()
+}
+
+// NextHDIndex returns the index following the maximum one used by the wallet
+// accounts derived with the standard NEO derivation path (0 if there are
+// none).
+func (w *Wallet) NextHDIndex() uint32 {
+	var next uint32
+	for _, a := range w.Accounts {
+		if !a.IsHD() || !strings.HasPrefix(a.Extra.DerivationPath, neoDerivationPrefix) {
+			continue
+		}
+		i, err := strconv.ParseUint(a.Extra.DerivationPath[len(neoDerivationPrefix):], 10, 32)
+		if err == nil && uint32(i) >= next {
+			next = uint32(i) + 1
+		}
+	}
+	return next
+}
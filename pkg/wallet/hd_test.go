@@ -0,0 +1,51 @@
+package wallet
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHDAccount(t *testing.T) {
+	wall := checkWalletConstructor(t)
+	seed, err := MnemonicToSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	require.NoError(t, err)
+
+	require.Equal(t, uint32(0), wall.NextHDIndex())
+	acc, err := wall.CreateHDAccount(seed, 0, "hd0", "pass")
+	require.NoError(t, err)
+	require.True(t, acc.IsHD())
+	require.Equal(t, "m/44'/888'/0'/0/0", acc.Extra.DerivationPath)
+	require.Equal(t, uint32(1), wall.NextHDIndex())
+
+	_, err = wall.CreateHDAccount(seed, 0, "hd0", "pass")
+	require.Error(t, err, "the same account")
+
+	acc5, err := wall.CreateHDAccount(seed, 5, "hd5", "pass")
+	require.NoError(t, err)
+	require.NotEqual(t, acc.Address, acc5.Address)
+	require.Equal(t, uint32(6), wall.NextHDIndex())
+
+	// The same seed and path give the same key.
+	same, err := NewHDAccount(seed, NEODerivationPath(5))
+	require.NoError(t, err)
+	require.Equal(t, acc5.Address, same.Address)
+
+	require.NoError(t, wall.CreateAccount("random", "pass"))
+	require.False(t, wall.Accounts[2].IsHD())
+	data, err := json.Marshal(wall.Accounts[2])
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "extra")
+
+	data, err = json.Marshal(wall)
+	require.NoError(t, err)
+	w2 := new(Wallet)
+	require.NoError(t, json.Unmarshal(data, w2))
+	require.Equal(t, uint32(6), w2.NextHDIndex())
+	require.NoError(t, w2.Accounts[1].Decrypt("pass"))
+	require.Equal(t, acc5.PrivateKey(), w2.Accounts[1].PrivateKey())
+
+	_, err = NewHDAccount(seed, "44'/888'")
+	require.Error(t, err)
+}
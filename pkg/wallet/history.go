@@ -0,0 +1,191 @@
+package wallet
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// HistoryEntryType is the type of account history entry.
+type HistoryEntryType string
+
+// History entry types.
+const (
+	// HistoryReceived is an output received by the account.
+	HistoryReceived HistoryEntryType = "received"
+	// HistorySpent is an output of the account spent.
+	HistorySpent HistoryEntryType = "spent"
+	// HistoryClaim is GAS received via claim transaction.
+	HistoryClaim HistoryEntryType = "claim"
+	// HistoryNEP5Received is NEP5 tokens transferred to the account.
+	HistoryNEP5Received HistoryEntryType = "nep5-received"
+	// HistoryNEP5Sent is NEP5 tokens transferred from the account.
+	HistoryNEP5Sent HistoryEntryType = "nep5-sent"
+)
+
+// History is a local index of account histories, it's stored in a separate
+// file next to the wallet (see HistoryPath) and is updated incrementally.
+// It only contains addresses, so it works the same way for accounts with
+// and without keys.
+type History struct {
+	Accounts map[string]*AccountHistory `json:"accounts"`
+}
+
+// AccountHistory is the history of a single account.
+type AccountHistory struct {
+	// Height is the chain height the history was last updated at.
+	Height uint32 `json:"height"`
+	// Entries are history entries sorted by block.
+	Entries []HistoryEntry `json:"entries"`
+	// Unspents are account outputs known to be unspent at Height, outputs
+	// missing from the next update are considered to be spent.
+	Unspents []HistoryOutput `json:"unspents"`
+
+	known map[string]bool
+}
+
+// HistoryEntry is a single account history event.
+type HistoryEntry struct {
+	Type  HistoryEntryType `json:"type"`
+	Block uint32           `json:"block"`
+	// Timestamp of the block, it's not known for approximate entries and
+	// outputs received before the first update.
+	Timestamp uint32 `json:"timestamp,omitempty"`
+	// Tx is the transaction of the output (for UTXO entries, including spent
+	// ones) or of the transfer (for NEP5 ones).
+	Tx util.Uint256 `json:"txid"`
+	// Index is an output index for UTXO entries and notification index for
+	// NEP5 ones.
+	Index uint32 `json:"n"`
+	// Asset is UTXO asset ID or NEP5 token hash (LE hex string).
+	Asset  string `json:"asset"`
+	Amount string `json:"amount"`
+	// Address is the other party for NEP5 transfers (if known).
+	Address string `json:"address,omitempty"`
+	// SpentBy is the transaction spending the output for spent entries (if
+	// known).
+	SpentBy *util.Uint256 `json:"spent_by,omitempty"`
+	// Approximate is set for spent entries with unknown spending height, in
+	// this case Block is the height of the update that found the output to
+	// be spent.
+	Approximate bool `json:"approximate,omitempty"`
+}
+
+// HistoryOutput is an account output tracked by the history.
+type HistoryOutput struct {
+	Asset util.Uint256 `json:"asset"`
+	Tx    util.Uint256 `json:"txid"`
+	Index uint16       `json:"n"`
+	Value util.Fixed8  `json:"value"`
+	Block uint32       `json:"block"`
+}
+
+// HistoryPath returns the path of history index file for the wallet located
+// at the given path.
+func HistoryPath(walletPath string) string {
+	return walletPath + ".history"
+}
+
+// NewHistory returns an empty History.
+func NewHistory() *History {
+	return &History{Accounts: make(map[string]*AccountHistory)}
+}
+
+// OpenHistory reads the history index from the given file, an empty History
+// is returned if there is no such file.
+func OpenHistory(path string) (*History, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return NewHistory(), nil
+	} else if err != nil {
+		return nil, err
+	}
+	h := NewHistory()
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, err
+	}
+	if h.Accounts == nil {
+		h.Accounts = make(map[string]*AccountHistory)
+	}
+	return h, nil
+}
+
+// Save writes the history index to a temporary file in the same directory and
+// then renames it to the given path, so the index is never left partially
+// written.
+func (h *History) Save(path string) error {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// GetAccount returns the history for the given address creating an empty one
+// if there is none.
+func (h *History) GetAccount(addr string) *AccountHistory {
+	ah, ok := h.Accounts[addr]
+	if !ok {
+		ah = new(AccountHistory)
+		h.Accounts[addr] = ah
+	}
+	return ah
+}
+
+func (e *HistoryEntry) key() string {
+	return string(e.Type) + e.Tx.StringLE() + e.Asset + strconv.FormatUint(uint64(e.Index), 10)
+}
+
+// AddEntry adds the entry to the history unless there already is the same one
+// (with the same type, transaction, asset and index), it returns true if the
+// entry was added. Entries are not sorted until Sort is called.
+func (ah *AccountHistory) AddEntry(e HistoryEntry) bool {
+	if ah.known == nil {
+		ah.known = make(map[string]bool, len(ah.Entries))
+		for i := range ah.Entries {
+			ah.known[ah.Entries[i].key()] = true
+		}
+	}
+	k := e.key()
+	if ah.known[k] {
+		return false
+	}
+	ah.known[k] = true
+	ah.Entries = append(ah.Entries, e)
+	return true
+}
+
+// Sort sorts history entries chronologically.
+func (ah *AccountHistory) Sort() {
+	sort.SliceStable(ah.Entries, func(i, j int) bool {
+		a, b := &ah.Entries[i], &ah.Entries[j]
+		if a.Block != b.Block {
+			return a.Block < b.Block
+		}
+		if !a.Tx.Equals(b.Tx) {
+			return a.Tx.StringLE() < b.Tx.StringLE()
+		}
+		return a.Index < b.Index
+	})
+}
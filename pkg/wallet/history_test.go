@@ -0,0 +1,68 @@
+package wallet
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountHistory_AddEntry(t *testing.T) {
+	ah := new(AccountHistory)
+	e := HistoryEntry{Type: HistoryReceived, Block: 10, Tx: util.Uint256{1}, Asset: "00", Amount: "1"}
+	require.True(t, ah.AddEntry(e))
+	require.False(t, ah.AddEntry(e))
+
+	e.Type = HistorySpent
+	e.Block = 5
+	require.True(t, ah.AddEntry(e))
+	e.Index = 1
+	e.Block = 7
+	require.True(t, ah.AddEntry(e))
+
+	ah.Sort()
+	require.Equal(t, 3, len(ah.Entries))
+	require.Equal(t, uint32(5), ah.Entries[0].Block)
+	require.Equal(t, uint32(7), ah.Entries[1].Block)
+	require.Equal(t, uint32(10), ah.Entries[2].Block)
+}
+
+func TestHistory_SaveOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	p := HistoryPath(path.Join(dir, "wallet.json"))
+	h, err := OpenHistory(p)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(h.Accounts))
+
+	ah := h.GetAccount("addr")
+	ah.Height = 42
+	ah.AddEntry(HistoryEntry{Type: HistoryNEP5Sent, Block: 10, Tx: util.Uint256{1}, Asset: "01", Amount: "3", Address: "other"})
+	ah.Unspents = append(ah.Unspents, HistoryOutput{Tx: util.Uint256{2}, Value: util.Fixed8FromInt64(5), Block: 20})
+	require.NoError(t, h.Save(p))
+
+	loaded, err := OpenHistory(p)
+	require.NoError(t, err)
+	lah := loaded.GetAccount("addr")
+	require.Equal(t, ah.Height, lah.Height)
+	require.Equal(t, ah.Entries, lah.Entries)
+	require.Equal(t, ah.Unspents, lah.Unspents)
+
+	// Loaded entries are known.
+	require.False(t, lah.AddEntry(ah.Entries[0]))
+
+	// Existing index is replaced without leaving temporary files behind.
+	lah.Height = 43
+	require.NoError(t, loaded.Save(p))
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(files))
+	loaded, err = OpenHistory(p)
+	require.NoError(t, err)
+	require.Equal(t, uint32(43), loaded.GetAccount("addr").Height)
+}
@@ -0,0 +1,148 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// MessageContextType is the ParameterContext type of signed messages.
+const MessageContextType = "Neo.SignedMessage"
+
+// messagePrefix separates message signatures from signatures of anything
+// else (like transactions), so that a signed message can't be used as a
+// signed transaction and vice versa.
+const messagePrefix = "Neo Signed Message:\n"
+
+// Message is an arbitrary off-chain message signed by account keys. It's bound
+// to the network, so signature made for one network is not valid for another.
+type Message struct {
+	// Magic is the network magic (see config.NetMode).
+	Magic uint32
+	Data  []byte
+}
+
+// NewMessage returns new message for the network.
+func NewMessage(magic uint32, data []byte) *Message {
+	return &Message{
+		Magic: magic,
+		Data:  data,
+	}
+}
+
+// EncodeBinary implements io.Serializable interface.
+func (m *Message) EncodeBinary(w *io.BinWriter) {
+	w.WriteU32LE(m.Magic)
+	w.WriteVarBytes(m.Data)
+}
+
+// DecodeBinary implements io.Serializable interface.
+func (m *Message) DecodeBinary(r *io.BinReader) {
+	m.Magic = r.ReadU32LE()
+	m.Data = r.ReadVarBytes()
+}
+
+// GetSignedPart returns the data to be signed: the message along with its
+// network magic prefixed with the domain separator.
+func (m *Message) GetSignedPart() []byte {
+	w := io.NewBufBinWriter()
+	w.WriteBytes([]byte(messagePrefix))
+	m.EncodeBinary(w.BinWriter)
+	return w.Bytes()
+}
+
+// Hash returns the hash signatures are made for.
+func (m *Message) Hash() util.Uint256 {
+	return hash.Sha256(m.GetSignedPart())
+}
+
+// SignMessage signs the message with the account key, the account must be
+// unlocked.
+func (a *Account) SignMessage(m *Message) ([]byte, error) {
+	if a.privateKey == nil {
+		return nil, errors.New("account is not unlocked")
+	}
+	return a.privateKey.Sign(m.GetSignedPart()), nil
+}
+
+// VerifyMessage checks the signature of the message made with the private key
+// corresponding to pub.
+func VerifyMessage(m *Message, pub *keys.PublicKey, sig []byte) bool {
+	if len(sig) != 64 {
+		return false
+	}
+	return pub.Verify(sig, m.Hash().BytesBE())
+}
+
+// VerifyMessageWitness checks that the message is signed by the owner of the
+// witness verification script which must be a standard signature or
+// multisignature contract. Script hash of the verification script (that is
+// the address owning the message) is returned on success.
+func VerifyMessageWitness(m *Message, w *transaction.Witness) (util.Uint160, error) {
+	var (
+		nsigs int
+		pubs  [][]byte
+		owner = hash.Hash160(w.VerificationScript)
+	)
+	sigs, err := parseInvocationSignatures(w.InvocationScript)
+	if err != nil {
+		return owner, err
+	}
+	if vm.IsSignatureContract(w.VerificationScript) {
+		nsigs, pubs = 1, [][]byte{w.VerificationScript[2:35]}
+	} else if ps, ok := vm.ParseMultiSigContract(w.VerificationScript); ok {
+		nsigs, pubs = getMultiSigM(w.VerificationScript, len(ps)), ps
+	} else {
+		return owner, errors.New("verification script is not a standard one")
+	}
+	if len(sigs) != nsigs {
+		return owner, fmt.Errorf("%d signatures expected, got %d", nsigs, len(sigs))
+	}
+
+	// Signatures follow the order of keys in the script, just like in
+	// CHECKMULTISIG.
+	var k int
+	for i := range sigs {
+		for ; k < len(pubs); k++ {
+			pub, err := keys.NewPublicKeyFromBytes(pubs[k])
+			if err != nil {
+				return owner, err
+			}
+			if VerifyMessage(m, pub, sigs[i]) {
+				break
+			}
+		}
+		if k == len(pubs) {
+			return owner, fmt.Errorf("signature %d is invalid", i)
+		}
+		k++
+	}
+	return owner, nil
+}
+
+// parseInvocationSignatures returns signatures pushed by the invocation
+// script.
+func parseInvocationSignatures(script []byte) ([][]byte, error) {
+	var (
+		sigs [][]byte
+		ctx  = vm.NewContext(script)
+	)
+	for ctx.NextIP() < len(script) {
+		instr, param, err := ctx.Next()
+		if err != nil {
+			return nil, err
+		}
+		if instr != opcode.PUSHDATA1 || len(param) != 64 {
+			return nil, errors.New("invocation script is not a list of signatures")
+		}
+		sigs = append(sigs, param)
+	}
+	return sigs, nil
+}
@@ -0,0 +1,120 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/internal/testserdes"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessage_EncodeDecodeBinary(t *testing.T) {
+	m := NewMessage(0x74746e41, []byte("I own this address"))
+	testserdes.EncodeDecodeBinary(t, m, new(Message))
+}
+
+func TestSignMessage(t *testing.T) {
+	priv, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	acc := newAccountFromPrivateKey(priv)
+
+	m := NewMessage(0x00746e41, []byte("I own this address"))
+	sig, err := acc.SignMessage(m)
+	require.NoError(t, err)
+	require.True(t, VerifyMessage(m, priv.PublicKey(), sig))
+
+	t.Run("other network", func(t *testing.T) {
+		require.False(t, VerifyMessage(NewMessage(0x74746e41, m.Data), priv.PublicKey(), sig))
+	})
+	t.Run("other message", func(t *testing.T) {
+		require.False(t, VerifyMessage(NewMessage(m.Magic, []byte("I don't")), priv.PublicKey(), sig))
+	})
+	t.Run("raw data signature", func(t *testing.T) {
+		require.False(t, VerifyMessage(m, priv.PublicKey(), priv.Sign(m.Data)))
+	})
+	t.Run("locked account", func(t *testing.T) {
+		acc, err := NewWatchOnlyAccount(acc.Address, nil)
+		require.NoError(t, err)
+		_, err = acc.SignMessage(m)
+		require.Error(t, err)
+	})
+}
+
+func TestVerifyMessageWitness(t *testing.T) {
+	privs := make([]*keys.PrivateKey, 3)
+	pubs := make(keys.PublicKeys, len(privs))
+	for i := range privs {
+		var err error
+		privs[i], err = keys.NewPrivateKey()
+		require.NoError(t, err)
+		pubs[i] = privs[i].PublicKey()
+	}
+	m := NewMessage(0x00746e41, []byte("I own this address"))
+	invocation := func(privs ...*keys.PrivateKey) []byte {
+		var script []byte
+		for _, p := range privs {
+			script = append(script, byte(opcode.PUSHDATA1), 64)
+			script = append(script, p.Sign(m.GetSignedPart())...)
+		}
+		return script
+	}
+
+	t.Run("signature", func(t *testing.T) {
+		w := &transaction.Witness{
+			InvocationScript:   invocation(privs[0]),
+			VerificationScript: pubs[0].GetVerificationScript(),
+		}
+		h, err := VerifyMessageWitness(m, w)
+		require.NoError(t, err)
+		require.Equal(t, pubs[0].GetScriptHash(), h)
+
+		w.InvocationScript = invocation(privs[1])
+		_, err = VerifyMessageWitness(m, w)
+		require.Error(t, err)
+	})
+
+	t.Run("multisig", func(t *testing.T) {
+		script, err := smartcontract.CreateMultiSigRedeemScript(2, pubs)
+		require.NoError(t, err)
+
+		// Keys are sorted in the script, signatures must follow their order.
+		ordered, ok := vm.ParseMultiSigContract(script)
+		require.True(t, ok)
+		sorted := make([]*keys.PrivateKey, len(ordered))
+		for i := range ordered {
+			for _, p := range privs {
+				if string(p.PublicKey().Bytes()) == string(ordered[i]) {
+					sorted[i] = p
+				}
+			}
+		}
+
+		w := &transaction.Witness{
+			InvocationScript:   invocation(sorted[0], sorted[2]),
+			VerificationScript: script,
+		}
+		_, err = VerifyMessageWitness(m, w)
+		require.NoError(t, err)
+
+		w.InvocationScript = invocation(sorted[2], sorted[0])
+		_, err = VerifyMessageWitness(m, w)
+		require.Error(t, err)
+
+		w.InvocationScript = invocation(sorted[1])
+		_, err = VerifyMessageWitness(m, w)
+		require.Error(t, err)
+	})
+
+	t.Run("non-standard", func(t *testing.T) {
+		w := &transaction.Witness{
+			InvocationScript:   invocation(privs[0]),
+			VerificationScript: []byte{byte(opcode.PUSHT)},
+		}
+		_, err := VerifyMessageWitness(m, w)
+		require.Error(t, err)
+	})
+}
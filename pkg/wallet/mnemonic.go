@@ -0,0 +1,120 @@
+package wallet
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// DefaultMnemonicEntropy is the default entropy size (in bits) used
+	// for mnemonic generation, it corresponds to 24 words.
+	DefaultMnemonicEntropy = 256
+
+	mnemonicSeedIterations = 2048
+	mnemonicSeedLength     = 64
+)
+
+var (
+	mnemonicWords   = strings.Fields(mnemonicWordList)
+	mnemonicIndexes = make(map[string]int, len(mnemonicWords))
+)
+
+func init() {
+	for i, w := range mnemonicWords {
+		mnemonicIndexes[w] = i
+	}
+}
+
+// NewMnemonic generates a new BIP-39 mnemonic with the given entropy size in
+// bits (128, 160, 192, 224 or 256).
+func NewMnemonic(bits int) (string, error) {
+	if err := checkEntropySize(bits); err != nil {
+		return "", err
+	}
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", err
+	}
+	return NewMnemonicFromEntropy(entropy)
+}
+
+// NewMnemonicFromEntropy returns BIP-39 mnemonic encoding the given entropy.
+func NewMnemonicFromEntropy(entropy []byte) (string, error) {
+	bits := len(entropy) * 8
+	if err := checkEntropySize(bits); err != nil {
+		return "", err
+	}
+	csBits := uint(bits / 32)
+	h := sha256.Sum256(entropy)
+	// Entropy bits followed by the checksum bits.
+	n := new(big.Int).SetBytes(entropy)
+	n.Lsh(n, csBits)
+	n.Or(n, big.NewInt(int64(h[0]>>(8-csBits))))
+
+	words := make([]string, (bits+int(csBits))/11)
+	mask := big.NewInt(2047)
+	idx := new(big.Int)
+	for i := len(words) - 1; i >= 0; i-- {
+		idx.And(n, mask)
+		words[i] = mnemonicWords[idx.Int64()]
+		n.Rsh(n, 11)
+	}
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToEntropy checks the given BIP-39 mnemonic and returns the entropy
+// it encodes.
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	if len(words)%3 != 0 || len(words) < 12 || len(words) > 24 {
+		return nil, errors.New("invalid mnemonic: wrong number of words")
+	}
+	n := new(big.Int)
+	for _, w := range words {
+		i, ok := mnemonicIndexes[w]
+		if !ok {
+			return nil, fmt.Errorf("invalid mnemonic: unknown word %q", w)
+		}
+		n.Lsh(n, 11)
+		n.Or(n, big.NewInt(int64(i)))
+	}
+	csBits := uint(len(words) / 3)
+	cs := byte(new(big.Int).And(n, big.NewInt(1<<csBits-1)).Int64())
+	n.Rsh(n, csBits)
+
+	// Every 3 words encode 32 bits of entropy and 1 bit of checksum.
+	entropy := make([]byte, len(words)/3*4)
+	b := n.Bytes()
+	copy(entropy[len(entropy)-len(b):], b)
+	h := sha256.Sum256(entropy)
+	if h[0]>>(8-csBits) != cs {
+		return nil, errors.New("invalid mnemonic: wrong checksum")
+	}
+	return entropy, nil
+}
+
+// MnemonicToSeed checks the given BIP-39 mnemonic and returns the 64-byte
+// seed derived from it and the (optional) passphrase.
+func MnemonicToSeed(mnemonic, passphrase string) ([]byte, error) {
+	if _, err := MnemonicToEntropy(mnemonic); err != nil {
+		return nil, err
+	}
+	m := strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " ")
+	salt := "mnemonic" + norm.NFKD.String(passphrase)
+	return pbkdf2.Key([]byte(m), []byte(salt), mnemonicSeedIterations, mnemonicSeedLength, sha512.New), nil
+}
+
+func checkEntropySize(bits int) error {
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return fmt.Errorf("invalid entropy size %d, must be a multiple of 32 in [128, 256] range", bits)
+	}
+	return nil
+}
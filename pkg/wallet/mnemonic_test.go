@@ -0,0 +1,80 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"hash/crc32"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMnemonicWordList(t *testing.T) {
+	require.Equal(t, uint32(0xc1dbd296), crc32.ChecksumIEEE([]byte(mnemonicWordList)))
+	require.Equal(t, 2048, len(mnemonicWords))
+}
+
+func TestMnemonicVectors(t *testing.T) {
+	// Test vectors from https://github.com/trezor/python-mnemonic/blob/master/vectors.json
+	testCases := []struct {
+		entropy  string
+		mnemonic string
+		seed     string
+	}{
+		{
+			entropy:  "00000000000000000000000000000000",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			seed:     "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+			seed:     "2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+		},
+		{
+			entropy:  "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+			seed:     "dd48c104698c30cfe2b6142103248622fb7bb0ff692eebb00089b32d22484e1613912f0a5b694407be899ffd31ed3992c456cdf60f5d4564b8ba3f05a69890ad",
+		},
+	}
+	for _, tc := range testCases {
+		entropy, err := hex.DecodeString(tc.entropy)
+		require.NoError(t, err)
+		m, err := NewMnemonicFromEntropy(entropy)
+		require.NoError(t, err)
+		require.Equal(t, tc.mnemonic, m)
+
+		e, err := MnemonicToEntropy(m)
+		require.NoError(t, err)
+		require.Equal(t, entropy, e)
+
+		seed, err := MnemonicToSeed(m, "TREZOR")
+		require.NoError(t, err)
+		require.Equal(t, tc.seed, hex.EncodeToString(seed))
+	}
+}
+
+func TestNewMnemonic(t *testing.T) {
+	for _, bits := range []int{128, 160, 192, 224, 256} {
+		m, err := NewMnemonic(bits)
+		require.NoError(t, err)
+		require.Equal(t, bits*3/32, len(strings.Fields(m)))
+		_, err = MnemonicToEntropy(m)
+		require.NoError(t, err)
+	}
+	_, err := NewMnemonic(100)
+	require.Error(t, err)
+}
+
+func TestMnemonicInvalid(t *testing.T) {
+	for _, m := range []string{
+		"abandon abandon abandon",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon neogo",
+	} {
+		_, err := MnemonicToEntropy(m)
+		require.Error(t, err, m)
+		_, err = MnemonicToSeed(m, "")
+		require.Error(t, err, m)
+	}
+}
@@ -0,0 +1,92 @@
+package remote
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+
+// defaultTimeout is the default timeout for a single request.
+const defaultTimeout = 5 * time.Second
+
+// Client is a wallet.Signer using remote signer process. Every request is
+// made over a new connection, so the signer can be restarted without
+// restarting the client.
+type Client struct {
+	path    string
+	timeout time.Duration
+}
+
+var _ wallet.Signer = (*Client)(nil)
+
+// NewClient returns a new Client for the signer listening on the given Unix
+// socket. Zero timeout means the default one.
+func NewClient(path string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Client{
+		path:    path,
+		timeout: timeout,
+	}
+}
+
+// PublicKeys implements wallet.Signer interface.
+func (c *Client) PublicKeys() (keys.PublicKeys, error) {
+	resp, err := c.call(&request{Method: methodPublicKeys})
+	if err != nil {
+		return nil, err
+	}
+	pubs := make(keys.PublicKeys, len(resp.PublicKeys))
+	for i := range resp.PublicKeys {
+		if pubs[i], err = keys.NewPublicKeyFromString(resp.PublicKeys[i]); err != nil {
+			return nil, err
+		}
+	}
+	return pubs, nil
+}
+
+// Sign implements wallet.Signer interface.
+func (c *Client) Sign(pub *keys.PublicKey, data []byte) ([]byte, error) {
+	resp, err := c.call(&request{
+		Method:    methodSign,
+		PublicKey: hex.EncodeToString(pub.Bytes()),
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Signature, nil
+}
+
+// call performs a single request.
+func (c *Client) call(req *request) (*response, error) {
+	conn, err := net.DialTimeout("unix", c.path, c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return nil, err
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, err
+	}
+	resp := new(response)
+	if err := json.NewDecoder(conn).Decode(resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		if resp.Error == wallet.ErrUnknownKey.Error() {
+			return nil, wallet.ErrUnknownKey
+		}
+		return nil, errors.New(resp.Error)
+	}
+	return resp, nil
+}
@@ -0,0 +1,40 @@
+/*
+Package remote implements a wallet.Signer which keeps private keys in a
+separate process and a server for such process.
+
+Client and server talk over a Unix socket, each request and response is a
+single JSON object. There are two methods, "publickeys" returns the list of
+public keys available:
+
+	--> {"method":"publickeys"}
+	<-- {"publickeys":["02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2"]}
+
+and "sign" signs base64-encoded data with the key specified:
+
+	--> {"method":"sign","publickey":"02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2","data":"AQID"}
+	<-- {"signature":"..."}
+
+Errors are returned in "error" field of the response. Any number of requests
+can be made over a single connection.
+*/
+package remote
+
+// Protocol methods.
+const (
+	methodPublicKeys = "publickeys"
+	methodSign       = "sign"
+)
+
+type (
+	request struct {
+		Method    string `json:"method"`
+		PublicKey string `json:"publickey,omitempty"`
+		Data      []byte `json:"data,omitempty"`
+	}
+
+	response struct {
+		PublicKeys []string `json:"publickeys,omitempty"`
+		Signature  []byte   `json:"signature,omitempty"`
+		Error      string   `json:"error,omitempty"`
+	}
+)
@@ -0,0 +1,58 @@
+package remote
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "neogo.remotesigner")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	priv, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "signer.sock")
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	srv := NewServer(wallet.NewLocalSigner(priv))
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(l) }()
+
+	c := NewClient(path, 0)
+	pubs, err := c.PublicKeys()
+	require.NoError(t, err)
+	require.Equal(t, keys.PublicKeys{priv.PublicKey()}, pubs)
+
+	data := []byte{1, 2, 3}
+	h := sha256.Sum256(data)
+	sig, err := c.Sign(priv.PublicKey(), data)
+	require.NoError(t, err)
+	require.True(t, priv.PublicKey().Verify(sig, h[:]))
+
+	other, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	_, err = c.Sign(other.PublicKey(), data)
+	require.Equal(t, wallet.ErrUnknownKey, err)
+
+	t.Run("bad request", func(t *testing.T) {
+		_, err := c.call(&request{Method: "unknown"})
+		require.Error(t, err)
+		_, err = c.call(&request{Method: methodSign, PublicKey: "bad"})
+		require.Error(t, err)
+	})
+
+	srv.Shutdown()
+	require.Error(t, <-errCh)
+	_, err = c.PublicKeys()
+	require.Error(t, err)
+}
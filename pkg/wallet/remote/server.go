@@ -0,0 +1,129 @@
+package remote
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+
+// Server serves requests to the wallet.Signer it wraps.
+type Server struct {
+	signer wallet.Signer
+
+	lock     sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+// NewServer returns a new Server for the given signer.
+func NewServer(s wallet.Signer) *Server {
+	return &Server{
+		signer: s,
+		conns:  make(map[net.Conn]struct{}),
+	}
+}
+
+// Serve accepts connections on the listener and serves them until the
+// listener is closed or Shutdown is called.
+func (s *Server) Serve(l net.Listener) error {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		_ = l.Close()
+		return errors.New("server is shut down")
+	}
+	s.listener = l
+	s.lock.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		s.lock.Lock()
+		if s.closed {
+			s.lock.Unlock()
+			_ = conn.Close()
+			continue
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.lock.Unlock()
+
+		go s.handleConn(conn)
+	}
+}
+
+// Shutdown closes the listener and all active connections waiting for their
+// handlers to finish.
+func (s *Server) Shutdown() {
+	s.lock.Lock()
+	s.closed = true
+	if s.listener != nil {
+		_ = s.listener.Close()
+	}
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.lock.Unlock()
+	s.wg.Wait()
+}
+
+// handleConn serves requests made over a single connection.
+func (s *Server) handleConn(conn net.Conn) {
+	defer func() {
+		s.lock.Lock()
+		delete(s.conns, conn)
+		s.lock.Unlock()
+		_ = conn.Close()
+		s.wg.Done()
+	}()
+
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+	for {
+		req := new(request)
+		if err := dec.Decode(req); err != nil {
+			return
+		}
+		if err := enc.Encode(s.handleRequest(req)); err != nil {
+			return
+		}
+	}
+}
+
+// handleRequest performs the request.
+func (s *Server) handleRequest(req *request) *response {
+	switch req.Method {
+	case methodPublicKeys:
+		pubs, err := s.signer.PublicKeys()
+		if err != nil {
+			return &response{Error: err.Error()}
+		}
+		resp := &response{PublicKeys: make([]string, len(pubs))}
+		for i := range pubs {
+			resp.PublicKeys[i] = hex.EncodeToString(pubs[i].Bytes())
+		}
+		return resp
+	case methodSign:
+		pub, err := keys.NewPublicKeyFromString(req.PublicKey)
+		if err != nil {
+			return &response{Error: fmt.Sprintf("invalid public key: %v", err)}
+		}
+		sig, err := s.signer.Sign(pub, req.Data)
+		if err != nil {
+			return &response{Error: err.Error()}
+		}
+		return &response{Signature: sig}
+	default:
+		return &response{Error: fmt.Sprintf("unknown method %q", req.Method)}
+	}
+}
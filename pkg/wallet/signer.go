@@ -0,0 +1,81 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+)
+
+// Signer signs data with private keys it holds. Keys never leave the signer,
+// so it can be implemented by some external process or device.
+type Signer interface {
+	// PublicKeys returns public keys of all private keys available to the
+	// signer.
+	PublicKeys() (keys.PublicKeys, error)
+	// Sign signs data with the private key corresponding to the given
+	// public key. Signature format is the same as for keys.PrivateKey.Sign.
+	Sign(pub *keys.PublicKey, data []byte) ([]byte, error)
+}
+
+// ErrUnknownKey is returned by Signer when there is no private key for the
+// public key given.
+var ErrUnknownKey = errors.New("unknown key")
+
+// LocalSigner is a Signer keeping private keys in memory.
+type LocalSigner struct {
+	pubs  keys.PublicKeys
+	privs map[string]*keys.PrivateKey
+}
+
+// NewLocalSigner creates a new LocalSigner using the given private keys.
+func NewLocalSigner(privs ...*keys.PrivateKey) *LocalSigner {
+	s := &LocalSigner{
+		pubs:  make(keys.PublicKeys, 0, len(privs)),
+		privs: make(map[string]*keys.PrivateKey, len(privs)),
+	}
+	for _, priv := range privs {
+		pub := priv.PublicKey()
+		if _, ok := s.privs[string(pub.Bytes())]; ok {
+			continue
+		}
+		s.pubs = append(s.pubs, pub)
+		s.privs[string(pub.Bytes())] = priv
+	}
+	return s
+}
+
+// NewWalletSigner creates a new LocalSigner with keys of all wallet accounts
+// that can be decrypted using the given passphrase. Accounts without keys
+// are skipped, an error is returned if no keys can be decrypted.
+func NewWalletSigner(w *Wallet, passphrase string) (*LocalSigner, error) {
+	var privs []*keys.PrivateKey
+	for _, acc := range w.Accounts {
+		if acc.EncryptedWIF == "" {
+			continue
+		}
+		priv, err := keys.NEP2DecryptWithParams(acc.EncryptedWIF, passphrase, w.Scrypt)
+		if err != nil {
+			continue
+		}
+		privs = append(privs, priv)
+	}
+	if len(privs) == 0 {
+		return nil, fmt.Errorf("no accounts in %s can be unlocked", w.Path())
+	}
+	return NewLocalSigner(privs...), nil
+}
+
+// PublicKeys implements Signer interface.
+func (s *LocalSigner) PublicKeys() (keys.PublicKeys, error) {
+	return append(keys.PublicKeys(nil), s.pubs...), nil
+}
+
+// Sign implements Signer interface.
+func (s *LocalSigner) Sign(pub *keys.PublicKey, data []byte) ([]byte, error) {
+	priv, ok := s.privs[string(pub.Bytes())]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return priv.Sign(data), nil
+}
@@ -0,0 +1,49 @@
+package wallet
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalSigner(t *testing.T) {
+	priv1, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	priv2, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	s := NewLocalSigner(priv1, priv2, priv1)
+	pubs, err := s.PublicKeys()
+	require.NoError(t, err)
+	require.Equal(t, keys.PublicKeys{priv1.PublicKey(), priv2.PublicKey()}, pubs)
+
+	data := []byte{1, 2, 3}
+	h := sha256.Sum256(data)
+	sig, err := s.Sign(priv2.PublicKey(), data)
+	require.NoError(t, err)
+	require.True(t, priv2.PublicKey().Verify(sig, h[:]))
+
+	priv3, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	_, err = s.Sign(priv3.PublicKey(), data)
+	require.Equal(t, ErrUnknownKey, err)
+}
+
+func TestNewWalletSigner(t *testing.T) {
+	w := checkWalletConstructor(t)
+	_, err := NewWalletSigner(w, "pass")
+	require.Error(t, err)
+
+	require.NoError(t, w.CreateAccount("one", "pass"))
+	require.NoError(t, w.CreateAccount("two", "other"))
+	w.AddAccount(&Account{Address: "watch-only"})
+
+	s, err := NewWalletSigner(w, "pass")
+	require.NoError(t, err)
+	pubs, err := s.PublicKeys()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(pubs))
+	require.Equal(t, w.Accounts[0].Address, pubs[0].Address())
+}
@@ -3,10 +3,14 @@ package wallet
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
 )
@@ -38,10 +42,12 @@ type Wallet struct {
 	rw io.ReadWriter
 }
 
-// Extra stores imported token contracts.
+// Extra stores imported token contracts and the address book.
 type Extra struct {
 	// Tokens is a list of imported token contracts.
 	Tokens []*Token
+	// Contacts is an address book.
+	Contacts []*Contact `json:",omitempty"`
 }
 
 // NewWallet creates a new NEO wallet at the given location.
@@ -66,6 +72,12 @@ func NewWalletFromFile(path string) (*Wallet, error) {
 	if err := json.NewDecoder(file).Decode(wall); err != nil {
 		return nil, err
 	}
+	if wall.Scrypt == (keys.ScryptParams{}) {
+		wall.Scrypt = keys.NEP2ScryptParams()
+	}
+	for _, acc := range wall.Accounts {
+		acc.scrypt = wall.Scrypt
+	}
 	return wall, nil
 }
 
@@ -91,15 +103,17 @@ func (w *Wallet) CreateAccount(name, passphrase string) error {
 		return err
 	}
 	acc.Label = name
-	if err := acc.Encrypt(passphrase); err != nil {
+	if err := acc.EncryptWithParams(passphrase, w.Scrypt); err != nil {
 		return err
 	}
 	w.AddAccount(acc)
 	return w.Save()
 }
 
-// AddAccount adds an existing Account to the wallet.
+// AddAccount adds an existing Account to the wallet, its key is then
+// encrypted and decrypted using wallet scrypt parameters.
 func (w *Wallet) AddAccount(acc *Account) {
+	acc.scrypt = w.Scrypt
 	w.Accounts = append(w.Accounts, acc)
 }
 
@@ -133,6 +147,39 @@ func (w *Wallet) RemoveToken(h util.Uint160) error {
 	return errors.New("token wasn't found")
 }
 
+// AddContact adds new contact to the address book, names must be unique.
+func (w *Wallet) AddContact(c *Contact) error {
+	if w.GetContact(c.Name) != nil {
+		return fmt.Errorf("contact %q already exists", c.Name)
+	}
+	w.Extra.Contacts = append(w.Extra.Contacts, c)
+	return nil
+}
+
+// RemoveContact removes contact with the specified name from the address
+// book.
+func (w *Wallet) RemoveContact(name string) error {
+	for i, c := range w.Extra.Contacts {
+		if c.Name == name {
+			copy(w.Extra.Contacts[i:], w.Extra.Contacts[i+1:])
+			w.Extra.Contacts = w.Extra.Contacts[:len(w.Extra.Contacts)-1]
+			return nil
+		}
+	}
+	return errors.New("contact wasn't found")
+}
+
+// GetContact returns the contact with the specified name or nil if there is
+// no such contact.
+func (w *Wallet) GetContact(name string) *Contact {
+	for _, c := range w.Extra.Contacts {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
 // Path returns the location of the wallet on the filesystem.
 func (w *Wallet) Path() string {
 	return w.path
@@ -148,6 +195,71 @@ func (w *Wallet) Save() error {
 	return json.NewEncoder(w.rw).Encode(w)
 }
 
+// SaveAtomic saves the wallet data to a temporary file in the same directory
+// and then renames it to the wallet path, so the wallet file is never left
+// partially written. It only works for wallets stored in files, internal
+// ReadWriter is reopened to the new file.
+func (w *Wallet) SaveAtomic() error {
+	if w.path == "" {
+		return errors.New("wallet is not stored in a file")
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(w.path), filepath.Base(w.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := json.NewEncoder(tmp).Encode(w); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), w.path); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(w.path, os.O_RDWR, os.ModeAppend)
+	if err != nil {
+		return err
+	}
+	w.Close()
+	w.rw = file
+	return nil
+}
+
+// Reencrypt re-encrypts keys of all wallet accounts decrypting them with the
+// old passphrase and current wallet scrypt parameters and encrypting them
+// with the new passphrase and scrypt parameters that then become the wallet
+// ones. Nothing is changed if any of the keys can't be decrypted.
+func (w *Wallet) Reencrypt(oldPass, newPass string, scrypt keys.ScryptParams) error {
+	wifs := make([]string, len(w.Accounts))
+	for i, acc := range w.Accounts {
+		if acc.EncryptedWIF == "" {
+			continue
+		}
+		priv, err := keys.NEP2DecryptWithParams(acc.EncryptedWIF, oldPass, w.Scrypt)
+		if err != nil {
+			return fmt.Errorf("can't decrypt account %s: %v", acc.Address, err)
+		}
+		wifs[i], err = keys.NEP2EncryptWithParams(priv, newPass, scrypt)
+		if err != nil {
+			return fmt.Errorf("can't encrypt account %s: %v", acc.Address, err)
+		}
+	}
+	for i, acc := range w.Accounts {
+		if wifs[i] != "" {
+			acc.EncryptedWIF = wifs[i]
+		}
+		acc.scrypt = scrypt
+	}
+	w.Scrypt = scrypt
+	return nil
+}
+
 // savePretty saves wallet in a beautiful JSON.
 func (w *Wallet) savePretty() error {
 	if err := w.rewind(); err != nil {
@@ -181,8 +293,19 @@ func (w *Wallet) Close() {
 
 // GetAccount returns account corresponding to the provided scripthash.
 func (w *Wallet) GetAccount(h util.Uint160) *Account {
+	var addr string
 	for _, acc := range w.Accounts {
-		if c := acc.Contract; c != nil && h.Equals(c.ScriptHash()) {
+		if c := acc.Contract; c != nil {
+			if h.Equals(c.ScriptHash()) {
+				return acc
+			}
+			continue
+		}
+		// Watch-only account without a contract.
+		if addr == "" {
+			addr = address.Uint160ToString(h)
+		}
+		if acc.Address == addr {
 			return acc
 		}
 	}
@@ -4,4 +4,8 @@ package wallet
 type Config struct {
 	Path     string `yaml:"Path"`
 	Password string `yaml:"Password"`
+	// RemoteSigner is the path to the Unix socket of a remote signer
+	// process. If it's set, private keys are kept by the signer and Path
+	// and Password are not used.
+	RemoteSigner string `yaml:"RemoteSigner"`
 }
@@ -6,6 +6,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/stretchr/testify/assert"
@@ -109,6 +110,51 @@ func TestSave(t *testing.T) {
 	})
 }
 
+func TestWallet_Reencrypt(t *testing.T) {
+	file, err := ioutil.TempFile("", walletTemplate)
+	require.NoError(t, err)
+	defer removeWallet(t, file.Name())
+	w, err := NewWallet(file.Name())
+	require.NoError(t, err)
+	defer w.Close()
+
+	// Use cheap parameters to speed things up.
+	w.Scrypt = keys.ScryptParams{N: 1024, R: 8, P: 1}
+	require.NoError(t, w.CreateAccount("one", "old"))
+	require.NoError(t, w.CreateAccount("two", "old"))
+	watch, err := NewWatchOnlyAccount("ANg3mmstMr7qtY8TgdKM777WSLKCNFbawM", nil)
+	require.NoError(t, err)
+	w.AddAccount(watch)
+	require.NoError(t, w.Save())
+
+	oldWIF := w.Accounts[0].EncryptedWIF
+	newParams := keys.ScryptParams{N: 2048, R: 8, P: 1}
+	require.Error(t, w.Reencrypt("wrong", "new", newParams))
+	require.Equal(t, oldWIF, w.Accounts[0].EncryptedWIF)
+	require.Equal(t, 1024, w.Scrypt.N)
+
+	require.NoError(t, w.Reencrypt("old", "new", newParams))
+	require.NoError(t, w.SaveAtomic())
+
+	w2, err := NewWalletFromFile(file.Name())
+	require.NoError(t, err)
+	defer w2.Close()
+	require.Equal(t, newParams, w2.Scrypt)
+	require.Equal(t, 3, len(w2.Accounts))
+	require.NoError(t, w2.Accounts[0].Decrypt("new"))
+	require.NoError(t, w2.Accounts[1].Decrypt("new"))
+	require.Error(t, w2.Accounts[1].Decrypt("old"))
+	require.Error(t, w2.Accounts[1].DecryptWithParams("new", keys.NEP2ScryptParams()))
+	require.True(t, w2.Accounts[2].IsWatchOnly())
+
+	// Internal file is reopened after rename.
+	require.NoError(t, w.CreateAccount("three", "new"))
+	w3, err := NewWalletFromFile(file.Name())
+	require.NoError(t, err)
+	defer w3.Close()
+	require.Equal(t, 4, len(w3.Accounts))
+}
+
 func TestJSONMarshallUnmarshal(t *testing.T) {
 	wallet := checkWalletConstructor(t)
 
@@ -151,6 +197,40 @@ func TestWallet_AddToken(t *testing.T) {
 	require.Equal(t, 0, len(w.Extra.Tokens))
 }
 
+func TestWallet_AddContact(t *testing.T) {
+	w := checkWalletConstructor(t)
+	c := NewContact("exchange", util.Uint160{1, 2, 3}, "deposit address")
+	require.NoError(t, w.AddContact(c))
+	require.Error(t, w.AddContact(NewContact("exchange", util.Uint160{4, 5, 6}, "")))
+	require.Equal(t, c, w.GetContact("exchange"))
+	require.Nil(t, w.GetContact("friend"))
+
+	data, err := json.Marshal(w)
+	require.NoError(t, err)
+	w2 := new(Wallet)
+	require.NoError(t, json.Unmarshal(data, w2))
+	require.Equal(t, w.Extra.Contacts, w2.Extra.Contacts)
+
+	require.Error(t, w.RemoveContact("friend"))
+	require.NoError(t, w.RemoveContact("exchange"))
+	require.Equal(t, 0, len(w.Extra.Contacts))
+
+	// Empty address book is omitted.
+	data, err = json.Marshal(w)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "Contacts")
+}
+
+func TestWallet_GetAccountWatchOnly(t *testing.T) {
+	wallet := checkWalletConstructor(t)
+	h := util.Uint160{1, 2, 3}
+	acc, err := NewWatchOnlyAccount(address.Uint160ToString(h), nil)
+	require.NoError(t, err)
+	wallet.AddAccount(acc)
+	require.Equal(t, acc, wallet.GetAccount(h))
+	require.Nil(t, wallet.GetAccount(util.Uint160{3, 2, 1}))
+}
+
 func TestWallet_GetAccount(t *testing.T) {
 	wallet := checkWalletConstructor(t)
 	accounts := []*Account{